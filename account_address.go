@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/crypto"
 	"github.com/0xbe1/aptopher/internal/hex"
 )
 
@@ -60,6 +61,64 @@ func MustParseAccountAddress(s string) AccountAddress {
 	return addr
 }
 
+// AccountAddressFromEd25519Legacy derives the address of an account created
+// with the legacy Ed25519 scheme: SHA3-256(pubKey || 0x00). Accounts created
+// by the Aptos CLI and by Account in this package use this derivation, and
+// sign with the legacy Ed25519 transaction authenticator.
+func AccountAddressFromEd25519Legacy(pubKey []byte) (AccountAddress, error) {
+	if len(pubKey) != crypto.Ed25519PublicKeyLength {
+		return AccountAddress{}, fmt.Errorf("invalid Ed25519 public key length: got %d, want %d", len(pubKey), crypto.Ed25519PublicKeyLength)
+	}
+	return AccountAddress(crypto.AuthenticationKey(pubKey, crypto.Ed25519Scheme)), nil
+}
+
+// AccountAddressFromEd25519SingleKey derives the address of an account created
+// with the single-key scheme for an Ed25519 key:
+// SHA3-256(bcs(AnyPublicKey::Ed25519(pubKey)) || 0x02). Such accounts sign
+// with the single-key authenticator (TransactionAuthenticatorSingleSender
+// wrapping AccountAuthenticatorSingleKey), and their address differs from the
+// legacy derivation for the same key.
+func AccountAddressFromEd25519SingleKey(pubKey []byte) (AccountAddress, error) {
+	if len(pubKey) != crypto.Ed25519PublicKeyLength {
+		return AccountAddress{}, fmt.Errorf("invalid Ed25519 public key length: got %d, want %d", len(pubKey), crypto.Ed25519PublicKeyLength)
+	}
+	return AccountAddress(crypto.SingleKeyAuthenticationKey(uint8(crypto.Ed25519Scheme), pubKey)), nil
+}
+
+// AccountAddressFromMultiEd25519 derives the address of an account created
+// with a legacy MultiEd25519 key: SHA3-256(publicKey.Bytes() || 0x01). Such
+// accounts sign with the MultiEd25519 transaction authenticator.
+func AccountAddressFromMultiEd25519(publicKey *crypto.MultiEd25519PublicKey) AccountAddress {
+	return AccountAddress(publicKey.AuthKey())
+}
+
+// AccountAddressFromMultiKey derives the address of an account created with
+// a k-of-n multi-key (AIP-55): SHA3-256(bcs(MultiKey) || 0x03). Such accounts
+// sign with the multi-key authenticator.
+func AccountAddressFromMultiKey(publicKey *MultiKey) (AccountAddress, error) {
+	if err := publicKey.validate(); err != nil {
+		return AccountAddress{}, err
+	}
+	authKey, err := publicKey.AuthKey()
+	if err != nil {
+		return AccountAddress{}, err
+	}
+	return AccountAddress(authKey), nil
+}
+
+// AccountAddressFromSecp256k1SingleKey derives the address of an account for
+// a secp256k1 key, compressed or uncompressed:
+// SHA3-256(bcs(AnyPublicKey::Secp256k1Ecdsa(uncompressed pubKey)) || 0x02).
+// Secp256k1 keys have no legacy scheme; such accounts always sign with the
+// single-key authenticator.
+func AccountAddressFromSecp256k1SingleKey(pubKey []byte) (AccountAddress, error) {
+	uncompressed, err := crypto.UncompressSecp256k1PubKey(pubKey)
+	if err != nil {
+		return AccountAddress{}, err
+	}
+	return AccountAddress(crypto.SingleKeyAuthenticationKey(uint8(crypto.Secp256k1Scheme), uncompressed)), nil
+}
+
 // String returns the address as a 0x-prefixed hex string.
 // Leading zeros are preserved for the full 32-byte representation.
 func (a AccountAddress) String() string {
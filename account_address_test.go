@@ -1,10 +1,13 @@
 package aptos
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"testing"
 
 	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/crypto"
 )
 
 func TestParseAccountAddress(t *testing.T) {
@@ -113,3 +116,139 @@ func TestWellKnownAddresses(t *testing.T) {
 		t.Errorf("AccountFour = %v", AccountFour.ShortString())
 	}
 }
+
+func TestAccountAddressFromEd25519(t *testing.T) {
+	// Key pair from the Aptos TypeScript SDK test vectors
+	seed, err := hex.DecodeString("c5338cd251c22daa8c9c9cc94f498cc8a5c7e1d2e75287a5dda91096fe64efa5")
+	if err != nil {
+		t.Fatalf("DecodeString error: %v", err)
+	}
+	account, err := AccountFromEd25519Seed(seed)
+	if err != nil {
+		t.Fatalf("AccountFromEd25519Seed error: %v", err)
+	}
+	pubKey := account.Signer.PublicKey()
+	if got := bytesToHex(pubKey); got != "0xde19e5d1880cac87d57484ce9ed2e84cf0f9599f12e7cc3a52e4e7657a763f2c" {
+		t.Fatalf("public key = %s", got)
+	}
+
+	legacy, err := AccountAddressFromEd25519Legacy(pubKey)
+	if err != nil {
+		t.Fatalf("AccountAddressFromEd25519Legacy error: %v", err)
+	}
+	if want := "0x978c213990c4833df71548df7ce49d54c759d6b6d932de22b24d56060b7af2aa"; legacy.String() != want {
+		t.Errorf("legacy address = %s, want %s", legacy, want)
+	}
+	if account.Address != legacy {
+		t.Errorf("Account.Address = %s, want the legacy address %s", account.Address, legacy)
+	}
+
+	// The single-key derivation is pinned against the TypeScript SDK's
+	// secp256k1 vector in TestAccountAddressFromSecp256k1; for Ed25519 only
+	// the AnyPublicKey variant and key length differ.
+	singleKey, err := AccountAddressFromEd25519SingleKey(pubKey)
+	if err != nil {
+		t.Fatalf("AccountAddressFromEd25519SingleKey error: %v", err)
+	}
+	preimage, _ := hex.DecodeString("00" + "20" + "de19e5d1880cac87d57484ce9ed2e84cf0f9599f12e7cc3a52e4e7657a763f2c" + "02")
+	if want := AccountAddress(crypto.Sha3256(preimage)); singleKey != want {
+		t.Errorf("single-key address = %s, want %s", singleKey, want)
+	}
+	if singleKey == legacy {
+		t.Error("single-key and legacy addresses should differ")
+	}
+
+	// Transactions must carry the authenticator the address was derived
+	// from; the node rejects a single-key authenticator for a legacy
+	// address with INVALID_AUTH_KEY.
+	rawTxn, err := NewRawTransaction(account.Address, TransactionPayload{
+		Payload: &EntryFunction{Module: ModuleId{Address: AccountOne, Name: "aptos_account"}, Function: "transfer"},
+	}, 4, WithSequenceNumber(0), WithGasUnitPrice(100), WithExpirationTimestampSecs(1700000000))
+	if err != nil {
+		t.Fatalf("NewRawTransaction error: %v", err)
+	}
+	signedTxn, err := account.SignTransaction(rawTxn)
+	if err != nil {
+		t.Fatalf("SignTransaction error: %v", err)
+	}
+	for name, txn := range map[string]*SignedTransaction{
+		"signed":    signedTxn,
+		"simulated": rawTxn.SimulationTransaction(pubKey, crypto.Ed25519Scheme),
+	} {
+		auth, ok := txn.Authenticator.Auth.(*AccountAuthenticatorEd25519)
+		if txn.Authenticator.Variant != TransactionAuthenticatorEd25519 || !ok {
+			t.Errorf("%s authenticator = %d %T, want the legacy Ed25519 authenticator", name, txn.Authenticator.Variant, txn.Authenticator.Auth)
+			continue
+		}
+		if addr, _ := AccountAddressFromEd25519Legacy(auth.PublicKey[:]); addr != account.Address {
+			t.Errorf("%s authenticator derives %s, want %s", name, addr, account.Address)
+		}
+	}
+
+	if _, err := AccountAddressFromEd25519Legacy(pubKey[:31]); err == nil {
+		t.Error("AccountAddressFromEd25519Legacy should reject a 31-byte key")
+	}
+	if _, err := AccountAddressFromEd25519SingleKey(pubKey[:31]); err == nil {
+		t.Error("AccountAddressFromEd25519SingleKey should reject a 31-byte key")
+	}
+}
+
+func TestAccountAddressFromSecp256k1(t *testing.T) {
+	// Key pair and address from the Aptos TypeScript SDK test vectors
+	keyBytes, err := hex.DecodeString("d107155adf816a0a94c6db3c9489c13ad8a1eda7ada2e558ba3bfa47c020347e")
+	if err != nil {
+		t.Fatalf("DecodeString error: %v", err)
+	}
+	account, err := AccountFromSecp256k1Bytes(keyBytes)
+	if err != nil {
+		t.Fatalf("AccountFromSecp256k1Bytes error: %v", err)
+	}
+	uncompressed, err := crypto.UncompressSecp256k1PubKey(account.Signer.PublicKey())
+	if err != nil {
+		t.Fatalf("UncompressSecp256k1PubKey error: %v", err)
+	}
+	if got := bytesToHex(uncompressed); got != "0x04acdd16651b839c24665b7e2033b55225f384554949fef46c397b5275f37f6ee95554d70fb5d9f93c5831ebf695c7206e7477ce708f03ae9bb2862dc6c9e033ea" {
+		t.Fatalf("public key = %s", got)
+	}
+
+	// SHA3-256(0x01 (AnyPublicKey::Secp256k1Ecdsa) || 0x41 (length) || uncompressed pubKey || 0x02 (SingleKey))
+	const want = "0x5792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498"
+	for _, pubKey := range [][]byte{account.Signer.PublicKey(), uncompressed} {
+		addr, err := AccountAddressFromSecp256k1SingleKey(pubKey)
+		if err != nil {
+			t.Fatalf("AccountAddressFromSecp256k1SingleKey error: %v", err)
+		}
+		if addr.String() != want {
+			t.Errorf("AccountAddressFromSecp256k1SingleKey(%d bytes) = %s, want %s", len(pubKey), addr, want)
+		}
+	}
+	if account.Address.String() != want {
+		t.Errorf("Account.Address = %s, want %s", account.Address, want)
+	}
+
+	// Transactions carry the key the address was derived from
+	rawTxn, err := NewRawTransaction(account.Address, TransactionPayload{
+		Payload: &EntryFunction{Module: ModuleId{Address: AccountOne, Name: "aptos_account"}, Function: "transfer"},
+	}, 4, WithSequenceNumber(0), WithGasUnitPrice(100), WithExpirationTimestampSecs(1700000000))
+	if err != nil {
+		t.Fatalf("NewRawTransaction error: %v", err)
+	}
+	signedTxn, err := account.SignTransaction(rawTxn)
+	if err != nil {
+		t.Fatalf("SignTransaction error: %v", err)
+	}
+	auth, ok := signedTxn.Authenticator.Auth.(*AccountAuthenticatorSingleKey)
+	if signedTxn.Authenticator.Variant != TransactionAuthenticatorSingleSender || !ok {
+		t.Fatalf("authenticator = %+v, want a single-key authenticator", signedTxn.Authenticator)
+	}
+	if auth.PublicKey.Variant != 1 || auth.Signature.Variant != 1 || !bytes.Equal(auth.PublicKey.PublicKey, uncompressed) {
+		t.Errorf("unexpected single-key authenticator: %+v", auth)
+	}
+	if err := auth.validate(); err != nil {
+		t.Errorf("validate error: %v", err)
+	}
+
+	if _, err := AccountAddressFromSecp256k1SingleKey(uncompressed[:64]); err == nil {
+		t.Error("AccountAddressFromSecp256k1SingleKey should reject a 64-byte key")
+	}
+}
@@ -0,0 +1,120 @@
+package aptos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/crypto"
+)
+
+// RotationProofChallenge is the 0x1::account::RotationProofChallenge struct.
+// Both the current and the new key must sign it to prove ownership when
+// rotating an account's authentication key.
+type RotationProofChallenge struct {
+	SequenceNumber uint64
+	Originator     AccountAddress
+	CurrentAuthKey AccountAddress
+	NewPublicKey   []byte
+}
+
+// MarshalBCS implements bcs.Marshaler.
+func (c RotationProofChallenge) MarshalBCS(ser *bcs.Serializer) {
+	ser.U64(c.SequenceNumber)
+	c.Originator.MarshalBCS(ser)
+	c.CurrentAuthKey.MarshalBCS(ser)
+	ser.Bytes(c.NewPublicKey)
+}
+
+// SigningMessage returns the message to be signed for this challenge.
+// This is bcs(TypeInfo) || bcs(RotationProofChallenge), matching Move's
+// signature::signed_message.
+func (c *RotationProofChallenge) SigningMessage() ([]byte, error) {
+	ser := bcs.NewSerializer()
+	// TypeInfo of 0x1::account::RotationProofChallenge
+	AccountOne.MarshalBCS(ser)
+	ser.String("account")
+	ser.String("RotationProofChallenge")
+	c.MarshalBCS(ser)
+	if err := ser.Error(); err != nil {
+		return nil, err
+	}
+	return ser.ToBytes(), nil
+}
+
+// RotateAuthenticationKey rotates the authentication key of account to newKey
+// by calling 0x1::account::rotate_authentication_key and waiting for the
+// transaction to commit.
+//
+// Both keys must be Ed25519 keys. On success, it returns a new Account bound
+// to the original address that signs with newKey.
+func (c *Client) RotateAuthenticationKey(
+	ctx context.Context,
+	account *Account,
+	newKey crypto.PrivateKey,
+	opts ...BuildOption,
+) (*Account, error) {
+	newSigner := newKey.Signer()
+	if account.Signer.Scheme() != crypto.Ed25519Scheme || newSigner.Scheme() != crypto.Ed25519Scheme {
+		return nil, fmt.Errorf("key rotation is only supported for Ed25519 keys")
+	}
+
+	// The challenge commits to the current on-chain state, so never use a pinned version
+	accountData, err := c.GetAccount(withoutLedgerVersion(ctx), account.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account info: %w", err)
+	}
+	currentAuthKey, err := ParseAccountAddress(accountData.Data.AuthenticationKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authentication key: %w", err)
+	}
+
+	challenge := RotationProofChallenge{
+		SequenceNumber: accountData.Data.SequenceNumberUint64(),
+		Originator:     account.Address,
+		CurrentAuthKey: currentAuthKey,
+		NewPublicKey:   newSigner.PublicKey(),
+	}
+	message, err := challenge.SigningMessage()
+	if err != nil {
+		return nil, fmt.Errorf("serialize rotation proof challenge: %w", err)
+	}
+	currentSig, err := account.Signer.Sign(message)
+	if err != nil {
+		return nil, fmt.Errorf("sign rotation proof with current key: %w", err)
+	}
+	newSig, err := newSigner.Sign(message)
+	if err != nil {
+		return nil, fmt.Errorf("sign rotation proof with new key: %w", err)
+	}
+
+	payload := TransactionPayload{
+		Payload: &EntryFunction{
+			Module:   ModuleId{Address: AccountOne, Name: "account"},
+			Function: "rotate_authentication_key",
+			Args: EntryFunctionArgs(
+				U8Arg(uint8(crypto.Ed25519Scheme)),
+				BytesArg(account.Signer.PublicKey()),
+				U8Arg(uint8(crypto.Ed25519Scheme)),
+				BytesArg(newSigner.PublicKey()),
+				BytesArg(currentSig),
+				BytesArg(newSig),
+			),
+		},
+	}
+
+	// The transaction must use the sequence number the challenge was signed for
+	buildOpts := append(append([]BuildOption{}, opts...), WithSequenceNumber(challenge.SequenceNumber))
+	txn, err := c.BuildSignAndSubmitTransaction(ctx, account, payload, buildOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if !txn.Data.Success {
+		return nil, fmt.Errorf("rotate authentication key failed: %s", txn.Data.VMStatus)
+	}
+
+	return &Account{
+		Address: account.Address,
+		Signer:  newSigner,
+	}, nil
+}
@@ -0,0 +1,184 @@
+package aptos
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/crypto"
+)
+
+func TestRotationProofChallengeSigningMessage(t *testing.T) {
+	challenge := RotationProofChallenge{
+		SequenceNumber: 7,
+		Originator:     MustParseAccountAddress("0x123"),
+		CurrentAuthKey: MustParseAccountAddress("0x456"),
+		NewPublicKey:   bytes.Repeat([]byte{0xab}, 32),
+	}
+
+	message, err := challenge.SigningMessage()
+	if err != nil {
+		t.Fatalf("SigningMessage error: %v", err)
+	}
+
+	// Message must start with the BCS-encoded TypeInfo
+	ser := bcs.NewSerializer()
+	AccountOne.MarshalBCS(ser)
+	ser.String("account")
+	ser.String("RotationProofChallenge")
+	typeInfo := ser.ToBytes()
+	if !bytes.HasPrefix(message, typeInfo) {
+		t.Fatalf("message does not start with TypeInfo")
+	}
+
+	challengeBytes, err := bcs.Serialize(&challenge)
+	if err != nil {
+		t.Fatalf("BCS serialize error: %v", err)
+	}
+	if !bytes.Equal(message[len(typeInfo):], challengeBytes) {
+		t.Errorf("message body = %x, want %x", message[len(typeInfo):], challengeBytes)
+	}
+
+	// u64 + address + address + length-prefixed 32-byte key
+	if want := 8 + 32 + 32 + 1 + 32; len(challengeBytes) != want {
+		t.Errorf("challenge length = %d, want %d", len(challengeBytes), want)
+	}
+}
+
+func TestRotationProofChallengeVector(t *testing.T) {
+	// The current key is the Ed25519 fixture of the TypeScript SDK's unit
+	// tests, whose legacy address is 0x978c...f2aa; the new key is derived
+	// from the seed 0x11 * 32.
+	currentKey, _ := hex.DecodeString("de19e5d1880cac87d57484ce9ed2e84cf0f9599f12e7cc3a52e4e7657a763f2c")
+	newKey, _ := hex.DecodeString("d04ab232742bb4ab3a1368bd4615e4e6d0224ab71a016baf8520a332c9778737")
+	address, err := AccountAddressFromEd25519Legacy(currentKey)
+	if err != nil {
+		t.Fatalf("AccountAddressFromEd25519Legacy error: %v", err)
+	}
+	if want := "0x978c213990c4833df71548df7ce49d54c759d6b6d932de22b24d56060b7af2aa"; address.String() != want {
+		t.Fatalf("address = %s, want %s", address, want)
+	}
+
+	challenge := RotationProofChallenge{
+		SequenceNumber: 7,
+		Originator:     address,
+		CurrentAuthKey: address,
+		NewPublicKey:   newKey,
+	}
+	message, err := challenge.SigningMessage()
+	if err != nil {
+		t.Fatalf("SigningMessage error: %v", err)
+	}
+
+	// Field by field, in the order of the TypeScript SDK's
+	// RotationProofChallenge.serialize and the Move struct
+	want := strings.Join([]string{
+		"0000000000000000000000000000000000000000000000000000000000000001", // TypeInfo.account_address
+		"07" + "6163636f756e74",                                                   // TypeInfo.module_name "account"
+		"16" + "526f746174696f6e50726f6f664368616c6c656e6765",                     // TypeInfo.struct_name "RotationProofChallenge"
+		"0700000000000000",                                                        // sequence_number
+		"978c213990c4833df71548df7ce49d54c759d6b6d932de22b24d56060b7af2aa",        // originator
+		"978c213990c4833df71548df7ce49d54c759d6b6d932de22b24d56060b7af2aa",        // current_auth_key
+		"20" + "d04ab232742bb4ab3a1368bd4615e4e6d0224ab71a016baf8520a332c9778737", // new_public_key
+	}, "")
+	if got := hex.EncodeToString(message); got != want {
+		t.Errorf("SigningMessage =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRotateAuthenticationKey(t *testing.T) {
+	account, err := AccountFromEd25519Seed(bytes.Repeat([]byte{0x22}, 32))
+	if err != nil {
+		t.Fatalf("AccountFromEd25519Seed error: %v", err)
+	}
+	newKey, err := crypto.NewEd25519PrivateKey(bytes.Repeat([]byte{0x11}, 32))
+	if err != nil {
+		t.Fatalf("NewEd25519PrivateKey error: %v", err)
+	}
+	authKey := account.AuthKey()
+	currentAuthKey := AccountAddress(authKey)
+
+	var submitted *SignedTransaction
+	client, closeServer := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Aptos-Chain-Id", "4")
+		switch {
+		case r.URL.Path == "/accounts/"+account.Address.String():
+			fmt.Fprintf(w, `{"sequence_number":"7","authentication_key":"%s"}`, currentAuthKey)
+		case r.URL.Path == "/-/healthy":
+		case r.URL.Path == "/estimate_gas_price":
+			w.Write([]byte(`{"gas_estimate":100}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/transactions":
+			body, _ := io.ReadAll(r.Body)
+			submitted = &SignedTransaction{}
+			if err := bcs.Deserialize(body, submitted); err != nil {
+				t.Errorf("decode submitted transaction: %v", err)
+			}
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"hash":"0x1"}`))
+		case r.URL.Path == "/transactions/wait_by_hash/0x1":
+			w.Write([]byte(`{"type":"user_transaction","hash":"0x1","success":true,"vm_status":"Executed successfully"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+	defer closeServer()
+
+	rotated, err := client.RotateAuthenticationKey(context.Background(), account, newKey)
+	if err != nil {
+		t.Fatalf("RotateAuthenticationKey error: %v", err)
+	}
+	if rotated.Address != account.Address || !bytes.Equal(rotated.Signer.PublicKey(), newKey.PublicKey()) {
+		t.Errorf("rotated account = %s with key %x, want %s with the new key", rotated.Address, rotated.Signer.PublicKey(), account.Address)
+	}
+	if submitted == nil {
+		t.Fatal("no transaction was submitted")
+	}
+	if submitted.RawTxn.SequenceNumber != 7 {
+		t.Errorf("sequence number = %d, want the challenge's 7", submitted.RawTxn.SequenceNumber)
+	}
+
+	ef, ok := submitted.RawTxn.Payload.Payload.(*EntryFunction)
+	if !ok || ef.Module != (ModuleId{Address: AccountOne, Name: "account"}) || ef.Function != "rotate_authentication_key" {
+		t.Fatalf("payload = %+v, want 0x1::account::rotate_authentication_key", submitted.RawTxn.Payload.Payload)
+	}
+	// rotate_authentication_key(account, from_scheme, from_public_key_bytes,
+	// to_scheme, to_public_key_bytes, cap_rotate_key, cap_update_table)
+	args, err := DecodeEntryFunctionArgs(ef.Args, mustParseTypeTags(t, "u8", "vector<u8>", "u8", "vector<u8>", "vector<u8>", "vector<u8>"))
+	if err != nil {
+		t.Fatalf("DecodeEntryFunctionArgs error: %v", err)
+	}
+	if args[0] != uint8(0) || args[2] != uint8(0) {
+		t.Errorf("schemes = %v, %v; want 0 (Ed25519) for both", args[0], args[2])
+	}
+	if !bytes.Equal(args[1].([]byte), account.Signer.PublicKey()) {
+		t.Errorf("from_public_key_bytes = %x, want the current key", args[1])
+	}
+	if !bytes.Equal(args[3].([]byte), newKey.PublicKey()) {
+		t.Errorf("to_public_key_bytes = %x, want the new key", args[3])
+	}
+
+	challenge := RotationProofChallenge{
+		SequenceNumber: 7,
+		Originator:     account.Address,
+		CurrentAuthKey: currentAuthKey,
+		NewPublicKey:   newKey.PublicKey(),
+	}
+	message, err := challenge.SigningMessage()
+	if err != nil {
+		t.Fatalf("SigningMessage error: %v", err)
+	}
+	if !crypto.VerifyEd25519(account.Signer.PublicKey(), message, args[4].([]byte)) {
+		t.Error("cap_rotate_key is not the current key's signature of the challenge")
+	}
+	if !crypto.VerifyEd25519(newKey.PublicKey(), message, args[5].([]byte)) {
+		t.Error("cap_update_table is not the new key's signature of the challenge")
+	}
+}
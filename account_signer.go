@@ -1,6 +1,10 @@
 package aptos
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/0xbe1/aptopher/crypto"
 )
 
@@ -29,6 +33,11 @@ func NewSecp256k1Account() (*Account, error) {
 }
 
 // AccountFromPrivateKey creates an account from a private key.
+// The address is the signer's authentication key; for Ed25519 keys this is the
+// legacy derivation (see AccountAddressFromEd25519Legacy), and transactions
+// are signed with the matching legacy Ed25519 authenticator. Secp256k1 keys
+// use the single-key derivation (see AccountAddressFromSecp256k1SingleKey) and
+// authenticator.
 func AccountFromPrivateKey(privKey crypto.PrivateKey) (*Account, error) {
 	signer := privKey.Signer()
 	authKey := signer.AuthKey()
@@ -51,6 +60,34 @@ func AccountFromEd25519Seed(seed []byte) (*Account, error) {
 	return AccountFromPrivateKey(privKey)
 }
 
+// DefaultDerivationPath is the BIP44 path of the first Aptos account of a
+// mnemonic, as used by the Petra wallet and the Aptos CLI. Later accounts
+// increment the account index: "m/44'/637'/1'/0'/0'", and so on.
+const DefaultDerivationPath = "m/44'/637'/0'/0'/0'"
+
+// AccountFromMnemonic creates an Ed25519 account from a BIP39 mnemonic and a
+// derivation path, or DefaultDerivationPath if the path is empty. The key is
+// derived from the mnemonic's seed, without a passphrase, with SLIP-0010, so
+// every step of the path must be hardened. The mnemonic must use the English
+// wordlist and have a valid checksum.
+func AccountFromMnemonic(mnemonic, derivationPath string) (*Account, error) {
+	if derivationPath == "" {
+		derivationPath = DefaultDerivationPath
+	}
+	if err := crypto.ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	mnemonicSeed, err := crypto.MnemonicToSeed(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	seed, err := crypto.DeriveEd25519Seed(mnemonicSeed, derivationPath)
+	if err != nil {
+		return nil, err
+	}
+	return AccountFromEd25519Seed(seed)
+}
+
 // AccountFromSecp256k1Bytes creates an account from a 32-byte secp256k1 private key.
 func AccountFromSecp256k1Bytes(keyBytes []byte) (*Account, error) {
 	privKey, err := crypto.NewSecp256k1PrivateKey(keyBytes)
@@ -65,6 +102,19 @@ func (a *Account) Sign(message []byte) ([]byte, error) {
 	return a.Signer.Sign(message)
 }
 
+// SignMessage signs an off-chain message, such as a login challenge. The
+// signature is over SHA3-256(crypto.MessageHashPrefix || message), which can
+// never be a transaction's signing message, so the signature cannot be
+// replayed as a transaction. Verify it with VerifyMessage.
+func (a *Account) SignMessage(message []byte) ([]byte, error) {
+	return a.Signer.Sign(messageSigningBytes(message))
+}
+
+// messageSigningBytes returns the bytes SignMessage signs for message.
+func messageSigningBytes(message []byte) []byte {
+	return crypto.HashWithPrefix(crypto.MessageHashPrefix, message)
+}
+
 // SignTransaction signs a raw transaction.
 func (a *Account) SignTransaction(rawTxn *RawTransaction) (*SignedTransaction, error) {
 	return rawTxn.Sign(a.Signer)
@@ -74,3 +124,107 @@ func (a *Account) SignTransaction(rawTxn *RawTransaction) (*SignedTransaction, e
 func (a *Account) AuthKey() [32]byte {
 	return a.Signer.AuthKey()
 }
+
+// VerifySignedMessage verifies a signature over message and that the public
+// key belongs to the claimed address, as needed for sign-in flows where the
+// wallet supplies the address, public key, and signature. Checking only the
+// signature would accept a valid signature from any key.
+//
+// The key must derive to the address: for Ed25519 with either the legacy or
+// the single-key scheme, for secp256k1 with the single-key scheme. Accounts
+// whose key was rotated no longer match their address; for those, compare the
+// key's authentication key with the one returned by GetAccount instead.
+//
+// It returns false if the signature is invalid or the key does not match the
+// address, and an error if the scheme is unsupported or the key is malformed.
+func VerifySignedMessage(address AccountAddress, pubKey, message, signature []byte, scheme crypto.SignatureScheme) (bool, error) {
+	var matches bool
+	switch scheme {
+	case crypto.Ed25519Scheme:
+		legacy, err := AccountAddressFromEd25519Legacy(pubKey)
+		if err != nil {
+			return false, err
+		}
+		singleKey, err := AccountAddressFromEd25519SingleKey(pubKey)
+		if err != nil {
+			return false, err
+		}
+		matches = address == legacy || address == singleKey
+	case crypto.Secp256k1Scheme:
+		singleKey, err := AccountAddressFromSecp256k1SingleKey(pubKey)
+		if err != nil {
+			return false, err
+		}
+		matches = address == singleKey
+	default:
+		return false, fmt.Errorf("unsupported signature scheme %d", scheme)
+	}
+	if !matches {
+		return false, nil
+	}
+
+	if scheme == crypto.Ed25519Scheme {
+		return crypto.VerifyEd25519(pubKey, message, signature), nil
+	}
+	return crypto.VerifySecp256k1(pubKey, message, signature), nil
+}
+
+// VerifyMessage verifies a signature made with Account.SignMessage, and that
+// the public key belongs to the claimed address, as VerifySignedMessage does
+// for signatures over the raw message.
+func VerifyMessage(address AccountAddress, pubKey, message, signature []byte, scheme crypto.SignatureScheme) (bool, error) {
+	return VerifySignedMessage(address, pubKey, messageSigningBytes(message), signature, scheme)
+}
+
+// WalletMessage holds the fields of a message signed through the Aptos wallet
+// standard's signMessage. Optional fields are omitted from the signed message
+// when unset, as they are when the dapp does not request them.
+type WalletMessage struct {
+	// Address is the signing account, included if requested by the dapp.
+	Address *AccountAddress
+
+	// Application is the dapp's origin, such as "https://example.com",
+	// included if requested by the dapp.
+	Application string
+
+	// ChainID is the wallet's network, included if requested by the dapp.
+	ChainID *uint8
+
+	Message string
+	Nonce   string
+}
+
+// WalletMessageBytes returns the full message a wallet signs for m:
+//
+//	APTOS
+//	address: <address>
+//	application: <application>
+//	chainId: <chain ID>
+//	message: <message>
+//	nonce: <nonce>
+//
+// with the address, application, and chain ID lines present only when set.
+// Wallets sign these UTF-8 bytes directly, without hashing or a prefix.
+func WalletMessageBytes(m WalletMessage) []byte {
+	var b strings.Builder
+	b.WriteString("APTOS")
+	if m.Address != nil {
+		b.WriteString("\naddress: " + m.Address.String())
+	}
+	if m.Application != "" {
+		b.WriteString("\napplication: " + m.Application)
+	}
+	if m.ChainID != nil {
+		b.WriteString("\nchainId: " + strconv.Itoa(int(*m.ChainID)))
+	}
+	b.WriteString("\nmessage: " + m.Message)
+	b.WriteString("\nnonce: " + m.Nonce)
+	return []byte(b.String())
+}
+
+// VerifyWalletMessage verifies a signature returned by a wallet's
+// signMessage, rebuilding the signed bytes from m with WalletMessageBytes.
+// Like VerifySignedMessage, it also checks that the key belongs to address.
+func VerifyWalletMessage(address AccountAddress, pubKey []byte, m WalletMessage, signature []byte, scheme crypto.SignatureScheme) (bool, error) {
+	return VerifySignedMessage(address, pubKey, WalletMessageBytes(m), signature, scheme)
+}
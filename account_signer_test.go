@@ -0,0 +1,289 @@
+package aptos
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/0xbe1/aptopher/crypto"
+)
+
+func TestVerifySignedMessage(t *testing.T) {
+	message := []byte("Sign in to example.com\nNonce: 42")
+
+	ed, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+	edSig, err := ed.Sign(message)
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	edSingleKey, err := AccountAddressFromEd25519SingleKey(ed.Signer.PublicKey())
+	if err != nil {
+		t.Fatalf("AccountAddressFromEd25519SingleKey error: %v", err)
+	}
+
+	secp, err := NewSecp256k1Account()
+	if err != nil {
+		t.Fatalf("NewSecp256k1Account error: %v", err)
+	}
+	secpSig, err := secp.Sign(message)
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+
+	other, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+	otherSig, err := other.Sign(message)
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+
+	mustHex := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatalf("bad hex %q: %v", s, err)
+		}
+		return b
+	}
+	// Key pairs, addresses and signatures of "hello world" from the Aptos
+	// TypeScript SDK test vectors
+	helloWorld := []byte("hello world")
+	tsEd25519Address := MustParseAccountAddress("0x978c213990c4833df71548df7ce49d54c759d6b6d932de22b24d56060b7af2aa")
+	tsEd25519Key := mustHex("de19e5d1880cac87d57484ce9ed2e84cf0f9599f12e7cc3a52e4e7657a763f2c")
+	tsEd25519Sig := mustHex("9e653d56a09247570bb174a389e85b9226abd5c403ea6c504b386626a145158cd4efd66fc5e071c0e19538a96a05ddbda24d3c51e1e6a9dacc6bb1ce775cce07")
+	tsSecp256k1Address := MustParseAccountAddress("0x5792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498")
+	tsSecp256k1Key := mustHex("04acdd16651b839c24665b7e2033b55225f384554949fef46c397b5275f37f6ee95554d70fb5d9f93c5831ebf695c7206e7477ce708f03ae9bb2862dc6c9e033ea")
+	tsSecp256k1Sig := mustHex("d0d634e843b61339473b028105930ace022980708b2855954b977da09df84a770c0b68c29c8ca1b5409a5085b0ec263be80e433c83fcf6debb82f3447e71edca")
+
+	tests := []struct {
+		name      string
+		address   AccountAddress
+		pubKey    []byte
+		message   []byte
+		signature []byte
+		scheme    crypto.SignatureScheme
+		want      bool
+		wantErr   bool
+	}{
+		{"ed25519 sdk vector", tsEd25519Address, tsEd25519Key, helloWorld, tsEd25519Sig, crypto.Ed25519Scheme, true, false},
+		{"secp256k1 sdk vector", tsSecp256k1Address, tsSecp256k1Key, helloWorld, tsSecp256k1Sig, crypto.Secp256k1Scheme, true, false},
+		{"secp256k1 sdk vector with another address", tsEd25519Address, tsSecp256k1Key, helloWorld, tsSecp256k1Sig, crypto.Secp256k1Scheme, false, false},
+		{"secp256k1 sdk vector with another message", tsSecp256k1Address, tsSecp256k1Key, message, tsSecp256k1Sig, crypto.Secp256k1Scheme, false, false},
+		{"ed25519 legacy address", ed.Address, ed.Signer.PublicKey(), message, edSig, crypto.Ed25519Scheme, true, false},
+		{"ed25519 single-key address", edSingleKey, ed.Signer.PublicKey(), message, edSig, crypto.Ed25519Scheme, true, false},
+		{"secp256k1", secp.Address, secp.Signer.PublicKey(), message, secpSig, crypto.Secp256k1Scheme, true, false},
+		{"valid signature from another key", ed.Address, other.Signer.PublicKey(), message, otherSig, crypto.Ed25519Scheme, false, false},
+		{"tampered message", ed.Address, ed.Signer.PublicKey(), []byte("other"), edSig, crypto.Ed25519Scheme, false, false},
+		{"malformed key", ed.Address, []byte{1, 2, 3}, message, edSig, crypto.Ed25519Scheme, false, true},
+		{"unsupported scheme", ed.Address, ed.Signer.PublicKey(), message, edSig, crypto.SignatureScheme(9), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifySignedMessage(tt.address, tt.pubKey, tt.message, tt.signature, tt.scheme)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifySignedMessage error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("VerifySignedMessage = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignMessage(t *testing.T) {
+	if bytes.Equal(crypto.MessageHashPrefix, crypto.RawTransactionHashPrefix) ||
+		bytes.Equal(crypto.MessageHashPrefix, crypto.RawTransactionWithDataHashPrefix) {
+		t.Fatal("MessageHashPrefix must differ from the transaction prefixes")
+	}
+
+	account, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+	rawTxn, err := NewRawTransaction(account.Address, TransactionPayload{
+		Payload: &EntryFunction{Module: ModuleId{Address: AccountOne, Name: "aptos_account"}, Function: "transfer"},
+	}, 4, WithSequenceNumber(0), WithGasUnitPrice(100), WithExpirationTimestampSecs(1700000000))
+	if err != nil {
+		t.Fatalf("NewRawTransaction error: %v", err)
+	}
+	message, err := rawTxn.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes error: %v", err)
+	}
+
+	sig, err := account.SignMessage(message)
+	if err != nil {
+		t.Fatalf("SignMessage error: %v", err)
+	}
+	pubKey := account.Signer.PublicKey()
+	if !crypto.VerifyEd25519(pubKey, crypto.HashWithPrefix(crypto.MessageHashPrefix, message), sig) {
+		t.Error("SignMessage signature is not over the prefixed message hash")
+	}
+
+	// The same bytes signed as a transaction give a different signature,
+	// which does not verify as a message
+	signingMessage, err := rawTxn.SigningMessage()
+	if err != nil {
+		t.Fatalf("SigningMessage error: %v", err)
+	}
+	txnSig, err := account.Sign(signingMessage)
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	if bytes.Equal(sig, txnSig) {
+		t.Error("message and transaction signatures over the same bytes are equal")
+	}
+	if crypto.VerifyEd25519(pubKey, signingMessage, sig) {
+		t.Error("SignMessage signature verifies as a transaction signature")
+	}
+
+	tests := []struct {
+		name      string
+		signature []byte
+		want      bool
+	}{
+		{"message signature", sig, true},
+		{"transaction signature", txnSig, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyMessage(account.Address, pubKey, message, tt.signature, crypto.Ed25519Scheme)
+			if err != nil {
+				t.Fatalf("VerifyMessage error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyMessage = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalletMessageBytes(t *testing.T) {
+	address := MustParseAccountAddress("0x5e1f")
+	chainID := uint8(1)
+
+	// want is the fullMessage the wallet adapter returns from signMessage for
+	// the same request: "APTOS", then the address, application and chainId
+	// lines when the dapp sets those flags, then the message and nonce.
+	tests := []struct {
+		name string
+		msg  WalletMessage
+		want string
+	}{
+		{
+			// The signMessage example in the Petra wallet docs
+			name: "no optional fields",
+			msg:  WalletMessage{Message: "hello", Nonce: "random_string"},
+			want: "APTOS\nmessage: hello\nnonce: random_string",
+		},
+		{
+			name: "address only",
+			msg:  WalletMessage{Address: &address, Message: "hello", Nonce: "random_string"},
+			want: "APTOS\naddress: 0x0000000000000000000000000000000000000000000000000000000000005e1f\nmessage: hello\nnonce: random_string",
+		},
+		{
+			name: "application only",
+			msg:  WalletMessage{Application: "https://aptos.dev", Message: "hello", Nonce: "random_string"},
+			want: "APTOS\napplication: https://aptos.dev\nmessage: hello\nnonce: random_string",
+		},
+		{
+			name: "address and application",
+			msg:  WalletMessage{Address: &address, Application: "https://aptos.dev", Message: "hello", Nonce: "random_string"},
+			want: "APTOS\naddress: 0x0000000000000000000000000000000000000000000000000000000000005e1f\napplication: https://aptos.dev\nmessage: hello\nnonce: random_string",
+		},
+		{
+			name: "all fields",
+			msg: WalletMessage{
+				Address:     &address,
+				Application: "https://example.com",
+				ChainID:     &chainID,
+				Message:     "Sign in to example.com",
+				Nonce:       "1234",
+			},
+			want: "APTOS\naddress: 0x0000000000000000000000000000000000000000000000000000000000005e1f\napplication: https://example.com\nchainId: 1\nmessage: Sign in to example.com\nnonce: 1234",
+		},
+		{
+			name: "chain ID only",
+			msg:  WalletMessage{ChainID: &chainID, Message: "multi\nline", Nonce: "n"},
+			want: "APTOS\nchainId: 1\nmessage: multi\nline\nnonce: n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(WalletMessageBytes(tt.msg)); got != tt.want {
+				t.Errorf("WalletMessageBytes = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyWalletMessage(t *testing.T) {
+	account, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+	msg := WalletMessage{Address: &account.Address, Application: "https://example.com", Message: "Sign in", Nonce: "42"}
+	signature, err := account.Sign(WalletMessageBytes(msg))
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+
+	ok, err := VerifyWalletMessage(account.Address, account.Signer.PublicKey(), msg, signature, crypto.Ed25519Scheme)
+	if err != nil || !ok {
+		t.Errorf("VerifyWalletMessage = %v, %v, want true", ok, err)
+	}
+
+	// Dropping an optional field changes the signed bytes.
+	msg.Application = ""
+	ok, err = VerifyWalletMessage(account.Address, account.Signer.PublicKey(), msg, signature, crypto.Ed25519Scheme)
+	if err != nil || ok {
+		t.Errorf("VerifyWalletMessage without application = %v, %v, want false", ok, err)
+	}
+}
+
+func TestAccountFromMnemonic(t *testing.T) {
+	// Vector from the Aptos TypeScript SDK, matching the Petra wallet
+	mnemonic := "shoot island position soft burden budget tooth cruel issue economy destroy above"
+	want := MustParseAccountAddress("0x07968dab936c1bad187c60ce4082f307d030d780e91e694ae03aef16aba73f30")
+	for _, path := range []string{"", DefaultDerivationPath} {
+		account, err := AccountFromMnemonic(mnemonic, path)
+		if err != nil {
+			t.Fatalf("AccountFromMnemonic(%q) error: %v", path, err)
+		}
+		if account.Address != want {
+			t.Errorf("AccountFromMnemonic(%q) address = %s, want %s", path, account.Address, want)
+		}
+		if got := bytesToHex(account.Signer.PublicKey()); got != "0xea526ba1710343d953461ff68641f1b7df5f23b9042ffa2d2a798d3adb3f3d6c" {
+			t.Errorf("public key = %s", got)
+		}
+	}
+
+	second, err := AccountFromMnemonic(mnemonic, "m/44'/637'/1'/0'/0'")
+	if err != nil || second.Address == want {
+		t.Errorf("second account = %v, %v, want a different address", second, err)
+	}
+
+	tests := []struct {
+		name     string
+		mnemonic string
+		path     string
+		wantErr  string
+	}{
+		{"bad checksum", strings.Replace(mnemonic, "above", "abandon", 1), "", "checksum"},
+		{"unhardened path", mnemonic, "m/44'/637'/0'/0/0", "not hardened"},
+		{"malformed path", mnemonic, "44'/637'/0'/0'/0'", "must start with m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AccountFromMnemonic(tt.mnemonic, tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("AccountFromMnemonic error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ansSuffix is the top-level domain of Aptos Name Service names.
+const ansSuffix = ".apt"
+
+// ansOption is the JSON form of a Move Option<T> in view arguments and results.
+type ansOption[T any] struct {
+	Vec []T `json:"vec"`
+}
+
+// parseANSName splits an ANS name such as "alice.apt" or "bob.alice.apt" into
+// its domain and optional subdomain. The ".apt" suffix may be omitted.
+func parseANSName(name string) (domain, subdomain string, err error) {
+	trimmed := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ansSuffix)
+	labels := strings.Split(trimmed, ".")
+	for _, label := range labels {
+		if label == "" {
+			return "", "", fmt.Errorf("invalid name %q", name)
+		}
+	}
+	switch len(labels) {
+	case 1:
+		return labels[0], "", nil
+	case 2:
+		return labels[1], labels[0], nil
+	default:
+		return "", "", fmt.Errorf("invalid name %q: too many labels", name)
+	}
+}
+
+// ResolveName resolves an Aptos Name Service name, such as "alice.apt" or
+// "bob.alice.apt", to its target address using the router's get_target_addr
+// view function. The ".apt" suffix may be omitted. Returns ErrNameNotFound if
+// the name is not registered, has expired, or has no target address.
+// The router address comes from ClientConfig.ANSAddress.
+func (c *Client) ResolveName(ctx context.Context, name string, opts ...RequestOption) (AccountAddress, error) {
+	if c.ansAddress.IsZero() {
+		return AccountAddress{}, errors.New("resolve name: ANS address not configured")
+	}
+	domain, subdomain, err := parseANSName(name)
+	if err != nil {
+		return AccountAddress{}, fmt.Errorf("resolve name: %w", err)
+	}
+
+	sub := ansOption[string]{Vec: []string{}}
+	if subdomain != "" {
+		sub.Vec = append(sub.Vec, subdomain)
+	}
+	result, err := c.View(ctx, ViewRequest{
+		Function:  c.ansAddress.String() + "::router::get_target_addr",
+		Arguments: []interface{}{domain, sub},
+	}, opts...)
+	if err != nil {
+		return AccountAddress{}, err
+	}
+
+	if len(result.Data) != 1 {
+		return AccountAddress{}, fmt.Errorf("resolve name: got %d view results, want 1", len(result.Data))
+	}
+	var target ansOption[AccountAddress]
+	if err := json.Unmarshal(result.Data[0], &target); err != nil {
+		return AccountAddress{}, fmt.Errorf("resolve name: decode target address: %w", err)
+	}
+	if len(target.Vec) == 0 {
+		return AccountAddress{}, fmt.Errorf("resolve name %s: %w", name, ErrNameNotFound)
+	}
+	return target.Vec[0], nil
+}
+
+// ReverseLookup returns the primary Aptos Name Service name of an address,
+// such as "alice.apt", using the router's get_primary_name view function.
+// Returns ErrNameNotFound if the address has no primary name.
+// The router address comes from ClientConfig.ANSAddress.
+func (c *Client) ReverseLookup(ctx context.Context, address AccountAddress, opts ...RequestOption) (string, error) {
+	if c.ansAddress.IsZero() {
+		return "", errors.New("reverse lookup: ANS address not configured")
+	}
+	result, err := c.View(ctx, ViewRequest{
+		Function:  c.ansAddress.String() + "::router::get_primary_name",
+		Arguments: []interface{}{address},
+	}, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	// The result is (subdomain: Option<String>, domain: Option<String>).
+	if len(result.Data) != 2 {
+		return "", fmt.Errorf("reverse lookup: got %d view results, want 2", len(result.Data))
+	}
+	var subdomain, domain ansOption[string]
+	if err := json.Unmarshal(result.Data[0], &subdomain); err != nil {
+		return "", fmt.Errorf("reverse lookup: decode subdomain: %w", err)
+	}
+	if err := json.Unmarshal(result.Data[1], &domain); err != nil {
+		return "", fmt.Errorf("reverse lookup: decode domain: %w", err)
+	}
+	if len(domain.Vec) == 0 {
+		return "", fmt.Errorf("reverse lookup %s: %w", address, ErrNameNotFound)
+	}
+
+	name := domain.Vec[0] + ansSuffix
+	if len(subdomain.Vec) > 0 {
+		name = subdomain.Vec[0] + "." + name
+	}
+	return name, nil
+}
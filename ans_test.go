@@ -0,0 +1,107 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseANSName(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantDomain    string
+		wantSubdomain string
+		wantErr       bool
+	}{
+		{"alice.apt", "alice", "", false},
+		{"alice", "alice", "", false},
+		{" Bob.Alice.APT ", "alice", "bob", false},
+		{"", "", "", true},
+		{"alice..apt", "", "", true},
+		{"a.b.c.apt", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domain, subdomain, err := parseANSName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseANSName error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if domain != tt.wantDomain || subdomain != tt.wantSubdomain {
+				t.Errorf("parseANSName = (%q, %q), want (%q, %q)", domain, subdomain, tt.wantDomain, tt.wantSubdomain)
+			}
+		})
+	}
+}
+
+func TestANS(t *testing.T) {
+	router := MustParseAccountAddress("0xa11ce")
+	alice := MustParseAccountAddress("0xa")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Function  string            `json:"function"`
+			Arguments []json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch req.Function {
+		case router.String() + "::router::get_target_addr":
+			if string(req.Arguments[0]) == `"alice"` && string(req.Arguments[1]) == `{"vec":["bob"]}` {
+				w.Write([]byte(`[{"vec":["` + alice.String() + `"]}]`))
+				return
+			}
+			w.Write([]byte(`[{"vec":[]}]`))
+		case router.String() + "::router::get_primary_name":
+			if string(req.Arguments[0]) == `"`+alice.String()+`"` {
+				w.Write([]byte(`[{"vec":["bob"]},{"vec":["alice"]}]`))
+				return
+			}
+			w.Write([]byte(`[{"vec":[]},{"vec":[]}]`))
+		default:
+			t.Errorf("unexpected function %s", req.Function)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL, ANSAddress: router})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+
+	got, err := client.ResolveName(ctx, "bob.alice.apt")
+	if err != nil {
+		t.Fatalf("ResolveName error: %v", err)
+	}
+	if got != alice {
+		t.Errorf("ResolveName = %v, want %v", got, alice)
+	}
+	if _, err := client.ResolveName(ctx, "nobody.apt"); !errors.Is(err, ErrNameNotFound) {
+		t.Errorf("ResolveName error = %v, want ErrNameNotFound", err)
+	}
+
+	name, err := client.ReverseLookup(ctx, alice)
+	if err != nil {
+		t.Fatalf("ReverseLookup error: %v", err)
+	}
+	if name != "bob.alice.apt" {
+		t.Errorf("ReverseLookup = %q, want %q", name, "bob.alice.apt")
+	}
+	if _, err := client.ReverseLookup(ctx, AccountOne); !errors.Is(err, ErrNameNotFound) {
+		t.Errorf("ReverseLookup error = %v, want ErrNameNotFound", err)
+	}
+
+	unconfigured, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	if _, err := unconfigured.ResolveName(ctx, "alice.apt"); err == nil {
+		t.Error("ResolveName should fail without an ANS address")
+	}
+}
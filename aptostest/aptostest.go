@@ -0,0 +1,148 @@
+// Package aptostest provides helpers for testing Move contracts from Go by
+// simulating transactions and asserting on their outcome and events.
+//
+//	txn := aptostest.Simulate(t, ctx, client, account, payload)
+//	aptostest.AssertSuccess(t, txn)
+//	aptostest.AssertEmitsEvent(t, txn, "0x1::coin::CoinDeposit",
+//	    aptostest.EventField("amount", "1000"))
+package aptostest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	aptos "github.com/0xbe1/aptopher"
+)
+
+// EventMatcher reports whether an event matches a condition.
+type EventMatcher func(event aptos.Event) bool
+
+// EventField matches events whose data has a top-level field with the given
+// value. String fields are compared by their value, and all other fields by
+// their JSON encoding (for example "true" or "{\"inner\":\"0x1\"}").
+func EventField(name, want string) EventMatcher {
+	return func(event aptos.Event) bool {
+		var data map[string]json.RawMessage
+		if err := event.DecodeData(&data); err != nil {
+			return false
+		}
+		raw, ok := data[name]
+		if !ok {
+			return false
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			return s == want
+		}
+		return string(raw) == want
+	}
+}
+
+// Simulate simulates the payload as the account and returns the simulated
+// transaction. The test fails immediately if the simulation request fails.
+func Simulate(
+	t testing.TB,
+	ctx context.Context,
+	client *aptos.Client,
+	account *aptos.Account,
+	payload aptos.TransactionPayload,
+	opts ...aptos.BuildOption,
+) aptos.UserTransaction {
+	t.Helper()
+	result, err := client.SimulatePayload(ctx, account, payload, opts...)
+	if err != nil {
+		t.Fatalf("simulate transaction: %v", err)
+	}
+	if len(result.Data) == 0 {
+		t.Fatalf("simulate transaction: empty result")
+	}
+	return result.Data[0]
+}
+
+// AssertSuccess fails the test if the transaction did not execute successfully.
+func AssertSuccess(t testing.TB, txn aptos.UserTransaction) {
+	t.Helper()
+	if !txn.Success {
+		t.Errorf("transaction failed: %s", txn.VMStatus)
+	}
+}
+
+// AssertFailure fails the test if the transaction executed successfully, or
+// if its VM status does not contain wantStatus (when non-empty).
+func AssertFailure(t testing.TB, txn aptos.UserTransaction, wantStatus string) {
+	t.Helper()
+	if txn.Success {
+		t.Errorf("transaction succeeded, want failure")
+		return
+	}
+	if wantStatus != "" && !strings.Contains(txn.VMStatus, wantStatus) {
+		t.Errorf("VM status = %q, want it to contain %q", txn.VMStatus, wantStatus)
+	}
+}
+
+// FindEvents returns the events of the given type that satisfy all matchers.
+// Type addresses are compared in normalized form, so "0x1::coin::CoinDeposit"
+// matches "0x0000...0001::coin::CoinDeposit".
+func FindEvents(txn aptos.UserTransaction, eventType string, matchers ...EventMatcher) []aptos.Event {
+	want := normalizeType(eventType)
+	var found []aptos.Event
+	for _, event := range txn.Events {
+		if normalizeType(event.Type) != want {
+			continue
+		}
+		if matchesAll(event, matchers) {
+			found = append(found, event)
+		}
+	}
+	return found
+}
+
+// AssertEmitsEvent fails the test unless the transaction emitted an event of
+// the given type that satisfies all matchers, and returns the first such event.
+func AssertEmitsEvent(t testing.TB, txn aptos.UserTransaction, eventType string, matchers ...EventMatcher) aptos.Event {
+	t.Helper()
+	found := FindEvents(txn, eventType, matchers...)
+	if len(found) == 0 {
+		t.Errorf("no matching %s event among %d events: %s", eventType, len(txn.Events), eventTypes(txn))
+		return aptos.Event{}
+	}
+	return found[0]
+}
+
+// AssertNoEvent fails the test if the transaction emitted an event of the
+// given type that satisfies all matchers.
+func AssertNoEvent(t testing.TB, txn aptos.UserTransaction, eventType string, matchers ...EventMatcher) {
+	t.Helper()
+	if found := FindEvents(txn, eventType, matchers...); len(found) > 0 {
+		t.Errorf("unexpected %s event: %s", eventType, found[0].Data)
+	}
+}
+
+func matchesAll(event aptos.Event, matchers []EventMatcher) bool {
+	for _, match := range matchers {
+		if !match(event) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeType returns the canonical form of a type string, or the string
+// itself if it cannot be parsed.
+func normalizeType(s string) string {
+	tag, err := aptos.ParseTypeTag(s)
+	if err != nil {
+		return s
+	}
+	return tag.String()
+}
+
+func eventTypes(txn aptos.UserTransaction) string {
+	types := make([]string, len(txn.Events))
+	for i, event := range txn.Events {
+		types[i] = event.Type
+	}
+	return "[" + strings.Join(types, ", ") + "]"
+}
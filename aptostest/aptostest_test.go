@@ -0,0 +1,46 @@
+package aptostest
+
+import (
+	"encoding/json"
+	"testing"
+
+	aptos "github.com/0xbe1/aptopher"
+)
+
+func TestFindEvents(t *testing.T) {
+	txn := aptos.UserTransaction{
+		Success: true,
+		Events: []aptos.Event{
+			{Type: "0x1::coin::CoinDeposit", Data: json.RawMessage(`{"amount":"1000","account":"0x1"}`)},
+			{Type: "0x0000000000000000000000000000000000000000000000000000000000000001::coin::CoinDeposit", Data: json.RawMessage(`{"amount":"5"}`)},
+			{Type: "0x1::transaction_fee::FeeStatement", Data: json.RawMessage(`{"total_charge_gas_units":"6"}`)},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		eventType string
+		matchers  []EventMatcher
+		want      int
+	}{
+		{"by type", "0x1::coin::CoinDeposit", nil, 2},
+		{"long address", "0x0000000000000000000000000000000000000000000000000000000000000001::coin::CoinDeposit", nil, 2},
+		{"by field", "0x1::coin::CoinDeposit", []EventMatcher{EventField("amount", "1000")}, 1},
+		{"by two fields", "0x1::coin::CoinDeposit", []EventMatcher{EventField("amount", "5"), EventField("account", "0x1")}, 0},
+		{"missing type", "0x1::coin::CoinWithdraw", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(FindEvents(txn, tt.eventType, tt.matchers...)); got != tt.want {
+				t.Errorf("FindEvents() found %d events, want %d", got, tt.want)
+			}
+		})
+	}
+
+	AssertSuccess(t, txn)
+	event := AssertEmitsEvent(t, txn, "0x1::coin::CoinDeposit", EventField("amount", "5"))
+	if string(event.Data) != `{"amount":"5"}` {
+		t.Errorf("AssertEmitsEvent() = %s, want the matching event", event.Data)
+	}
+	AssertNoEvent(t, txn, "0x1::coin::CoinWithdraw")
+}
@@ -0,0 +1,140 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AssetStandard identifies how an asset is represented on-chain.
+type AssetStandard uint8
+
+const (
+	// AssetStandardUnknown is a string that is neither a coin type nor an address.
+	AssetStandardUnknown AssetStandard = iota
+
+	// AssetStandardCoin is a legacy coin, identified by its coin type,
+	// such as "0x1::aptos_coin::AptosCoin".
+	AssetStandardCoin
+
+	// AssetStandardFungibleAsset is a fungible asset, identified by the
+	// address of its metadata object, such as "0xa" for APT.
+	AssetStandardFungibleAsset
+)
+
+// String returns the name of the standard.
+func (s AssetStandard) String() string {
+	switch s {
+	case AssetStandardCoin:
+		return "coin"
+	case AssetStandardFungibleAsset:
+		return "fungible_asset"
+	default:
+		return "unknown"
+	}
+}
+
+// AssetKind reports whether s identifies a legacy coin, by parsing as a struct
+// type tag, or a fungible asset, by parsing as a metadata object address.
+// Both forms are accepted by GetAccountBalance.
+func AssetKind(s string) AssetStandard {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "::") {
+		tag, err := ParseTypeTag(s)
+		if err != nil {
+			return AssetStandardUnknown
+		}
+		if _, ok := tag.Value.(*StructTag); ok {
+			return AssetStandardCoin
+		}
+		return AssetStandardUnknown
+	}
+	if strings.TrimPrefix(s, "0x") == "" {
+		return AssetStandardUnknown
+	}
+	if _, err := ParseAccountAddress(s); err == nil {
+		return AssetStandardFungibleAsset
+	}
+	return AssetStandardUnknown
+}
+
+// FungibleAssetMetadata is the data of the 0x1::fungible_asset::Metadata
+// resource stored at a fungible asset's metadata object.
+type FungibleAssetMetadata struct {
+	Name       string `json:"name"`
+	Symbol     string `json:"symbol"`
+	Decimals   uint8  `json:"decimals"`
+	IconURI    string `json:"icon_uri"`
+	ProjectURI string `json:"project_uri"`
+}
+
+// AptosCoinFAMetadata is the address of the APT fungible asset metadata
+// object, which APT is paired with since the migration to fungible assets.
+// It is 0xa on every network; APTMetadataAddress confirms it on-chain.
+var AptosCoinFAMetadata = MustParseAccountAddress("0xa")
+
+// APTMetadataAddress returns the address of the APT fungible asset metadata
+// object, as reported by 0x1::coin::paired_metadata<AptosCoin> on the
+// client's network. The result is cached for the lifetime of the client.
+// Nodes without that view function report AptosCoinFAMetadata; any other
+// error is returned and not cached, so a later call queries the node again.
+func (c *Client) APTMetadataAddress(ctx context.Context) (AccountAddress, error) {
+	c.aptMetadataMu.Lock()
+	cached := c.aptMetadata
+	c.aptMetadataMu.Unlock()
+	if cached != nil {
+		return *cached, nil
+	}
+
+	address := AptosCoinFAMetadata
+	result, err := c.View(ctx, ViewRequest{
+		Function:      "0x1::coin::paired_metadata",
+		TypeArguments: []string{AptosCoinType},
+	})
+	switch {
+	case isViewFunctionNotFound(err):
+		// Keep the default
+	case err != nil:
+		return AccountAddress{}, err
+	default:
+		// The result is an Option<Object<Metadata>>.
+		var metadata struct {
+			Vec []struct {
+				Inner AccountAddress `json:"inner"`
+			} `json:"vec"`
+		}
+		if len(result.Data) != 1 {
+			return AccountAddress{}, fmt.Errorf("paired metadata: got %d view results, want 1", len(result.Data))
+		}
+		if err := json.Unmarshal(result.Data[0], &metadata); err != nil {
+			return AccountAddress{}, fmt.Errorf("paired metadata: %w", err)
+		}
+		if len(metadata.Vec) == 1 {
+			address = metadata.Vec[0].Inner
+		}
+	}
+
+	c.aptMetadataMu.Lock()
+	c.aptMetadata = &address
+	c.aptMetadataMu.Unlock()
+	return address, nil
+}
+
+// isViewFunctionNotFound reports whether err is a node's 4xx response to a
+// view of a function or module it does not have.
+func isViewFunctionNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode < 400 || apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests {
+		return false
+	}
+	if apiErr.ErrorCode == ErrCodeModuleNotFound {
+		return true
+	}
+	message := strings.ToLower(apiErr.Message)
+	return strings.Contains(message, "not found") ||
+		strings.Contains(message, "function_resolution_failure") ||
+		strings.Contains(message, "linker_error")
+}
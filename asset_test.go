@@ -0,0 +1,103 @@
+package aptos
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestAssetKind(t *testing.T) {
+	tests := []struct {
+		s    string
+		want AssetStandard
+	}{
+		{"0x1::aptos_coin::AptosCoin", AssetStandardCoin},
+		{" 0x1::coin::T<0x1::aptos_coin::AptosCoin> ", AssetStandardCoin},
+		{"0xa", AssetStandardFungibleAsset},
+		{"0x357b0b74bc833e95a115ad22604854d6b0fca151cecd94111770e5d6ffc9dc2b", AssetStandardFungibleAsset},
+		{"357b0b74bc833e95a115ad22604854d6b0fca151cecd94111770e5d6ffc9dc2b", AssetStandardFungibleAsset},
+		{"", AssetStandardUnknown},
+		{"0x", AssetStandardUnknown},
+		{"0x1::aptos_coin", AssetStandardUnknown},
+		{"vector<u8>", AssetStandardUnknown},
+		{"APT", AssetStandardUnknown},
+	}
+	for _, tt := range tests {
+		if got := AssetKind(tt.s); got != tt.want {
+			t.Errorf("AssetKind(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestGetAPTBalance(t *testing.T) {
+	owner := MustParseAccountAddress("0xa11ce")
+	store := PrimaryFungibleStoreAddress(owner, AptosCoinFAMetadata)
+	const (
+		coinStore      = `{"type":"0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>","data":{"coin":{"value":"300"},"frozen":false}}`
+		fungibleStore  = `{"type":"0x1::fungible_asset::FungibleStore","data":{"metadata":{"inner":"0xa"},"balance":"40","frozen":false}}`
+		emptyStore     = `{"type":"0x1::fungible_asset::FungibleStore","data":{"metadata":{"inner":"0xa"},"balance":"0","frozen":false}}`
+		concurrent     = `{"type":"0x1::fungible_asset::ConcurrentFungibleBalance","data":{"balance":{"value":"55","max_value":"18446744073709551615"}}}`
+		objectCore     = `{"type":"0x1::object::ObjectCore","data":{"owner":"0xa11ce","allow_ungated_transfer":false}}`
+		resourceAbsent = `{"message":"Resource not found","error_code":"resource_not_found"}`
+		accountAbsent  = `{"message":"Account not found","error_code":"account_not_found"}`
+	)
+
+	tests := []struct {
+		name           string
+		coinStore      string // empty if the account has no CoinStore
+		storeResources string // empty if the primary store does not exist
+		want           uint64
+	}{
+		{"coin store only", coinStore, "", 300},
+		{"fungible store only", "", "[" + objectCore + "," + fungibleStore + "]", 40},
+		{"both", coinStore, "[" + objectCore + "," + fungibleStore + "]", 340},
+		{"concurrent balance", "", "[" + objectCore + "," + emptyStore + "," + concurrent + "]", 55},
+		{"neither", "", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, closeServer := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/accounts/" + owner.String() + "/resource/0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>":
+					if tt.coinStore == "" {
+						w.WriteHeader(http.StatusNotFound)
+						w.Write([]byte(resourceAbsent))
+						return
+					}
+					w.Write([]byte(tt.coinStore))
+				case "/accounts/" + store.String() + "/resources":
+					if tt.storeResources == "" {
+						w.WriteHeader(http.StatusNotFound)
+						w.Write([]byte(accountAbsent))
+						return
+					}
+					w.Write([]byte(tt.storeResources))
+				default:
+					t.Errorf("unexpected request %s", r.URL.Path)
+					http.NotFound(w, r)
+				}
+			})
+			defer closeServer()
+
+			got, err := client.GetAPTBalance(context.Background(), owner)
+			if err != nil {
+				t.Fatalf("GetAPTBalance error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetAPTBalance = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// Errors other than not found are returned
+	client, closeServer := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"message":"internal error","error_code":"internal_error"}`))
+	})
+	defer closeServer()
+
+	if _, err := client.GetAPTBalance(context.Background(), owner); ErrorKind(err) != ErrKindServerError {
+		t.Errorf("GetAPTBalance error = %v, want a server error", err)
+	}
+}
@@ -0,0 +1,180 @@
+package aptos
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Type strings used when decoding APT balance changes from transaction JSON.
+const (
+	coinDepositEventType        = "0x1::coin::DepositEvent"
+	coinWithdrawEventType       = "0x1::coin::WithdrawEvent"
+	coinDepositModuleEventType  = "0x1::coin::CoinDeposit"
+	coinWithdrawModuleEventType = "0x1::coin::CoinWithdraw"
+	faDepositEventType          = "0x1::fungible_asset::Deposit"
+	faWithdrawEventType         = "0x1::fungible_asset::Withdraw"
+	aptosCoinStoreType          = "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>"
+	feePayerSignatureType       = "fee_payer_signature"
+)
+
+// APTBalanceChange computes the net change in the APT balance of address
+// caused by a committed transaction, in octas.
+//
+// It sums coin and fungible asset deposit/withdraw events (both legacy handle
+// events and module events) for the address's APT CoinStore and primary
+// fungible store, and subtracts the net gas fee if the address paid for gas.
+func (t *Transaction) APTBalanceChange(address AccountAddress) (int64, error) {
+	if t.IsPending() {
+		return 0, fmt.Errorf("transaction %s is pending", t.Hash)
+	}
+
+	handles, err := aptCoinStoreEventHandles(t.Changes, address)
+	if err != nil {
+		return 0, err
+	}
+	primaryStore := PrimaryFungibleStoreAddress(address, AptosCoinFAMetadata)
+
+	var delta int64
+	var storageRefund uint64
+	for i := range t.Events {
+		event := &t.Events[i]
+		switch event.Type {
+		case coinDepositEventType, coinWithdrawEventType:
+			if handles[event.GUID.CreationNumber] && sameAddress(event.GUID.AccountAddress, address) {
+				amount, err := decodeEventAmount(event)
+				if err != nil {
+					return 0, err
+				}
+				delta += signedAmount(event.Type == coinDepositEventType, amount)
+			}
+		case coinDepositModuleEventType, coinWithdrawModuleEventType:
+			var data struct {
+				CoinType string         `json:"coin_type"`
+				Account  AccountAddress `json:"account"`
+				Amount   JSONUint64     `json:"amount"`
+			}
+			if err := event.DecodeData(&data); err != nil {
+				return 0, fmt.Errorf("decode %s event: %w", event.Type, err)
+			}
+			if data.Account == address && data.CoinType == AptosCoinType {
+				delta += signedAmount(event.Type == coinDepositModuleEventType, data.Amount.Uint64())
+			}
+		case faDepositEventType, faWithdrawEventType:
+			var data struct {
+				Store  AccountAddress `json:"store"`
+				Amount JSONUint64     `json:"amount"`
+			}
+			if err := event.DecodeData(&data); err != nil {
+				return 0, fmt.Errorf("decode %s event: %w", event.Type, err)
+			}
+			if data.Store == primaryStore {
+				delta += signedAmount(event.Type == faDepositEventType, data.Amount.Uint64())
+			}
+		case FeeStatementEventType:
+			var fs FeeStatement
+			if err := event.DecodeData(&fs); err != nil {
+				return 0, fmt.Errorf("decode %s event: %w", event.Type, err)
+			}
+			storageRefund = fs.StorageFeeRefundOctas
+		}
+	}
+
+	// Gas is charged to the fee payer without emitting a withdraw event
+	gasPayer, err := t.gasPayer()
+	if err != nil {
+		return 0, err
+	}
+	if gasPayer == address {
+		fee := t.GasUsedUint64() * t.GasUnitPrice.Uint64()
+		delta -= int64(fee) - int64(storageRefund)
+	}
+	return delta, nil
+}
+
+// gasPayer returns the address that paid for gas: the fee payer if the
+// transaction is sponsored, otherwise the sender.
+func (t *Transaction) gasPayer() (AccountAddress, error) {
+	if t.Sender == "" {
+		return AccountAddress{}, nil
+	}
+	var sig struct {
+		Type            string `json:"type"`
+		FeePayerAddress string `json:"fee_payer_address"`
+	}
+	if len(t.Signature) > 0 {
+		if err := json.Unmarshal(t.Signature, &sig); err != nil {
+			return AccountAddress{}, fmt.Errorf("decode signature: %w", err)
+		}
+	}
+	if sig.Type == feePayerSignatureType {
+		return ParseAccountAddress(sig.FeePayerAddress)
+	}
+	return ParseAccountAddress(t.Sender)
+}
+
+// aptCoinStoreEventHandles returns the creation numbers of the deposit and
+// withdraw event handles of the address's CoinStore<AptosCoin>, as found in
+// the transaction changes.
+func aptCoinStoreEventHandles(changes json.RawMessage, address AccountAddress) (map[JSONUint64]bool, error) {
+	handles := make(map[JSONUint64]bool)
+	if len(changes) == 0 {
+		return handles, nil
+	}
+
+	type eventHandle struct {
+		GUID struct {
+			ID struct {
+				Addr        string     `json:"addr"`
+				CreationNum JSONUint64 `json:"creation_num"`
+			} `json:"id"`
+		} `json:"guid"`
+	}
+	var writeSet []struct {
+		Type    string         `json:"type"`
+		Address AccountAddress `json:"address"`
+		Data    struct {
+			Type string `json:"type"`
+			Data struct {
+				DepositEvents  eventHandle `json:"deposit_events"`
+				WithdrawEvents eventHandle `json:"withdraw_events"`
+			} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(changes, &writeSet); err != nil {
+		return nil, fmt.Errorf("decode changes: %w", err)
+	}
+
+	for _, change := range writeSet {
+		if change.Type != WriteSetChangeWriteResource || change.Address != address || change.Data.Type != aptosCoinStoreType {
+			continue
+		}
+		for _, h := range []eventHandle{change.Data.Data.DepositEvents, change.Data.Data.WithdrawEvents} {
+			handles[h.GUID.ID.CreationNum] = true
+		}
+	}
+	return handles, nil
+}
+
+// decodeEventAmount decodes the amount field of a legacy coin event.
+func decodeEventAmount(event *Event) (uint64, error) {
+	var data struct {
+		Amount JSONUint64 `json:"amount"`
+	}
+	if err := event.DecodeData(&data); err != nil {
+		return 0, fmt.Errorf("decode %s event: %w", event.Type, err)
+	}
+	return data.Amount.Uint64(), nil
+}
+
+// sameAddress reports whether s is a valid address string equal to address.
+func sameAddress(s string, address AccountAddress) bool {
+	parsed, err := ParseAccountAddress(s)
+	return err == nil && parsed == address
+}
+
+func signedAmount(deposit bool, amount uint64) int64 {
+	if deposit {
+		return int64(amount)
+	}
+	return -int64(amount)
+}
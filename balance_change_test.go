@@ -0,0 +1,67 @@
+package aptos
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAPTBalanceChange(t *testing.T) {
+	sender := MustParseAccountAddress("0xa11ce")
+	recipient := MustParseAccountAddress("0xb0b")
+	senderStore := PrimaryFungibleStoreAddress(sender, AptosCoinFAMetadata)
+
+	txnJSON := `{
+		"type": "user_transaction",
+		"hash": "0x1",
+		"sender": "` + sender.String() + `",
+		"gas_used": "10",
+		"gas_unit_price": "100",
+		"success": true,
+		"signature": {"type": "ed25519_signature"},
+		"changes": [{
+			"type": "write_resource",
+			"address": "` + recipient.String() + `",
+			"data": {
+				"type": "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>",
+				"data": {
+					"deposit_events": {"guid": {"id": {"addr": "` + recipient.String() + `", "creation_num": "2"}}},
+					"withdraw_events": {"guid": {"id": {"addr": "` + recipient.String() + `", "creation_num": "3"}}}
+				}
+			}
+		}],
+		"events": [
+			{"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0",
+			 "type": "0x1::fungible_asset::Withdraw", "data": {"store": "` + senderStore.String() + `", "amount": "5000"}},
+			{"guid": {"creation_number": "2", "account_address": "` + recipient.ShortString() + `"}, "sequence_number": "0",
+			 "type": "0x1::coin::DepositEvent", "data": {"amount": "5000"}},
+			{"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0",
+			 "type": "0x1::transaction_fee::FeeStatement", "data": {"storage_fee_refund_octas": "200"}}
+		]
+	}`
+
+	var txn Transaction
+	if err := json.Unmarshal([]byte(txnJSON), &txn); err != nil {
+		t.Fatalf("json.Unmarshal error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		address AccountAddress
+		want    int64
+	}{
+		{"sender pays amount and net gas", sender, -5000 - (10*100 - 200)},
+		{"recipient receives amount", recipient, 5000},
+		{"unrelated address", AccountOne, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := txn.APTBalanceChange(tt.address)
+			if err != nil {
+				t.Fatalf("APTBalanceChange error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("APTBalanceChange() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
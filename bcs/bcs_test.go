@@ -1,8 +1,12 @@
 package bcs
 
 import (
+	"bufio"
 	"bytes"
+	"errors"
+	"io"
 	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -195,6 +199,39 @@ func TestBytes(t *testing.T) {
 	}
 }
 
+func TestBytesVector(t *testing.T) {
+	tests := []struct {
+		value [][]byte
+		want  []byte
+	}{
+		{[][]byte{}, []byte{0x00}},
+		{[][]byte{{}, {0x01, 0x02}}, []byte{0x02, 0x00, 0x02, 0x01, 0x02}},
+	}
+	for _, tt := range tests {
+		s := NewSerializer()
+		s.BytesVector(tt.value)
+		if !bytes.Equal(s.ToBytes(), tt.want) {
+			t.Errorf("BytesVector(%v) = %v, want %v", tt.value, s.ToBytes(), tt.want)
+		}
+		d := NewDeserializer(tt.want)
+		got := d.BytesVector()
+		if d.Error() != nil || len(got) != len(tt.value) {
+			t.Fatalf("BytesVector roundtrip = %v, %v, want %v", got, d.Error(), tt.value)
+		}
+		for i := range got {
+			if !bytes.Equal(got[i], tt.value[i]) {
+				t.Errorf("BytesVector roundtrip[%d] = %v, want %v", i, got[i], tt.value[i])
+			}
+		}
+	}
+
+	// A count larger than the data fails without a large allocation
+	d := NewDeserializer([]byte{0xff, 0xff, 0xff, 0xff, 0x0f, 0x00})
+	if got := d.BytesVector(); got != nil || d.Error() == nil {
+		t.Errorf("BytesVector with a bad count = %v, %v, want an error", got, d.Error())
+	}
+}
+
 func TestFixedBytes(t *testing.T) {
 	value := []byte{0x01, 0x02, 0x03, 0x04}
 	s := NewSerializer()
@@ -226,6 +263,30 @@ func TestFixedBytesNoCopy(t *testing.T) {
 	}
 }
 
+func TestSkip(t *testing.T) {
+	// A 2-byte custom header followed by a BCS u16
+	d := NewDeserializer([]byte{0xca, 0xfe, 0x2a, 0x00})
+	d.Skip(2)
+	if got := d.U16(); got != 42 {
+		t.Errorf("U16 after Skip = %d, want 42", got)
+	}
+	if d.Error() != nil {
+		t.Errorf("unexpected error: %v", d.Error())
+	}
+
+	d = NewDeserializer([]byte{0x01})
+	d.Skip(2)
+	if d.Error() == nil {
+		t.Error("expected error when skipping past the end")
+	}
+
+	d = NewDeserializer([]byte{0x01})
+	d.Skip(-1)
+	if d.Error() == nil || d.Remaining() != 1 {
+		t.Errorf("Skip(-1): error = %v, remaining = %d; want error and 1", d.Error(), d.Remaining())
+	}
+}
+
 func TestBytesNoCopy(t *testing.T) {
 	// Serialize bytes with length prefix
 	s := NewSerializer()
@@ -331,6 +392,136 @@ func TestDeserializerErrors(t *testing.T) {
 	})
 }
 
+// testPair is a Marshaler of a u64 and a string for the hex helper tests.
+type testPair struct {
+	n uint64
+	s string
+}
+
+func (p testPair) MarshalBCS(s *Serializer) {
+	s.U64(p.n)
+	s.String(p.s)
+}
+
+// failingMarshaler always fails to serialize.
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalBCS(s *Serializer) {
+	s.SetError(errors.New("boom"))
+}
+
+func TestSerializeHex(t *testing.T) {
+	got, err := SerializeHex(testPair{n: 1, s: "aptos"})
+	if err != nil {
+		t.Fatalf("SerializeHex error: %v", err)
+	}
+	want := "0x0100000000000000056170746f73"
+	if got != want {
+		t.Errorf("SerializeHex = %s, want %s", got, want)
+	}
+
+	dump, err := HexDump(testPair{n: 1, s: "a longer string value"})
+	if err != nil {
+		t.Fatalf("HexDump error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(dump, "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "00000000  01 00 00 00") || !strings.HasPrefix(lines[1], "00000010  ") {
+		t.Errorf("HexDump =\n%s", dump)
+	}
+
+	if _, err := SerializeHex(failingMarshaler{}); err == nil {
+		t.Error("SerializeHex should return the serialization error")
+	}
+	if _, err := HexDump(failingMarshaler{}); err == nil {
+		t.Error("HexDump should return the serialization error")
+	}
+}
+
+// writeSample serializes one of each kind of value.
+func writeSample(s *Serializer) {
+	s.Bool(true)
+	s.U8(1)
+	s.U16(2)
+	s.U32(3)
+	s.U64(4)
+	s.U128(big.NewInt(5))
+	s.U256(big.NewInt(6))
+	s.Uleb128(300)
+	s.Bytes([]byte{7, 8})
+	s.FixedBytes([]byte{9})
+	s.String("aptos")
+}
+
+func TestStreamingSerializer(t *testing.T) {
+	buffered := NewSerializer()
+	writeSample(buffered)
+	want := buffered.ToBytes()
+
+	var out bytes.Buffer
+	streaming := NewStreamingSerializer(&out)
+	writeSample(streaming)
+	if streaming.Error() != nil {
+		t.Fatalf("Error() = %v", streaming.Error())
+	}
+	if !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("streamed %x, want %x", out.Bytes(), want)
+	}
+	if streaming.ToBytes() != nil {
+		t.Error("ToBytes() of a streaming serializer should be nil")
+	}
+}
+
+// failingWriter accepts n writes and fails the rest.
+type failingWriter struct {
+	n, calls int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.calls++
+	if w.calls > w.n {
+		return 0, errors.New("disk full")
+	}
+	return len(p), nil
+}
+
+func TestStreamingSerializerError(t *testing.T) {
+	w := &failingWriter{n: 2}
+	s := NewStreamingSerializer(w)
+	writeSample(s)
+	if s.Error() == nil || s.Error().Error() != "disk full" {
+		t.Errorf("Error() = %v, want disk full", s.Error())
+	}
+	// Only the first failing write is attempted
+	if w.calls != 3 {
+		t.Errorf("writer called %d times, want 3", w.calls)
+	}
+}
+
+func TestSerializerWriteTo(t *testing.T) {
+	s := NewSerializer()
+	s.U64(1)
+	var out bytes.Buffer
+	n, err := s.WriteTo(&out)
+	if err != nil || n != 8 || out.Len() != 8 {
+		t.Fatalf("WriteTo = %d, %v; wrote %d bytes", n, err, out.Len())
+	}
+
+	// The buffer is emptied, so the next batch is written on its own
+	s.U8(2)
+	if n, err := s.WriteTo(&out); err != nil || n != 1 {
+		t.Errorf("second WriteTo = %d, %v, want 1, nil", n, err)
+	}
+	if want := []byte{1, 0, 0, 0, 0, 0, 0, 0, 2}; !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("output = %x, want %x", out.Bytes(), want)
+	}
+
+	s.SetError(errors.New("bad value"))
+	s.U8(3)
+	if n, err := s.WriteTo(&out); err == nil || n != 0 {
+		t.Errorf("WriteTo after an error = %d, %v, want 0 and the error", n, err)
+	}
+}
+
 // Benchmarks
 
 func BenchmarkSerializerU64(b *testing.B) {
@@ -472,3 +663,44 @@ func BenchmarkBytesNoCopy(b *testing.B) {
 		_ = d.BytesNoCopy()
 	}
 }
+
+// largePayload serializes a large script-like payload: a 64 KiB code blob
+// followed by many small arguments.
+func largePayload(s *Serializer, code []byte) {
+	s.Bytes(code)
+	s.Uleb128(1024)
+	for i := 0; i < 1024; i++ {
+		s.U64(uint64(i))
+	}
+}
+
+func BenchmarkSerializerLargeToBytes(b *testing.B) {
+	code := make([]byte, 64*1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := NewSerializer()
+		largePayload(s, code)
+		io.Discard.Write(s.ToBytes())
+	}
+}
+
+func BenchmarkSerializerLargeWriteTo(b *testing.B) {
+	code := make([]byte, 64*1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := NewSerializer()
+		largePayload(s, code)
+		s.WriteTo(io.Discard)
+	}
+}
+
+func BenchmarkSerializerLargeStreaming(b *testing.B) {
+	code := make([]byte, 64*1024)
+	w := bufio.NewWriter(io.Discard)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := NewStreamingSerializer(w)
+		largePayload(s, code)
+		w.Flush()
+	}
+}
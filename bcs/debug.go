@@ -0,0 +1,24 @@
+package bcs
+
+import "encoding/hex"
+
+// SerializeHex serializes v and returns the bytes as a 0x-prefixed hex
+// string, the form other Aptos SDKs print, for comparing serializations.
+func SerializeHex(v Marshaler) (string, error) {
+	data, err := Serialize(v)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(data), nil
+}
+
+// HexDump serializes v and returns an annotated dump of the bytes, 16 per
+// line, each line starting with its offset, in the format of hexdump -C.
+// It is meant for debugging serialization mismatches byte by byte.
+func HexDump(v Marshaler) (string, error) {
+	data, err := Serialize(v)
+	if err != nil {
+		return "", err
+	}
+	return hex.Dump(data), nil
+}
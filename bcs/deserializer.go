@@ -199,6 +199,26 @@ func (d *Deserializer) Bytes() []byte {
 	return result
 }
 
+// BytesVector deserializes a vector<vector<u8>>, as written by
+// Serializer.BytesVector.
+func (d *Deserializer) BytesVector() [][]byte {
+	length := d.Uleb128()
+	if d.err != nil {
+		return nil
+	}
+	// Each element takes at least one byte, so cap the preallocation by the
+	// remaining data to avoid huge allocations on malformed input.
+	result := make([][]byte, 0, min(int(length), d.Remaining()))
+	for i := uint32(0); i < length; i++ {
+		b := d.Bytes()
+		if d.err != nil {
+			return nil
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
 // BytesNoCopy deserializes a byte slice without copying, returning a slice of the internal buffer.
 // WARNING: Do not modify the returned slice. Do not use it after the
 // Deserializer is released back to the pool or the underlying data is modified.
@@ -239,6 +259,20 @@ func (d *Deserializer) FixedBytesNoCopy(length int) []byte {
 	return result
 }
 
+// Skip advances past n bytes without reading them, for example to step over
+// a header of a custom envelope around BCS data. It sets an error if fewer
+// than n bytes remain or n is negative.
+func (d *Deserializer) Skip(n int) {
+	if n < 0 {
+		d.SetError(fmt.Errorf("bcs: cannot skip %d bytes", n))
+		return
+	}
+	if !d.checkRemaining(n) {
+		return
+	}
+	d.offset += n
+}
+
 // String deserializes a UTF-8 string with a ULEB128 length prefix.
 func (d *Deserializer) String() string {
 	return string(d.Bytes())
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math/big"
 	"sync"
 )
@@ -25,6 +26,7 @@ var serializerPool = sync.Pool{
 func AcquireSerializer() *Serializer {
 	s := serializerPool.Get().(*Serializer)
 	s.buf.Reset()
+	s.w = nil
 	s.err = nil
 	return s
 }
@@ -40,6 +42,11 @@ func ReleaseSerializer(s *Serializer) {
 type Serializer struct {
 	buf bytes.Buffer
 	err error
+
+	// w receives the output directly instead of buf for serializers created
+	// with NewStreamingSerializer.
+	w       io.Writer
+	scratch [1]byte
 }
 
 // NewSerializer creates a new BCS serializer.
@@ -48,6 +55,47 @@ func NewSerializer() *Serializer {
 	return &Serializer{}
 }
 
+// NewStreamingSerializer creates a BCS serializer that writes each value to w
+// as it is serialized, without buffering the output. The first write error is
+// recorded like any other serialization error, and later operations are
+// no-ops. ToBytes returns nil for a streaming serializer. Each value is a
+// separate small write, so wrap unbuffered writers such as files or network
+// connections in a bufio.Writer.
+func NewStreamingSerializer(w io.Writer) *Serializer {
+	return &Serializer{w: w}
+}
+
+// WriteTo writes the serialized bytes to w and empties the buffer, so a
+// serializer can flush its output in batches. It implements io.WriterTo, and
+// returns the serialization error, if any, without writing.
+func (s *Serializer) WriteTo(w io.Writer) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.buf.WriteTo(w)
+}
+
+// write appends p to the output.
+func (s *Serializer) write(p []byte) {
+	if s.w == nil {
+		s.buf.Write(p)
+		return
+	}
+	if _, err := s.w.Write(p); err != nil {
+		s.SetError(err)
+	}
+}
+
+// writeByte appends b to the output.
+func (s *Serializer) writeByte(b byte) {
+	if s.w == nil {
+		s.buf.WriteByte(b)
+		return
+	}
+	s.scratch[0] = b
+	s.write(s.scratch[:])
+}
+
 // Error returns any error that occurred during serialization.
 func (s *Serializer) Error() error {
 	return s.err
@@ -60,9 +108,10 @@ func (s *Serializer) SetError(err error) {
 	}
 }
 
-// ToBytes returns the serialized bytes. Returns nil if an error occurred.
+// ToBytes returns the serialized bytes. Returns nil if an error occurred, or
+// if the serializer streams its output.
 func (s *Serializer) ToBytes() []byte {
-	if s.err != nil {
+	if s.err != nil || s.w != nil {
 		return nil
 	}
 	return s.buf.Bytes()
@@ -75,9 +124,9 @@ func (s *Serializer) Bool(v bool) {
 		return
 	}
 	if v {
-		s.buf.WriteByte(0x01)
+		s.writeByte(0x01)
 	} else {
-		s.buf.WriteByte(0x00)
+		s.writeByte(0x00)
 	}
 }
 
@@ -86,7 +135,7 @@ func (s *Serializer) U8(v uint8) {
 	if s.err != nil {
 		return
 	}
-	s.buf.WriteByte(v)
+	s.writeByte(v)
 }
 
 // U16 serializes an unsigned 16-bit integer in little-endian format.
@@ -96,7 +145,7 @@ func (s *Serializer) U16(v uint16) {
 	}
 	var buf [2]byte
 	binary.LittleEndian.PutUint16(buf[:], v)
-	s.buf.Write(buf[:])
+	s.write(buf[:])
 }
 
 // U32 serializes an unsigned 32-bit integer in little-endian format.
@@ -106,7 +155,7 @@ func (s *Serializer) U32(v uint32) {
 	}
 	var buf [4]byte
 	binary.LittleEndian.PutUint32(buf[:], v)
-	s.buf.Write(buf[:])
+	s.write(buf[:])
 }
 
 // U64 serializes an unsigned 64-bit integer in little-endian format.
@@ -116,7 +165,7 @@ func (s *Serializer) U64(v uint64) {
 	}
 	var buf [8]byte
 	binary.LittleEndian.PutUint64(buf[:], v)
-	s.buf.Write(buf[:])
+	s.write(buf[:])
 }
 
 // U128 serializes a 128-bit unsigned integer in little-endian format.
@@ -143,7 +192,7 @@ func (s *Serializer) U128(v *big.Int) {
 	for i, b := range bytes {
 		buf[len(bytes)-1-i] = b
 	}
-	s.buf.Write(buf[:])
+	s.write(buf[:])
 }
 
 // U256 serializes a 256-bit unsigned integer in little-endian format.
@@ -170,7 +219,7 @@ func (s *Serializer) U256(v *big.Int) {
 	for i, b := range bytes {
 		buf[len(bytes)-1-i] = b
 	}
-	s.buf.Write(buf[:])
+	s.write(buf[:])
 }
 
 // Uleb128 serializes an unsigned integer using ULEB128 variable-length encoding.
@@ -181,7 +230,7 @@ func (s *Serializer) Uleb128(v uint32) {
 	}
 	// Fast path: single byte for values < 128 (most common case)
 	if v < 128 {
-		s.buf.WriteByte(byte(v))
+		s.writeByte(byte(v))
 		return
 	}
 	// Slow path for larger values
@@ -191,7 +240,7 @@ func (s *Serializer) Uleb128(v uint32) {
 		if v != 0 {
 			b |= 0x80
 		}
-		s.buf.WriteByte(b)
+		s.writeByte(b)
 		if v == 0 {
 			break
 		}
@@ -204,16 +253,30 @@ func (s *Serializer) Bytes(v []byte) {
 		return
 	}
 	s.Uleb128(uint32(len(v)))
-	s.buf.Write(v)
+	s.write(v)
+}
+
+// BytesVector serializes a vector<vector<u8>>: a ULEB128 count followed by
+// each byte slice with its own length prefix. Entry function arguments and
+// the module code passed to 0x1::code::publish_package_txn use this encoding.
+func (s *Serializer) BytesVector(v [][]byte) {
+	if s.err != nil {
+		return
+	}
+	s.Uleb128(uint32(len(v)))
+	for _, b := range v {
+		s.Bytes(b)
+	}
 }
 
 // FixedBytes serializes a byte slice without a length prefix.
-// Use for fixed-size types like AccountAddress.
+// Use for fixed-size types like AccountAddress, or to append bytes that are
+// already BCS-encoded or belong to a custom framing around BCS data.
 func (s *Serializer) FixedBytes(v []byte) {
 	if s.err != nil {
 		return
 	}
-	s.buf.Write(v)
+	s.write(v)
 }
 
 // String serializes a UTF-8 string with a ULEB128 length prefix.
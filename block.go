@@ -1,28 +1,78 @@
 package aptos
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 // Block represents an Aptos block.
 type Block struct {
-	BlockHeight    string            `json:"block_height"`
+	BlockHeight    JSONUint64        `json:"block_height"`
 	BlockHash      string            `json:"block_hash"`
-	BlockTimestamp string            `json:"block_timestamp"`
-	FirstVersion   string            `json:"first_version"`
-	LastVersion    string            `json:"last_version"`
+	BlockTimestamp JSONUint64        `json:"block_timestamp"`
+	FirstVersion   JSONUint64        `json:"first_version"`
+	LastVersion    JSONUint64        `json:"last_version"`
 	Transactions   []json.RawMessage `json:"transactions,omitempty"`
 }
 
 // BlockHeightUint64 returns the block height as uint64.
 func (b *Block) BlockHeightUint64() uint64 {
-	return parseStringToUint64(b.BlockHeight)
+	return b.BlockHeight.Uint64()
 }
 
 // FirstVersionUint64 returns the first version as uint64.
 func (b *Block) FirstVersionUint64() uint64 {
-	return parseStringToUint64(b.FirstVersion)
+	return b.FirstVersion.Uint64()
 }
 
 // LastVersionUint64 returns the last version as uint64.
 func (b *Block) LastVersionUint64() uint64 {
-	return parseStringToUint64(b.LastVersion)
+	return b.LastVersion.Uint64()
+}
+
+// Time returns the block timestamp.
+func (b *Block) Time() time.Time {
+	return microsTime(b.BlockTimestamp)
+}
+
+// TransactionCount returns the number of transactions in the block.
+func (b *Block) TransactionCount() uint64 {
+	return b.LastVersion.Uint64() - b.FirstVersion.Uint64() + 1
+}
+
+// ContainsVersion reports whether the transaction at version is in the block.
+func (b *Block) ContainsVersion(version uint64) bool {
+	return version >= b.FirstVersion.Uint64() && version <= b.LastVersion.Uint64()
+}
+
+// ForEachTransaction decodes the block's transactions one at a time and
+// calls fn with each and its version, stopping at the first error. Only the
+// transactions included in the response are visited; the node may return
+// fewer than TransactionCount for large blocks (see GetTransactionsInBlock).
+func (b *Block) ForEachTransaction(fn func(version uint64, txn *Transaction) error) error {
+	for i, raw := range b.Transactions {
+		version := b.FirstVersion.Uint64() + uint64(i)
+		var txn Transaction
+		if err := json.Unmarshal(raw, &txn); err != nil {
+			return fmt.Errorf("decode transaction %d: %w", version, err)
+		}
+		if err := fn(version, &txn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeTransactions decodes the block's transactions.
+func (b *Block) DecodeTransactions() ([]Transaction, error) {
+	txns := make([]Transaction, 0, len(b.Transactions))
+	err := b.ForEachTransaction(func(_ uint64, txn *Transaction) error {
+		txns = append(txns, *txn)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txns, nil
 }
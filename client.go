@@ -2,39 +2,84 @@ package aptos
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
+
+	"github.com/0xbe1/aptopher/bcs"
+	"golang.org/x/sync/singleflight"
 )
 
 // gasPriceCacheTTL is the time-to-live for cached gas price estimates.
 const gasPriceCacheTTL = 10 * time.Second
 
+// maxConcurrentRequests bounds the number of in-flight requests issued by batch helpers.
+const maxConcurrentRequests = 8
+
 // Client is the main Aptos SDK client.
 type Client struct {
 	http    *httpClient
 	chainID uint8
 
+	// submitHTTP sends transaction submissions and simulations; it is the
+	// same as http unless ClientConfig.SubmitNodeURL is set.
+	submitHTTP *httpClient
+
+	// ansAddress is the Aptos Name Service router, from ClientConfig.ANSAddress.
+	ansAddress AccountAddress
+
+	// submissions tracks submitted transactions; nil unless
+	// ClientConfig.TrackSubmissions is set.
+	submissions *submissionTracker
+
+	// aptMetadata caches the result of APTMetadataAddress.
+	aptMetadataMu sync.Mutex
+	aptMetadata   *AccountAddress
+
 	// Gas price cache
-	gasPriceMu       sync.RWMutex
-	cachedGasPrice   uint64
-	gasPriceCachedAt time.Time
+	gasPriceMu          sync.RWMutex
+	cachedGasEstimation GasEstimation
+	gasPriceCachedAt    time.Time
 }
 
 // NewClient creates a new Aptos client with the given configuration.
 func NewClient(config ClientConfig) (*Client, error) {
 	hc := config.HTTPClient
+	timeout := config.Timeout
 	if hc == nil {
-		timeout := config.Timeout
 		if timeout == 0 {
 			timeout = 30 * time.Second
 		}
-		hc = &http.Client{Timeout: timeout}
+		// The timeout is applied per request instead of as http.Client.Timeout
+		// so that ContextWithRequestTimeout can extend it for a single call.
+		hc = &http.Client{Transport: config.Transport}
 	}
 
-	return &Client{
-		http: newHTTPClient(config.NodeURL, hc),
-	}, nil
+	newNodeClient := func(nodeURL string) *httpClient {
+		httpClient := newHTTPClient(nodeURL, hc)
+		httpClient.timeout = timeout
+		if config.RespectRateLimit {
+			httpClient.rateLimiter = &rateLimiter{}
+		}
+		if config.DeduplicateReads {
+			httpClient.group = &singleflight.Group{}
+		}
+		httpClient.retry = newRetryPolicy(config)
+		return httpClient
+	}
+
+	client := &Client{http: newNodeClient(config.NodeURL), ansAddress: config.ANSAddress}
+	client.submitHTTP = client.http
+	if config.TrackSubmissions {
+		client.submissions = newSubmissionTracker()
+	}
+	if config.SubmitNodeURL != "" {
+		client.submitHTTP = newNodeClient(config.SubmitNodeURL)
+	}
+	return client, nil
 }
 
 // GetLedgerInfo retrieves the current ledger information.
@@ -64,19 +109,128 @@ func (c *Client) HealthCheck(ctx context.Context) error {
 	return err
 }
 
+// HealthCheckWithin checks that the node is healthy and that its latest
+// ledger timestamp is at most maxLagSecs seconds old, so reads are not served
+// from a node that has fallen behind. Returns an error wrapping
+// ErrNodeLagging if the node reports that it is behind, or another error if
+// it cannot be reached.
+func (c *Client) HealthCheckWithin(ctx context.Context, maxLagSecs uint64) error {
+	_, err := c.http.get(ctx, "/-/healthy?duration_secs="+strconv.FormatUint(maxLagSecs, 10), nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusServiceUnavailable {
+		return fmt.Errorf("%w: %w", ErrNodeLagging, err)
+	}
+	return err
+}
+
+// GetChainID returns the chain ID of the node's network. It is read from the
+// X-Aptos-Chain-Id header of the health check endpoint, avoiding a full ledger
+// info parse, and falls back to GetLedgerInfo if the header is missing.
+func (c *Client) GetChainID(ctx context.Context) (uint8, error) {
+	metadata, err := c.http.get(ctx, "/-/healthy", nil)
+	if err != nil {
+		return 0, err
+	}
+	if metadata.ChainID != 0 {
+		return metadata.ChainID, nil
+	}
+
+	info, err := c.GetLedgerInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return info.Data.ChainID, nil
+}
+
 // EstimateGasPrice retrieves the current gas price estimation.
+// Every successful call refreshes the estimate used by CachedGasEstimate.
 func (c *Client) EstimateGasPrice(ctx context.Context) (Response[GasEstimation], error) {
+	estimation, _, err := c.estimateGasPrice(ctx)
+	return estimation, err
+}
+
+// estimateGasPrice is EstimateGasPrice, also returning when the estimation was
+// fetched. A concurrent fetch may replace the cache before the caller reads
+// it, so the time is returned rather than read back.
+func (c *Client) estimateGasPrice(ctx context.Context) (Response[GasEstimation], time.Time, error) {
 	var estimation GasEstimation
 	metadata, err := c.http.get(ctx, "/estimate_gas_price", &estimation)
 	if err != nil {
-		return Response[GasEstimation]{}, err
+		return Response[GasEstimation]{}, time.Time{}, err
+	}
+	fetchedAt := time.Now()
+
+	c.gasPriceMu.Lock()
+	if fetchedAt.After(c.gasPriceCachedAt) {
+		c.cachedGasEstimation = estimation
+		c.gasPriceCachedAt = fetchedAt
+	}
+	c.gasPriceMu.Unlock()
+
+	return Response[GasEstimation]{Data: estimation, Metadata: metadata}, fetchedAt, nil
+}
+
+// CachedGasEstimate returns a gas price estimation that is at most maxAge old,
+// fetching a fresh one from the node only when the cached estimate is too old.
+//
+// If the fetch fails but an older estimate is cached, the older estimate is
+// returned with Stale set instead of an error.
+func (c *Client) CachedGasEstimate(ctx context.Context, maxAge time.Duration) (CachedGasEstimation, error) {
+	if estimation, ok := c.cachedGasEstimate(maxAge); ok {
+		return estimation, nil
+	}
+
+	fresh, fetchedAt, err := c.estimateGasPrice(ctx)
+	if err != nil {
+		c.gasPriceMu.RLock()
+		defer c.gasPriceMu.RUnlock()
+		if c.gasPriceCachedAt.IsZero() {
+			return CachedGasEstimation{}, err
+		}
+		return CachedGasEstimation{
+			GasEstimation: c.cachedGasEstimation,
+			FetchedAt:     c.gasPriceCachedAt,
+			Stale:         true,
+		}, nil
+	}
+
+	return CachedGasEstimation{
+		GasEstimation: fresh.Data,
+		FetchedAt:     fetchedAt,
+	}, nil
+}
+
+// RecommendGasUnitPrice returns the gas unit price to use for a transaction of
+// the given priority: the deprioritized, regular, or prioritized estimate of
+// the node. Estimates fetched within the last few seconds are reused.
+func (c *Client) RecommendGasUnitPrice(ctx context.Context, priority GasPriority) (uint64, error) {
+	if cached, ok := c.cachedGasEstimate(gasPriceCacheTTL); ok && cached.GasEstimate > 0 {
+		return cached.UnitPrice(priority), nil
+	}
+	estimate, err := c.EstimateGasPrice(ctx)
+	if err != nil {
+		return 0, err
 	}
-	return Response[GasEstimation]{Data: estimation, Metadata: metadata}, nil
+	return estimate.Data.UnitPrice(priority), nil
+}
+
+// cachedGasEstimate returns the cached estimation if it is at most maxAge old.
+func (c *Client) cachedGasEstimate(maxAge time.Duration) (CachedGasEstimation, bool) {
+	c.gasPriceMu.RLock()
+	defer c.gasPriceMu.RUnlock()
+	if c.gasPriceCachedAt.IsZero() || time.Since(c.gasPriceCachedAt) > maxAge {
+		return CachedGasEstimation{}, false
+	}
+	return CachedGasEstimation{
+		GasEstimation: c.cachedGasEstimation,
+		FetchedAt:     c.gasPriceCachedAt,
+	}, true
 }
 
 // GetAccount retrieves account information including sequence number and authentication key.
 func (c *Client) GetAccount(ctx context.Context, address AccountAddress, opts ...RequestOption) (Response[AccountData], error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/accounts/" + address.String() + options.BuildQueryParams()
 
 	var account AccountData
@@ -87,24 +241,89 @@ func (c *Client) GetAccount(ctx context.Context, address AccountAddress, opts ..
 	return Response[AccountData]{Data: account, Metadata: metadata}, nil
 }
 
+// GetSequenceNumber retrieves the sequence number of an account.
+// A malformed sequence number is reported as an error.
+func (c *Client) GetSequenceNumber(ctx context.Context, address AccountAddress, opts ...RequestOption) (uint64, error) {
+	account, err := c.GetAccount(ctx, address, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return account.Data.SequenceNumber.Uint64(), nil
+}
+
 // GetAccountResources retrieves all resources for an account.
+// With WithTreatNotFoundAsEmpty, an account that does not exist yields an
+// empty list instead of an error.
 func (c *Client) GetAccountResources(ctx context.Context, address AccountAddress, opts ...RequestOption) (Response[[]MoveResource], error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/accounts/" + address.String() + "/resources" + options.BuildQueryParams()
 
 	var resources []MoveResource
 	metadata, err := c.http.get(ctx, path, &resources)
 	if err != nil {
+		if options.TreatNotFoundAsEmpty && IsAccountNotFound(err) {
+			return Response[[]MoveResource]{Metadata: metadata}, nil
+		}
 		return Response[[]MoveResource]{}, err
 	}
 	return Response[[]MoveResource]{Data: resources, Metadata: metadata}, nil
 }
 
+// GetAccountResourceTypes retrieves the types of all resources for an
+// account, such as "0x1::account::Account". It fetches the same response as
+// GetAccountResources but decodes only the type of each resource, skipping
+// the data, which saves memory and CPU on accounts holding large resources.
+func (c *Client) GetAccountResourceTypes(ctx context.Context, address AccountAddress, opts ...RequestOption) (Response[[]string], error) {
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
+	path := "/accounts/" + address.String() + "/resources" + options.BuildQueryParams()
+
+	var resources []struct {
+		Type string `json:"type"`
+	}
+	metadata, err := c.http.get(ctx, path, &resources)
+	if err != nil {
+		if options.TreatNotFoundAsEmpty && IsAccountNotFound(err) {
+			return Response[[]string]{Metadata: metadata}, nil
+		}
+		return Response[[]string]{}, err
+	}
+	types := make([]string, len(resources))
+	for i, r := range resources {
+		types[i] = r.Type
+	}
+	return Response[[]string]{Data: types, Metadata: metadata}, nil
+}
+
+// GetAccountResourceStructTags is like GetAccountResourceTypes, but returns
+// the types parsed into StructTags, e.g. to group resources by module.
+func (c *Client) GetAccountResourceStructTags(ctx context.Context, address AccountAddress, opts ...RequestOption) (Response[[]StructTag], error) {
+	types, err := c.GetAccountResourceTypes(ctx, address, opts...)
+	if err != nil {
+		return Response[[]StructTag]{}, err
+	}
+	tags := make([]StructTag, len(types.Data))
+	for i, resourceType := range types.Data {
+		tag, err := ParseTypeTag(resourceType)
+		if err != nil {
+			return Response[[]StructTag]{}, fmt.Errorf("invalid resource type %s: %w", resourceType, err)
+		}
+		structTag, ok := tag.Value.(*StructTag)
+		if !ok {
+			return Response[[]StructTag]{}, fmt.Errorf("invalid resource type %s: not a struct", resourceType)
+		}
+		tags[i] = *structTag
+	}
+	return Response[[]StructTag]{Data: tags, Metadata: types.Metadata}, nil
+}
+
 // GetAccountResourcesBCS retrieves all resources for an account as raw BCS bytes.
 // This is faster than GetAccountResources as it skips JSON parsing.
-// Use bcs.Deserializer to decode the response.
+// Decode the response with DecodeResourceGroup.
 func (c *Client) GetAccountResourcesBCS(ctx context.Context, address AccountAddress, opts ...RequestOption) (BCSResponse, error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/accounts/" + address.String() + "/resources" + options.BuildQueryParams()
 
 	data, metadata, err := c.http.getBCS(ctx, path)
@@ -116,7 +335,8 @@ func (c *Client) GetAccountResourcesBCS(ctx context.Context, address AccountAddr
 
 // GetAccountResource retrieves a specific resource for an account.
 func (c *Client) GetAccountResource(ctx context.Context, address AccountAddress, resourceType string, opts ...RequestOption) (Response[MoveResource], error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/accounts/" + address.String() + "/resource/" + resourceType + options.BuildQueryParams()
 
 	var resource MoveResource
@@ -131,7 +351,8 @@ func (c *Client) GetAccountResource(ctx context.Context, address AccountAddress,
 // This is faster than GetAccountResource as it skips JSON parsing.
 // Use bcs.Deserializer to decode the response.
 func (c *Client) GetAccountResourceBCS(ctx context.Context, address AccountAddress, resourceType string, opts ...RequestOption) (BCSResponse, error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/accounts/" + address.String() + "/resource/" + resourceType + options.BuildQueryParams()
 
 	data, metadata, err := c.http.getBCS(ctx, path)
@@ -141,14 +362,45 @@ func (c *Client) GetAccountResourceBCS(ctx context.Context, address AccountAddre
 	return BCSResponse{Data: data, Metadata: metadata}, nil
 }
 
+// GetResourceGroupMember retrieves a single member of a resource group (AIP-9),
+// such as 0x1::object::ObjectCore in 0x1::object::ObjectGroup, as raw BCS bytes.
+// The whole group is fetched and the requested member is extracted from it.
+// Returns ErrResourceNotFound if the group does not contain the member.
+func (c *Client) GetResourceGroupMember(ctx context.Context, address AccountAddress, groupType, memberType string, opts ...RequestOption) (BCSResponse, error) {
+	group, err := c.GetAccountResourceBCS(ctx, address, groupType, opts...)
+	if err != nil {
+		return BCSResponse{}, err
+	}
+
+	var rg ResourceGroup
+	if err := bcs.Deserialize(group.Data, &rg); err != nil {
+		return BCSResponse{}, fmt.Errorf("failed to decode resource group %s: %w", groupType, err)
+	}
+	data, ok := rg.Member(memberType)
+	if !ok {
+		return BCSResponse{}, &APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("resource group %s has no member %s", groupType, memberType),
+			ErrorCode:  ErrCodeResourceNotFound,
+		}
+	}
+	return BCSResponse{Data: data, Metadata: group.Metadata}, nil
+}
+
 // GetAccountModules retrieves all modules for an account.
+// With WithTreatNotFoundAsEmpty, an account that does not exist yields an
+// empty list instead of an error.
 func (c *Client) GetAccountModules(ctx context.Context, address AccountAddress, opts ...RequestOption) (Response[[]MoveModuleBytecode], error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/accounts/" + address.String() + "/modules" + options.BuildQueryParams()
 
 	var modules []MoveModuleBytecode
 	metadata, err := c.http.get(ctx, path, &modules)
 	if err != nil {
+		if options.TreatNotFoundAsEmpty && IsAccountNotFound(err) {
+			return Response[[]MoveModuleBytecode]{Metadata: metadata}, nil
+		}
 		return Response[[]MoveModuleBytecode]{}, err
 	}
 	return Response[[]MoveModuleBytecode]{Data: modules, Metadata: metadata}, nil
@@ -158,7 +410,8 @@ func (c *Client) GetAccountModules(ctx context.Context, address AccountAddress,
 // This is faster than GetAccountModules as it skips JSON parsing.
 // Use bcs.Deserializer to decode the response.
 func (c *Client) GetAccountModulesBCS(ctx context.Context, address AccountAddress, opts ...RequestOption) (BCSResponse, error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/accounts/" + address.String() + "/modules" + options.BuildQueryParams()
 
 	data, metadata, err := c.http.getBCS(ctx, path)
@@ -170,7 +423,8 @@ func (c *Client) GetAccountModulesBCS(ctx context.Context, address AccountAddres
 
 // GetAccountModule retrieves a specific module for an account.
 func (c *Client) GetAccountModule(ctx context.Context, address AccountAddress, moduleName string, opts ...RequestOption) (Response[MoveModuleBytecode], error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/accounts/" + address.String() + "/module/" + moduleName + options.BuildQueryParams()
 
 	var module MoveModuleBytecode
@@ -181,11 +435,30 @@ func (c *Client) GetAccountModule(ctx context.Context, address AccountAddress, m
 	return Response[MoveModuleBytecode]{Data: module, Metadata: metadata}, nil
 }
 
+// ValidateEntryFunction fetches the ABI of the entry function's module and
+// checks the entry function against it with EntryFunction.ValidateABI. This
+// catches mistakes such as a missing coin type argument before submission.
+func (c *Client) ValidateEntryFunction(ctx context.Context, entryFunction *EntryFunction, opts ...RequestOption) error {
+	module, err := c.GetAccountModule(ctx, entryFunction.Module.Address, entryFunction.Module.Name, opts...)
+	if err != nil {
+		return fmt.Errorf("get module %s: %w", entryFunction.Module, err)
+	}
+	if module.Data.ABI == nil {
+		return fmt.Errorf("module %s has no ABI", entryFunction.Module)
+	}
+	fn, ok := module.Data.ABI.Function(entryFunction.Function)
+	if !ok {
+		return fmt.Errorf("function %s::%s not found", entryFunction.Module, entryFunction.Function)
+	}
+	return entryFunction.ValidateABI(fn)
+}
+
 // GetAccountModuleBCS retrieves a specific module for an account as raw BCS bytes.
 // This is faster than GetAccountModule as it skips JSON parsing.
 // Use bcs.Deserializer to decode the response.
 func (c *Client) GetAccountModuleBCS(ctx context.Context, address AccountAddress, moduleName string, opts ...RequestOption) (BCSResponse, error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/accounts/" + address.String() + "/module/" + moduleName + options.BuildQueryParams()
 
 	data, metadata, err := c.http.getBCS(ctx, path)
@@ -197,7 +470,8 @@ func (c *Client) GetAccountModuleBCS(ctx context.Context, address AccountAddress
 
 // GetAccountBalance retrieves the balance of a specific asset type for an account.
 func (c *Client) GetAccountBalance(ctx context.Context, address AccountAddress, assetType string, opts ...RequestOption) (Response[uint64], error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/accounts/" + address.String() + "/balance/" + assetType + options.BuildQueryParams()
 
 	var balance uint64
@@ -207,3 +481,124 @@ func (c *Client) GetAccountBalance(ctx context.Context, address AccountAddress,
 	}
 	return Response[uint64]{Data: balance, Metadata: metadata}, nil
 }
+
+// AptosCoinType is the coin type of the native APT coin.
+const AptosCoinType = "0x1::aptos_coin::AptosCoin"
+
+// GetAPTBalance retrieves the spendable APT balance of an account in octas:
+// the sum of its legacy CoinStore<AptosCoin> and its APT primary fungible
+// store, either of which may be missing. The result is correct before,
+// during, and after an account migrates to fungible assets.
+func (c *Client) GetAPTBalance(ctx context.Context, address AccountAddress, opts ...RequestOption) (uint64, error) {
+	var (
+		coinBalance, storeBalance uint64
+		coinErr, storeErr         error
+		wg                        sync.WaitGroup
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		coinBalance, coinErr = c.aptCoinStoreBalance(ctx, address, opts...)
+	}()
+	go func() {
+		defer wg.Done()
+		storeBalance, storeErr = c.fungibleStoreBalance(ctx, PrimaryFungibleStoreAddress(address, AptosCoinFAMetadata), opts...)
+	}()
+	wg.Wait()
+
+	if coinErr != nil {
+		return 0, fmt.Errorf("read APT coin store: %w", coinErr)
+	}
+	if storeErr != nil {
+		return 0, fmt.Errorf("read APT primary fungible store: %w", storeErr)
+	}
+	return coinBalance + storeBalance, nil
+}
+
+// aptCoinStoreBalance returns the balance of an account's legacy APT
+// CoinStore, or zero if it has none.
+func (c *Client) aptCoinStoreBalance(ctx context.Context, address AccountAddress, opts ...RequestOption) (uint64, error) {
+	resource, err := c.GetAccountResource(ctx, address, "0x1::coin::CoinStore<"+AptosCoinType+">", opts...)
+	if ErrorKind(err) == ErrKindNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var store CoinStoreResource
+	if err := resource.Data.DecodeData(&store); err != nil {
+		return 0, fmt.Errorf("decode CoinStore: %w", err)
+	}
+	return uint64(store.Coin.Value), nil
+}
+
+// fungibleStoreBalance returns the balance of the fungible store object at
+// store, or zero if it does not exist. Stores with a concurrent balance keep
+// it in a separate ConcurrentFungibleBalance resource.
+func (c *Client) fungibleStoreBalance(ctx context.Context, store AccountAddress, opts ...RequestOption) (uint64, error) {
+	resources, err := c.GetAccountResources(ctx, store, append(opts[:len(opts):len(opts)], WithTreatNotFoundAsEmpty())...)
+	if err != nil {
+		return 0, err
+	}
+	var balance uint64
+	for i := range resources.Data {
+		resource := &resources.Data[i]
+		switch resource.Type {
+		case "0x1::fungible_asset::FungibleStore":
+			var data struct {
+				Balance JSONUint64 `json:"balance"`
+			}
+			if err := resource.DecodeData(&data); err != nil {
+				return 0, fmt.Errorf("decode FungibleStore: %w", err)
+			}
+			balance += uint64(data.Balance)
+		case "0x1::fungible_asset::ConcurrentFungibleBalance":
+			var data struct {
+				Balance struct {
+					Value JSONUint64 `json:"value"`
+				} `json:"balance"`
+			}
+			if err := resource.DecodeData(&data); err != nil {
+				return 0, fmt.Errorf("decode ConcurrentFungibleBalance: %w", err)
+			}
+			balance += uint64(data.Balance.Value)
+		}
+	}
+	return balance, nil
+}
+
+// forEachConcurrent calls fn for each index in [0, n) with at most limit
+// calls in flight, and waits for all of them to finish. A limit of zero or
+// less uses maxConcurrentRequests. It returns the error of each call by
+// index. Once ctx is done, no further calls are started, and the error of
+// each skipped index is ctx's error.
+func forEachConcurrent(ctx context.Context, n, limit int, fn func(i int) error) []error {
+	if limit <= 0 {
+		limit = maxConcurrentRequests
+	}
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, limit)
+	for i := 0; i < n; i++ {
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			for j := i; j < n; j++ {
+				errs[j] = err
+			}
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
@@ -1,8 +1,10 @@
 package aptos
 
 import (
+	"context"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // RequestOptions contains options for API requests.
@@ -10,6 +12,14 @@ type RequestOptions struct {
 	LedgerVersion *uint64
 	Start         *uint64
 	Limit         *uint16
+
+	// TreatNotFoundAsEmpty makes account list queries return an empty
+	// result instead of an account_not_found error.
+	TreatNotFoundAsEmpty bool
+
+	// Timeout overrides the client's default timeout for each request the
+	// call makes; see WithTimeout.
+	Timeout *time.Duration
 }
 
 // RequestOption is a function that modifies request options.
@@ -24,6 +34,63 @@ func ApplyOptions(opts ...RequestOption) RequestOptions {
 	return options
 }
 
+// ApplyOptionsContext applies all options on top of any ledger version pinned
+// in ctx with ContextWithLedgerVersion. An explicit WithLedgerVersion option
+// takes precedence over the context value.
+func ApplyOptionsContext(ctx context.Context, opts ...RequestOption) RequestOptions {
+	var options RequestOptions
+	if version, ok := LedgerVersionFromContext(ctx); ok {
+		options.LedgerVersion = &version
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// ledgerVersionContextKey is the context key for a pinned ledger version.
+type ledgerVersionContextKey struct{}
+
+// ContextWithLedgerVersion returns a copy of ctx that pins reads to the given
+// ledger version. Client methods that accept a ledger version use it unless
+// the call passes WithLedgerVersion explicitly.
+func ContextWithLedgerVersion(ctx context.Context, version uint64) context.Context {
+	return context.WithValue(ctx, ledgerVersionContextKey{}, version)
+}
+
+// withoutLedgerVersion returns a copy of ctx with any pinned ledger version
+// removed, for reads that must observe the latest state.
+func withoutLedgerVersion(ctx context.Context) context.Context {
+	if _, ok := LedgerVersionFromContext(ctx); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, ledgerVersionContextKey{}, nil)
+}
+
+// LedgerVersionFromContext returns the ledger version pinned in ctx, if any.
+func LedgerVersionFromContext(ctx context.Context) (uint64, bool) {
+	version, ok := ctx.Value(ledgerVersionContextKey{}).(uint64)
+	return version, ok
+}
+
+// requestTimeoutContextKey is the context key for a per-call request timeout.
+type requestTimeoutContextKey struct{}
+
+// ContextWithRequestTimeout returns a copy of ctx that overrides the client's
+// default timeout (ClientConfig.Timeout) for each request made with it. Unlike
+// context.WithTimeout, which can only shorten the default, it can also extend
+// it, e.g. for a large module fetch. A timeout of zero or less disables the
+// default timeout; ctx's own deadline, if any, still applies.
+func ContextWithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutContextKey{}, timeout)
+}
+
+// requestTimeoutFromContext returns the request timeout set in ctx, if any.
+func requestTimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(requestTimeoutContextKey{}).(time.Duration)
+	return timeout, ok
+}
+
 // WithLedgerVersion specifies a ledger version for the request.
 // This retrieves the state at a specific historical version.
 func WithLedgerVersion(version uint64) RequestOption {
@@ -46,6 +113,34 @@ func WithLimit(limit uint16) RequestOption {
 	}
 }
 
+// WithTreatNotFoundAsEmpty makes GetAccountResources and GetAccountModules
+// return an empty (nil) list instead of an account_not_found error, so an
+// account that does not exist yet reads the same as one without resources.
+// Nodes answer either way for never-funded addresses, depending on their state.
+func WithTreatNotFoundAsEmpty() RequestOption {
+	return func(o *RequestOptions) {
+		o.TreatNotFoundAsEmpty = true
+	}
+}
+
+// WithTimeout overrides the client's default timeout (ClientConfig.Timeout)
+// for each request the call makes, like ContextWithRequestTimeout. A timeout
+// of zero or less disables the default timeout.
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(o *RequestOptions) {
+		o.Timeout = &timeout
+	}
+}
+
+// contextWithTimeout returns ctx with the timeout set by WithTimeout, if any,
+// for the client's requests to pick up.
+func (o *RequestOptions) contextWithTimeout(ctx context.Context) context.Context {
+	if o.Timeout == nil {
+		return ctx
+	}
+	return ContextWithRequestTimeout(ctx, *o.Timeout)
+}
+
 // BuildQueryParams builds query parameters from request options.
 func (o *RequestOptions) BuildQueryParams() string {
 	if o.LedgerVersion == nil && o.Start == nil && o.Limit == nil {
@@ -93,8 +188,12 @@ type BuildOptions struct {
 	MaxGasAmount            *uint64
 	GasUnitPrice            *uint64
 	ExpirationTimestampSecs *uint64
+	ExpirationDuration      *time.Duration // Relative alternative to ExpirationTimestampSecs
 	SequenceNumber          *uint64
-	ReplayProtectionNonce   *uint64 // For orderless transactions (mutually exclusive with SequenceNumber)
+	ChainID                 *uint8
+	ReplayProtectionNonce   *uint64     // For orderless transactions (mutually exclusive with SequenceNumber)
+	GasPriority             GasPriority // Estimate tier used when GasUnitPrice is not set
+	ReplacedGasUnitPrice    *uint64     // Gas unit price of the transaction ReplaceTransaction replaces
 }
 
 // ApplyBuildOptions applies all build options.
@@ -120,6 +219,24 @@ func WithGasUnitPrice(price uint64) BuildOption {
 	}
 }
 
+// WithReplacedGasUnitPrice gives ReplaceTransaction and CancelTransaction the
+// gas unit price of the pending transaction being replaced, so the
+// replacement is priced above it. Other builders ignore it.
+func WithReplacedGasUnitPrice(price uint64) BuildOption {
+	return func(o *BuildOptions) {
+		o.ReplacedGasUnitPrice = &price
+	}
+}
+
+// WithGasPriority selects the gas price estimate tier used when no explicit
+// gas unit price is given: GasPriorityLow, GasPriorityNormal (the default), or
+// GasPriorityHigh. WithGasUnitPrice takes precedence.
+func WithGasPriority(priority GasPriority) BuildOption {
+	return func(o *BuildOptions) {
+		o.GasPriority = priority
+	}
+}
+
 // WithExpirationTimestampSecs sets the expiration timestamp for the transaction.
 func WithExpirationTimestampSecs(timestamp uint64) BuildOption {
 	return func(o *BuildOptions) {
@@ -127,6 +244,16 @@ func WithExpirationTimestampSecs(timestamp uint64) BuildOption {
 	}
 }
 
+// WithExpirationDuration makes the transaction expire d after it is built,
+// by the local clock, instead of at an absolute time. It is mutually
+// exclusive with WithExpirationTimestampSecs, and d may not exceed
+// OrderlessMaxExpirationSeconds for orderless transactions.
+func WithExpirationDuration(d time.Duration) BuildOption {
+	return func(o *BuildOptions) {
+		o.ExpirationDuration = &d
+	}
+}
+
 // WithSequenceNumber sets the sequence number for the transaction.
 func WithSequenceNumber(seqNum uint64) BuildOption {
 	return func(o *BuildOptions) {
@@ -134,6 +261,22 @@ func WithSequenceNumber(seqNum uint64) BuildOption {
 	}
 }
 
+// WithAccountData sets the sequence number from previously fetched account
+// data, so BuildTransaction does not fetch it again.
+func WithAccountData(data AccountData) BuildOption {
+	return WithSequenceNumber(data.SequenceNumber.Uint64())
+}
+
+// WithChainID sets the chain ID, so BuildTransaction does not fetch it.
+// Together with WithSequenceNumber (or WithAccountData) and WithGasUnitPrice,
+// BuildTransaction makes no network calls. NewRawTransaction takes the chain
+// ID as an argument and ignores this option.
+func WithChainID(chainID uint8) BuildOption {
+	return func(o *BuildOptions) {
+		o.ChainID = &chainID
+	}
+}
+
 // WithReplayProtectionNonce sets the replay protection nonce for orderless transactions.
 // When set, the transaction does not depend on the account's sequence number, allowing
 // multiple transactions to be signed and submitted in any order.
@@ -144,3 +287,54 @@ func WithReplayProtectionNonce(nonce uint64) BuildOption {
 		o.ReplayProtectionNonce = &nonce
 	}
 }
+
+// BuildParams sets several transaction build parameters at once, as an
+// alternative to composing BuildOptions. A nil or zero field is filled in by
+// BuildTransactionWithParams the same way as an omitted option; a set field
+// is used as-is.
+type BuildParams struct {
+	// SequenceNumber and ReplayProtectionNonce are mutually exclusive. Set
+	// ReplayProtectionNonce to build an orderless transaction.
+	SequenceNumber        *uint64
+	ReplayProtectionNonce *uint64
+
+	GasUnitPrice uint64
+	GasPriority  GasPriority // Estimate tier used when GasUnitPrice is zero
+	MaxGasAmount uint64
+
+	// ExpirationTimestampSecs and ExpirationDuration are mutually exclusive.
+	ExpirationTimestampSecs uint64
+	ExpirationDuration      time.Duration
+
+	ChainID uint8
+}
+
+// Options returns the BuildOptions equivalent to the set fields of p.
+func (p BuildParams) Options() []BuildOption {
+	var opts []BuildOption
+	if p.SequenceNumber != nil {
+		opts = append(opts, WithSequenceNumber(*p.SequenceNumber))
+	}
+	if p.ReplayProtectionNonce != nil {
+		opts = append(opts, WithReplayProtectionNonce(*p.ReplayProtectionNonce))
+	}
+	if p.GasUnitPrice != 0 {
+		opts = append(opts, WithGasUnitPrice(p.GasUnitPrice))
+	}
+	if p.GasPriority != 0 {
+		opts = append(opts, WithGasPriority(p.GasPriority))
+	}
+	if p.MaxGasAmount != 0 {
+		opts = append(opts, WithMaxGasAmount(p.MaxGasAmount))
+	}
+	if p.ExpirationTimestampSecs != 0 {
+		opts = append(opts, WithExpirationTimestampSecs(p.ExpirationTimestampSecs))
+	}
+	if p.ExpirationDuration != 0 {
+		opts = append(opts, WithExpirationDuration(p.ExpirationDuration))
+	}
+	if p.ChainID != 0 {
+		opts = append(opts, WithChainID(p.ChainID))
+	}
+	return opts
+}
@@ -0,0 +1,31 @@
+package aptos
+
+import (
+	"context"
+	"testing"
+)
+
+func TestApplyOptionsContext(t *testing.T) {
+	ctx := ContextWithLedgerVersion(context.Background(), 100)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		opts []RequestOption
+		want string
+	}{
+		{"no version", context.Background(), nil, ""},
+		{"context version", ctx, nil, "?ledger_version=100"},
+		{"explicit version wins", ctx, []RequestOption{WithLedgerVersion(200)}, "?ledger_version=200"},
+		{"context version with limit", ctx, []RequestOption{WithLimit(10)}, "?ledger_version=100&limit=10"},
+		{"removed version", withoutLedgerVersion(ctx), nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := ApplyOptionsContext(tt.ctx, tt.opts...)
+			if got := options.BuildQueryParams(); got != tt.want {
+				t.Errorf("BuildQueryParams() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
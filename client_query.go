@@ -3,7 +3,9 @@ package aptos
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/url"
 	"strings"
 	"time"
@@ -12,6 +14,7 @@ import (
 // GetTransactions retrieves a list of transactions.
 func (c *Client) GetTransactions(ctx context.Context, opts ...RequestOption) (Response[[]Transaction], error) {
 	options := ApplyOptions(opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/transactions" + options.BuildQueryParams()
 
 	var txns []Transaction
@@ -22,7 +25,10 @@ func (c *Client) GetTransactions(ctx context.Context, opts ...RequestOption) (Re
 	return Response[[]Transaction]{Data: txns, Metadata: metadata}, nil
 }
 
-// GetTransactionByHash retrieves a transaction by its hash.
+// GetTransactionByHash retrieves a transaction by its hash. An unknown hash
+// yields the node's transaction_not_found error, which matches
+// ErrTransactionNotFound. A 404 without that error code, such as a proxy's
+// response to a wrong node URL, is returned as is and does not match it.
 func (c *Client) GetTransactionByHash(ctx context.Context, hash string) (Response[Transaction], error) {
 	path := "/transactions/by_hash/" + hash
 
@@ -34,17 +40,64 @@ func (c *Client) GetTransactionByHash(ctx context.Context, hash string) (Respons
 	return Response[Transaction]{Data: txn, Metadata: metadata}, nil
 }
 
+// GetTransactionsByHashes retrieves multiple transactions by hash, issuing at
+// most concurrency requests at a time (zero or less uses a default limit).
+// Responses are returned in the same order as hashes. If any lookup fails, its
+// response is left empty and the returned error joins the individual failures,
+// annotated with their index and hash. Use IsNotFound or errors.As with
+// *APIError to tell unknown hashes apart from transport errors. Once ctx is
+// done, the remaining hashes are not looked up and fail with ctx's error.
+func (c *Client) GetTransactionsByHashes(ctx context.Context, hashes []string, concurrency int) ([]Response[Transaction], error) {
+	responses := make([]Response[Transaction], len(hashes))
+	errs := forEachConcurrent(ctx, len(hashes), concurrency, func(i int) error {
+		var err error
+		responses[i], err = c.GetTransactionByHash(ctx, hashes[i])
+		return err
+	})
+	for i, err := range errs {
+		if err != nil {
+			errs[i] = fmt.Errorf("get transaction %d (%s): %w", i, hashes[i], err)
+		}
+	}
+	return responses, errors.Join(errs...)
+}
+
+// waitForTransactionRetryDelay is the pause before WaitForTransactionByHash
+// polls again after the node answered that the transaction is still pending.
+const waitForTransactionRetryDelay = 100 * time.Millisecond
+
 // WaitForTransactionByHash waits for a transaction to be committed.
-// This uses long-polling and will block until the transaction is committed or times out.
+// It issues long-polls, each bounded by the client's request timeout (see
+// ContextWithRequestTimeout), until the transaction is committed or ctx is
+// done. A long-poll that times out, or that returns the transaction still
+// pending, is retried, so only ctx's deadline bounds the wait; if it passes
+// first, the error wraps ErrTransactionTimeout. Other errors, such as an
+// unknown hash or a failed request, are returned immediately.
 func (c *Client) WaitForTransactionByHash(ctx context.Context, hash string) (Response[Transaction], error) {
 	path := "/transactions/wait_by_hash/" + hash
 
-	var txn Transaction
-	metadata, err := c.http.get(ctx, path, &txn)
-	if err != nil {
-		return Response[Transaction]{}, err
+	for {
+		var txn Transaction
+		metadata, err := c.http.get(ctx, path, &txn)
+		switch {
+		case err == nil && !txn.IsPending():
+			return Response[Transaction]{Data: txn, Metadata: metadata}, nil
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return Response[Transaction]{}, fmt.Errorf("%w: %s: %w", ErrTransactionTimeout, hash, ctx.Err())
+		case ctx.Err() != nil:
+			return Response[Transaction]{}, ctx.Err()
+		case err != nil && ErrorKind(err) != ErrKindTimeout:
+			return Response[Transaction]{}, err
+		case err != nil:
+			// The long-poll outlived its request timeout; poll again
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(waitForTransactionRetryDelay):
+		}
 	}
-	return Response[Transaction]{Data: txn, Metadata: metadata}, nil
 }
 
 // GetTransactionByVersion retrieves a transaction by its version.
@@ -59,9 +112,23 @@ func (c *Client) GetTransactionByVersion(ctx context.Context, version uint64) (R
 	return Response[Transaction]{Data: txn, Metadata: metadata}, nil
 }
 
+// GetTransactionByVersionBCS retrieves a transaction by its version as raw BCS bytes.
+// This is faster than GetTransactionByVersion as it skips JSON parsing.
+// Decode the response into a TransactionOnChainBCS.
+func (c *Client) GetTransactionByVersionBCS(ctx context.Context, version uint64) (BCSResponse, error) {
+	path := fmt.Sprintf("/transactions/by_version/%d", version)
+
+	data, metadata, err := c.http.getBCS(ctx, path)
+	if err != nil {
+		return BCSResponse{}, err
+	}
+	return BCSResponse{Data: data, Metadata: metadata}, nil
+}
+
 // GetAccountTransactions retrieves transactions for a specific account.
 func (c *Client) GetAccountTransactions(ctx context.Context, address AccountAddress, opts ...RequestOption) (Response[[]Transaction], error) {
 	options := ApplyOptions(opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/accounts/" + address.String() + "/transactions" + options.BuildQueryParams()
 
 	var txns []Transaction
@@ -87,6 +154,41 @@ func (c *Client) GetBlockByHeight(ctx context.Context, height uint64, withTransa
 	return Response[Block]{Data: block, Metadata: metadata}, nil
 }
 
+// GetTransactionsInBlock retrieves all transactions in the block at height,
+// in version order. The node returns at most one page of transactions with a
+// block, so the rest of a large block is fetched with GetTransactions.
+func (c *Client) GetTransactionsInBlock(ctx context.Context, height uint64) (Response[[]Transaction], error) {
+	block, err := c.GetBlockByHeight(ctx, height, true)
+	if err != nil {
+		return Response[[]Transaction]{}, err
+	}
+	txns, err := block.Data.DecodeTransactions()
+	if err != nil {
+		return Response[[]Transaction]{}, err
+	}
+
+	count := block.Data.TransactionCount()
+	for uint64(len(txns)) < count {
+		remaining := count - uint64(len(txns))
+		limit := uint16(math.MaxUint16)
+		if remaining < math.MaxUint16 {
+			limit = uint16(remaining)
+		}
+		page, err := c.GetTransactions(ctx, WithStart(block.Data.FirstVersionUint64()+uint64(len(txns))), WithLimit(limit))
+		if err != nil {
+			return Response[[]Transaction]{}, err
+		}
+		if len(page.Data) == 0 {
+			return Response[[]Transaction]{}, fmt.Errorf("block %d: got %d of %d transactions", height, len(txns), count)
+		}
+		if uint64(len(page.Data)) > remaining {
+			page.Data = page.Data[:remaining]
+		}
+		txns = append(txns, page.Data...)
+	}
+	return Response[[]Transaction]{Data: txns, Metadata: block.Metadata}, nil
+}
+
 // GetBlockByVersion retrieves a block by the ledger version it contains.
 func (c *Client) GetBlockByVersion(ctx context.Context, version uint64, withTransactions bool) (Response[Block], error) {
 	path := fmt.Sprintf("/blocks/by_version/%d", version)
@@ -103,21 +205,27 @@ func (c *Client) GetBlockByVersion(ctx context.Context, version uint64, withTran
 }
 
 // GetEventsByCreationNumber retrieves events by creation number.
+// Returns an error wrapping ErrEndpointDeprecated if the node no longer serves
+// event handle queries; an empty result means the handle has no events.
 func (c *Client) GetEventsByCreationNumber(ctx context.Context, address AccountAddress, creationNumber uint64, opts ...RequestOption) (Response[[]Event], error) {
 	options := ApplyOptions(opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := fmt.Sprintf("/accounts/%s/events/%d%s", address.String(), creationNumber, options.BuildQueryParams())
 
 	var events []Event
 	metadata, err := c.http.get(ctx, path, &events)
 	if err != nil {
-		return Response[[]Event]{}, err
+		return Response[[]Event]{}, wrapDeprecatedEndpoint(err)
 	}
 	return Response[[]Event]{Data: events, Metadata: metadata}, nil
 }
 
 // GetEventsByEventHandle retrieves events by event handle.
+// Returns an error wrapping ErrEndpointDeprecated if the node no longer serves
+// event handle queries; an empty result means the handle has no events.
 func (c *Client) GetEventsByEventHandle(ctx context.Context, address AccountAddress, eventHandle, fieldName string, opts ...RequestOption) (Response[[]Event], error) {
 	options := ApplyOptions(opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := fmt.Sprintf("/accounts/%s/events/%s/%s%s",
 		address.String(),
 		url.PathEscape(eventHandle),
@@ -127,14 +235,15 @@ func (c *Client) GetEventsByEventHandle(ctx context.Context, address AccountAddr
 	var events []Event
 	metadata, err := c.http.get(ctx, path, &events)
 	if err != nil {
-		return Response[[]Event]{}, err
+		return Response[[]Event]{}, wrapDeprecatedEndpoint(err)
 	}
 	return Response[[]Event]{Data: events, Metadata: metadata}, nil
 }
 
 // GetTableItem retrieves a table item.
 func (c *Client) GetTableItem(ctx context.Context, tableHandle string, req TableItemRequest, opts ...RequestOption) (Response[json.RawMessage], error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/tables/" + tableHandle + "/item" + options.BuildQueryParams()
 
 	var result json.RawMessage
@@ -149,7 +258,8 @@ func (c *Client) GetTableItem(ctx context.Context, tableHandle string, req Table
 // This is faster than GetTableItem as it skips JSON parsing.
 // Use bcs.Deserializer to decode the response.
 func (c *Client) GetTableItemBCS(ctx context.Context, tableHandle string, req TableItemRequest, opts ...RequestOption) (BCSResponse, error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/tables/" + tableHandle + "/item" + options.BuildQueryParams()
 
 	data, metadata, err := c.http.postJSONGetBCS(ctx, path, req)
@@ -161,7 +271,8 @@ func (c *Client) GetTableItemBCS(ctx context.Context, tableHandle string, req Ta
 
 // GetRawTableItem retrieves a raw table item.
 func (c *Client) GetRawTableItem(ctx context.Context, tableHandle string, req RawTableItemRequest, opts ...RequestOption) (Response[json.RawMessage], error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/tables/" + tableHandle + "/raw_item" + options.BuildQueryParams()
 
 	var result json.RawMessage
@@ -174,7 +285,8 @@ func (c *Client) GetRawTableItem(ctx context.Context, tableHandle string, req Ra
 
 // View executes a view function and returns the result.
 func (c *Client) View(ctx context.Context, req ViewRequest, opts ...RequestOption) (Response[[]json.RawMessage], error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/view" + options.BuildQueryParams()
 
 	var result []json.RawMessage
@@ -189,7 +301,8 @@ func (c *Client) View(ctx context.Context, req ViewRequest, opts ...RequestOptio
 // This is faster than View as it skips JSON parsing.
 // Use bcs.Deserializer to decode the response.
 func (c *Client) ViewBCS(ctx context.Context, req ViewRequest, opts ...RequestOption) (BCSResponse, error) {
-	options := ApplyOptions(opts...)
+	options := ApplyOptionsContext(ctx, opts...)
+	ctx = options.contextWithTimeout(ctx)
 	path := "/view" + options.BuildQueryParams()
 
 	data, metadata, err := c.http.postJSONGetBCS(ctx, path, req)
@@ -219,25 +332,82 @@ func (c *Client) SimulateTransaction(ctx context.Context, signedTxnBytes []byte,
 	}
 
 	var result []UserTransaction
-	metadata, err := c.http.postBCS(ctx, path, signedTxnBytes, &result)
+	metadata, err := c.submitHTTP.postBCS(ctx, path, signedTxnBytes, &result)
 	if err != nil {
 		return Response[[]UserTransaction]{}, err
 	}
 	return Response[[]UserTransaction]{Data: result, Metadata: metadata}, nil
 }
 
+// SimulationResult is the outcome of simulating one transaction in a batch.
+type SimulationResult struct {
+	Response Response[[]UserTransaction]
+	Err      error
+}
+
+// SimulateTransactions simulates multiple signed transactions concurrently.
+// Results are returned in the same order as signedTxns, each with its own
+// error. If any simulation fails, the returned error joins the individual
+// failures, annotated with their index; the results are still fully populated.
+// Once ctx is done, the remaining transactions are not sent, and their
+// results carry ctx's error.
+func (c *Client) SimulateTransactions(ctx context.Context, signedTxns [][]byte, opts ...SimulateOption) ([]SimulationResult, error) {
+	results := make([]SimulationResult, len(signedTxns))
+	simErrs := forEachConcurrent(ctx, len(signedTxns), 0, func(i int) error {
+		var err error
+		results[i].Response, err = c.SimulateTransaction(ctx, signedTxns[i], opts...)
+		return err
+	})
+
+	var errs []error
+	for i, err := range simErrs {
+		results[i].Err = err
+		if err != nil {
+			errs = append(errs, fmt.Errorf("simulate transaction %d: %w", i, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
 // SubmitTransaction submits a signed transaction.
+// With ClientConfig.TrackSubmissions, a duplicate submission returns an
+// error wrapping ErrDuplicateSubmission without contacting the node.
 func (c *Client) SubmitTransaction(ctx context.Context, signedTxnBytes []byte) (Response[PendingTransaction], error) {
 	path := "/transactions"
 
+	sub, err := c.submissions.begin(signedTxnBytes)
+	if err != nil {
+		return Response[PendingTransaction]{}, err
+	}
 	var result PendingTransaction
-	metadata, err := c.http.postBCS(ctx, path, signedTxnBytes, &result)
+	metadata, err := c.submitHTTP.postBCS(ctx, path, signedTxnBytes, &result)
 	if err != nil {
+		c.submissions.fail(sub)
 		return Response[PendingTransaction]{}, err
 	}
 	return Response[PendingTransaction]{Data: result, Metadata: metadata}, nil
 }
 
+// SubmitTransactionHash submits a signed transaction and returns only its
+// hash, computed locally. The node is asked for a BCS response, which is
+// discarded, so no PendingTransaction JSON is parsed. This suits
+// high-throughput submitters that track transactions by hash. Duplicate
+// submissions are handled as by SubmitTransaction.
+func (c *Client) SubmitTransactionHash(ctx context.Context, signedTxnBytes []byte) (Response[string], error) {
+	path := "/transactions"
+
+	sub, err := c.submissions.begin(signedTxnBytes)
+	if err != nil {
+		return Response[string]{}, err
+	}
+	_, metadata, err := c.submitHTTP.postBCSGetBCS(ctx, path, signedTxnBytes)
+	if err != nil {
+		c.submissions.fail(sub)
+		return Response[string]{}, err
+	}
+	return Response[string]{Data: signedTransactionHash(signedTxnBytes), Metadata: metadata}, nil
+}
+
 
 // SimulateOption is a function that modifies simulation options.
 type SimulateOption func(*SimulateOptions)
@@ -281,11 +451,20 @@ func WithEstimatePrioritizedGasUnitPrice() SimulateOption {
 
 // PollForTransaction polls for a transaction until it's found or the context is cancelled.
 // This is useful when long-polling is not available or times out.
+// Not-found and transient errors are retried; other errors, such as an
+// invalid hash, are returned immediately.
 func (c *Client) PollForTransaction(ctx context.Context, hash string, pollInterval time.Duration) (Response[Transaction], error) {
 	for {
+		// Don't send a request once the context is done
+		if err := ctx.Err(); err != nil {
+			return Response[Transaction]{}, err
+		}
 		txn, err := c.GetTransactionByHash(ctx, hash)
-		if err == nil && !txn.Data.IsPending() {
+		switch {
+		case err == nil && !txn.Data.IsPending():
 			return txn, nil
+		case err != nil && !IsTransactionNotFound(err) && !isTransient(err):
+			return Response[Transaction]{}, err
 		}
 
 		select {
@@ -296,3 +475,123 @@ func (c *Client) PollForTransaction(ctx context.Context, hash string, pollInterv
 		}
 	}
 }
+
+// WaitForLedgerVersion polls the node until its ledger version is at least
+// version or the context is cancelled, and returns the ledger info that
+// satisfied the condition.
+// This is useful for read-after-write consistency behind a load balancer: wait
+// for the node serving reads to catch up to the version a transaction
+// committed at before reading derived state.
+// Transient errors are retried; any other error is returned immediately. If
+// the context ends while retrying, the returned error wraps the context's error
+// and includes the last request error. pollInterval must be positive.
+func (c *Client) WaitForLedgerVersion(ctx context.Context, version uint64, pollInterval time.Duration) (Response[LedgerInfo], error) {
+	if pollInterval <= 0 {
+		return Response[LedgerInfo]{}, fmt.Errorf("poll interval must be positive, got %s", pollInterval)
+	}
+	timer := time.NewTimer(pollInterval)
+	defer timer.Stop()
+
+	var lastErr error
+	for {
+		if err := ctx.Err(); err != nil {
+			return Response[LedgerInfo]{}, pollContextError(err, lastErr)
+		}
+		info, err := c.GetLedgerInfo(ctx)
+		switch {
+		case err == nil && info.Data.LedgerVersion.Uint64() >= version:
+			return info, nil
+		case err != nil && !isTransient(err):
+			return Response[LedgerInfo]{}, err
+		case ctx.Err() == nil:
+			// A request cut short by the context says nothing new
+			lastErr = err
+		}
+
+		if err := pollSleep(ctx, timer, pollInterval); err != nil {
+			return Response[LedgerInfo]{}, pollContextError(err, lastErr)
+		}
+	}
+}
+
+// pollSleep waits for interval on timer, which is reused across polls, or
+// until the context is done, and returns the context's error in that case.
+func pollSleep(ctx context.Context, timer *time.Timer, interval time.Duration) error {
+	timer.Reset(interval)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// pollContextError returns ctxErr, noting lastErr if the last poll failed.
+// Only ctxErr is wrapped, so ErrorKind still reports a timeout or cancellation
+// rather than the kind of the last request error.
+func pollContextError(ctxErr, lastErr error) error {
+	if lastErr == nil {
+		return ctxErr
+	}
+	return fmt.Errorf("%w (last error: %v)", ctxErr, lastErr)
+}
+
+// WaitForEvent polls an account's event stream until an event satisfying
+// matcher appears or the context is cancelled, and returns that event. A nil
+// matcher accepts any event. The stream is scanned from sequence number 0
+// unless WithStart is given; pass the stream's current length to wait only
+// for new events. WithLimit sets the page size of each poll.
+// Not-found errors, such as the event handle not existing yet, and transient
+// errors are retried; any other error, including one wrapping
+// ErrEndpointDeprecated, is returned immediately. If the context
+// ends while retrying, the returned error wraps the context's error and
+// includes the last request error. pollInterval must be positive.
+func (c *Client) WaitForEvent(ctx context.Context, address AccountAddress, eventHandle, fieldName string, matcher func(Event) bool, pollInterval time.Duration, opts ...RequestOption) (Response[Event], error) {
+	if pollInterval <= 0 {
+		return Response[Event]{}, fmt.Errorf("poll interval must be positive, got %s", pollInterval)
+	}
+	options := ApplyOptions(opts...)
+	ctx = options.contextWithTimeout(ctx)
+	var start uint64
+	if options.Start != nil {
+		start = *options.Start
+	}
+	timer := time.NewTimer(pollInterval)
+	defer timer.Stop()
+
+	var lastErr error
+	for {
+		if err := ctx.Err(); err != nil {
+			return Response[Event]{}, pollContextError(err, lastErr)
+		}
+		pageOpts := []RequestOption{WithStart(start)}
+		if options.Limit != nil {
+			pageOpts = append(pageOpts, WithLimit(*options.Limit))
+		}
+		events, err := c.GetEventsByEventHandle(ctx, address, eventHandle, fieldName, pageOpts...)
+		switch {
+		case err == nil:
+			lastErr = nil
+			for _, event := range events.Data {
+				if matcher == nil || matcher(event) {
+					return Response[Event]{Data: event, Metadata: events.Metadata}, nil
+				}
+				start = event.SequenceNumberUint64() + 1
+			}
+			if len(events.Data) > 0 {
+				// More events may follow; fetch the next page right away
+				continue
+			}
+		case errors.Is(err, ErrEndpointDeprecated),
+			ErrorKind(err) != ErrKindNotFound && !isTransient(err):
+			return Response[Event]{}, err
+		case ctx.Err() == nil:
+			// A request cut short by the context says nothing new
+			lastErr = err
+		}
+
+		if err := pollSleep(ctx, timer, pollInterval); err != nil {
+			return Response[Event]{}, pollContextError(err, lastErr)
+		}
+	}
+}
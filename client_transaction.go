@@ -3,8 +3,11 @@ package aptos
 import (
 	"context"
 	"fmt"
+	"math"
 	"sync"
 	"time"
+
+	"github.com/0xbe1/aptopher/crypto"
 )
 
 // Default transaction parameters
@@ -14,6 +17,7 @@ const (
 	DefaultExpirationSeconds          = uint64(600) // 10 minutes
 	OrderlessMaxExpirationSeconds     = uint64(60)  // 60 seconds max for orderless transactions
 	OrderlessPlaceholderSequenceNum   = uint64(0xdeadbeef)
+	ReplacementGasPriceMultiplier     = uint64(2)   // Gas price bump when replacing a pending transaction
 )
 
 // BuildTransaction builds a raw transaction for the given sender and payload.
@@ -29,6 +33,9 @@ func (c *Client) BuildTransaction(ctx context.Context, sender AccountAddress, pa
 	if options.SequenceNumber != nil && options.ReplayProtectionNonce != nil {
 		return nil, fmt.Errorf("cannot specify both SequenceNumber and ReplayProtectionNonce")
 	}
+	if err := validateExpirationOptions(options); err != nil {
+		return nil, err
+	}
 
 	// Check if this is an orderless transaction
 	isOrderless := options.ReplayProtectionNonce != nil
@@ -36,7 +43,7 @@ func (c *Client) BuildTransaction(ctx context.Context, sender AccountAddress, pa
 	// Determine what needs to be fetched
 	needSequenceNumber := options.SequenceNumber == nil && !isOrderless
 	needGasPrice := options.GasUnitPrice == nil
-	needChainID := c.chainID == 0
+	needChainID := options.ChainID == nil && c.chainID == 0
 
 	// Results from concurrent fetches
 	var (
@@ -62,13 +69,14 @@ func (c *Client) BuildTransaction(ctx context.Context, sender AccountAddress, pa
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			account, err := c.GetAccount(ctx, sender)
+			// Always use the latest sequence number, even if ctx pins a ledger version
+			seqNum, err := c.GetSequenceNumber(withoutLedgerVersion(ctx), sender)
 			if err != nil {
 				setError(fmt.Errorf("failed to get account info: %w", err))
 				return
 			}
 			mu.Lock()
-			sequenceNumber = account.Data.SequenceNumberUint64()
+			sequenceNumber = seqNum
 			mu.Unlock()
 		}()
 	} else if isOrderless {
@@ -80,12 +88,10 @@ func (c *Client) BuildTransaction(ctx context.Context, sender AccountAddress, pa
 	// Fetch gas price (with cache)
 	if needGasPrice {
 		// Check cache first
-		c.gasPriceMu.RLock()
-		if time.Since(c.gasPriceCachedAt) < gasPriceCacheTTL && c.cachedGasPrice > 0 {
-			gasUnitPrice = c.cachedGasPrice
+		if cached, ok := c.cachedGasEstimate(gasPriceCacheTTL); ok && cached.GasEstimate > 0 {
+			gasUnitPrice = cached.UnitPrice(options.GasPriority)
 			needGasPrice = false
 		}
-		c.gasPriceMu.RUnlock()
 	}
 
 	if needGasPrice {
@@ -98,12 +104,8 @@ func (c *Client) BuildTransaction(ctx context.Context, sender AccountAddress, pa
 				// Use default if estimation fails (non-fatal)
 				gasUnitPrice = DefaultGasUnitPrice
 			} else {
-				gasUnitPrice = gasEstimate.Data.GasEstimate
-				// Update cache
-				c.gasPriceMu.Lock()
-				c.cachedGasPrice = gasUnitPrice
-				c.gasPriceCachedAt = time.Now()
-				c.gasPriceMu.Unlock()
+				// EstimateGasPrice updates the cache
+				gasUnitPrice = gasEstimate.Data.UnitPrice(options.GasPriority)
 			}
 			mu.Unlock()
 		}()
@@ -116,15 +118,17 @@ func (c *Client) BuildTransaction(ctx context.Context, sender AccountAddress, pa
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			ledgerInfo, err := c.GetLedgerInfo(ctx)
+			id, err := c.GetChainID(ctx)
 			if err != nil {
-				setError(fmt.Errorf("failed to get ledger info: %w", err))
+				setError(fmt.Errorf("failed to get chain ID: %w", err))
 				return
 			}
 			mu.Lock()
-			chainID = ledgerInfo.Data.ChainID
+			chainID = id
 			mu.Unlock()
 		}()
+	} else if options.ChainID != nil {
+		chainID = *options.ChainID
 	} else {
 		chainID = c.chainID
 	}
@@ -142,38 +146,44 @@ func (c *Client) BuildTransaction(ctx context.Context, sender AccountAddress, pa
 		c.chainID = chainID
 	}
 
-	// Get max gas amount
-	maxGasAmount := DefaultMaxGasAmount
-	if options.MaxGasAmount != nil {
-		maxGasAmount = *options.MaxGasAmount
-	}
+	return assembleRawTransaction(sender, payload, sequenceNumber, gasUnitPrice, chainID, options), nil
+}
 
-	// Get expiration timestamp
-	var expirationTimestampSecs uint64
-	if options.ExpirationTimestampSecs != nil {
-		expirationTimestampSecs = *options.ExpirationTimestampSecs
-	} else if isOrderless {
-		// Orderless transactions have a max expiration of 60 seconds
-		expirationTimestampSecs = uint64(time.Now().Unix()) + OrderlessMaxExpirationSeconds
-	} else {
-		expirationTimestampSecs = uint64(time.Now().Unix()) + DefaultExpirationSeconds
-	}
+// BuildTransactionWithParams builds a raw transaction like BuildTransaction,
+// taking its parameters from a BuildParams struct instead of options. Fields
+// left nil or zero are fetched or defaulted as usual.
+func (c *Client) BuildTransactionWithParams(ctx context.Context, sender AccountAddress, payload TransactionPayload, params BuildParams) (*RawTransaction, error) {
+	return c.BuildTransaction(ctx, sender, payload, params.Options()...)
+}
 
-	// For orderless transactions, wrap the payload in TransactionInnerPayloadV1
-	finalPayload := payload
-	if isOrderless {
-		finalPayload = wrapPayloadForOrderless(payload, options.ReplayProtectionNonce)
+// BuildMultiAgentTransaction builds a multi-agent transaction, whose payload
+// takes a signer for the sender and for each secondary signer, such as a swap
+// between two accounts. The secondary signers must be in the order the
+// payload's signer parameters expect them. Build options apply as in
+// BuildTransaction. Each signer signs the result with
+// RawTransactionWithData.Sign, and MultiAgentTransaction assembles their
+// authenticators.
+func (c *Client) BuildMultiAgentTransaction(ctx context.Context, sender AccountAddress, secondarySigners []AccountAddress, payload TransactionPayload, opts ...BuildOption) (*RawTransactionWithData, error) {
+	rawTxn, err := c.BuildTransaction(ctx, sender, payload, opts...)
+	if err != nil {
+		return nil, err
 	}
+	return NewMultiAgentTransaction(rawTxn, secondarySigners), nil
+}
 
-	return &RawTransaction{
-		Sender:                  sender,
-		SequenceNumber:          sequenceNumber,
-		Payload:                 finalPayload,
-		MaxGasAmount:            maxGasAmount,
-		GasUnitPrice:            gasUnitPrice,
-		ExpirationTimestampSecs: expirationTimestampSecs,
-		ChainID:                 chainID,
-	}, nil
+// BuildFeePayerTransaction builds a sponsored transaction, whose gas is paid by
+// a fee payer instead of the sender. The fee payer is left as the zero
+// address: the sender signs the result with RawTransactionWithData.Sign, and
+// the sponsor then fills in its address, signs, and assembles the transaction
+// with FeePayerSign. Build options apply as in BuildTransaction; the sender
+// needs no APT for gas. For a sponsored multi-agent transaction, wrap a
+// transaction from BuildTransaction with NewFeePayerTransaction.
+func (c *Client) BuildFeePayerTransaction(ctx context.Context, sender AccountAddress, payload TransactionPayload, opts ...BuildOption) (*RawTransactionWithData, error) {
+	rawTxn, err := c.BuildTransaction(ctx, sender, payload, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewFeePayerTransaction(rawTxn, nil, AccountAddress{}), nil
 }
 
 // wrapPayloadForOrderless wraps a transaction payload in TransactionInnerPayloadV1
@@ -229,25 +239,239 @@ func (c *Client) BuildSignAndSubmitTransaction(
 		return Response[Transaction]{}, fmt.Errorf("build transaction: %w", err)
 	}
 
+	// Sign and submit to network
+	pending, err := c.signAndSubmit(ctx, account, rawTxn)
+	if err != nil {
+		return Response[Transaction]{}, err
+	}
+
+	// Wait for confirmation
+	return c.WaitForTransactionByHash(ctx, pending.Data.Hash)
+}
+
+// TransferCoin transfers amount of a coin type from the account to another
+// account and waits for the transaction to be committed. See
+// TransferCoinPayload for the entry function used.
+func (c *Client) TransferCoin(
+	ctx context.Context,
+	account *Account,
+	coinType string,
+	to AccountAddress,
+	amount uint64,
+	opts ...BuildOption,
+) (Response[Transaction], error) {
+	payload, err := TransferCoinPayload(coinType, to, amount)
+	if err != nil {
+		return Response[Transaction]{}, err
+	}
+	return c.BuildSignAndSubmitTransaction(ctx, account, payload, opts...)
+}
+
+// TransferObject transfers an object owned by the account, such as a token,
+// to recipient and waits for the transaction to be committed. See
+// TransferObjectPayload for the entry function used.
+func (c *Client) TransferObject(
+	ctx context.Context,
+	account *Account,
+	object AccountAddress,
+	recipient AccountAddress,
+	opts ...BuildOption,
+) (Response[Transaction], error) {
+	return c.BuildSignAndSubmitTransaction(ctx, account, TransferObjectPayload(object, recipient), opts...)
+}
+
+// PingResult is the outcome of Client.Ping.
+type PingResult struct {
+	// Hash is the hash of the submitted transaction.
+	Hash string
+
+	// Success reports whether the transaction executed successfully.
+	// VMStatus holds the reason if it did not.
+	Success  bool
+	VMStatus string
+
+	// Latency is the time from building the transaction until it was
+	// committed.
+	Latency time.Duration
+}
+
+// Ping checks the full write path against the network by transferring 0 APT
+// from the account to itself and waiting for the transaction to be committed.
+// It costs only the gas of a minimal transaction. An error means the
+// transaction could not be built, signed, submitted, or confirmed; a
+// committed transaction that failed to execute is reported in the result.
+func (c *Client) Ping(ctx context.Context, account *Account, opts ...BuildOption) (PingResult, error) {
+	payload, err := TransferCoinPayload(AptosCoinType, account.Address, 0)
+	if err != nil {
+		return PingResult{}, err
+	}
+
+	start := time.Now()
+	txn, err := c.BuildSignAndSubmitTransaction(ctx, account, payload, opts...)
+	if err != nil {
+		return PingResult{}, err
+	}
+	return PingResult{
+		Hash:     txn.Data.Hash,
+		Success:  txn.Data.Success,
+		VMStatus: txn.Data.VMStatus,
+		Latency:  time.Since(start),
+	}, nil
+}
+
+// SimulatePayload builds a transaction for the account and payload and
+// simulates it without signing. Unless WithMaxGasAmount is given, the node
+// estimates the max gas amount so the simulation is not limited by the
+// default.
+func (c *Client) SimulatePayload(
+	ctx context.Context,
+	account *Account,
+	payload TransactionPayload,
+	opts ...BuildOption,
+) (Response[[]UserTransaction], error) {
+	return c.SimulatePayloadWithPublicKey(ctx, account.Address, account.Signer.PublicKey(), account.Signer.Scheme(), payload, opts...)
+}
+
+// SimulatePayloadWithPublicKey is like SimulatePayload, but only needs the
+// sender's public key and signature scheme instead of an Account. This allows
+// estimating gas for addresses whose private key is not available.
+func (c *Client) SimulatePayloadWithPublicKey(
+	ctx context.Context,
+	sender AccountAddress,
+	publicKey []byte,
+	scheme crypto.SignatureScheme,
+	payload TransactionPayload,
+	opts ...BuildOption,
+) (Response[[]UserTransaction], error) {
+	rawTxn, err := c.BuildTransaction(ctx, sender, payload, opts...)
+	if err != nil {
+		return Response[[]UserTransaction]{}, fmt.Errorf("build transaction: %w", err)
+	}
+
+	txnBytes, err := rawTxn.SimulationTransaction(publicKey, scheme).Bytes()
+	if err != nil {
+		return Response[[]UserTransaction]{}, fmt.Errorf("serialize transaction: %w", err)
+	}
+
+	var simOpts []SimulateOption
+	if ApplyBuildOptions(opts...).MaxGasAmount == nil {
+		simOpts = append(simOpts, WithEstimateMaxGasAmount())
+	}
+	return c.SimulateTransaction(ctx, txnBytes, simOpts...)
+}
+
+// signAndSubmit signs a raw transaction with the account and submits it.
+func (c *Client) signAndSubmit(ctx context.Context, account *Account, rawTxn *RawTransaction) (Response[PendingTransaction], error) {
 	// Sign the transaction
 	signedTxn, err := account.SignTransaction(rawTxn)
 	if err != nil {
-		return Response[Transaction]{}, fmt.Errorf("sign transaction: %w", err)
+		return Response[PendingTransaction]{}, fmt.Errorf("sign transaction: %w", err)
 	}
 
 	// Serialize to BCS
 	txnBytes, err := signedTxn.Bytes()
 	if err != nil {
-		return Response[Transaction]{}, fmt.Errorf("serialize transaction: %w", err)
+		return Response[PendingTransaction]{}, fmt.Errorf("serialize transaction: %w", err)
 	}
 
 	// Submit to network
 	pending, err := c.SubmitTransaction(ctx, txnBytes)
 	if err != nil {
-		return Response[Transaction]{}, fmt.Errorf("submit transaction: %w", err)
+		return Response[PendingTransaction]{}, fmt.Errorf("submit transaction: %w", err)
 	}
+	return pending, nil
+}
 
-	// Wait for confirmation
-	return c.WaitForTransactionByHash(ctx, pending.Data.Hash)
+// ReplaceTransaction replaces a pending transaction by submitting a new one
+// with the same sequence number and a higher gas unit price.
+//
+// The mempool only accepts a replacement when it pays more gas than the
+// original, so unless WithGasUnitPrice is given the gas unit price is bumped
+// to ReplacementGasPriceMultiplier times the current estimate (or the
+// prioritized estimate, whichever is higher). The original's price is not
+// known to the client: pass it with WithReplacedGasUnitPrice to price the
+// replacement at least one above it. Without it, a higher price than the
+// original is not guaranteed, and a transaction sent with a high
+// WithGasUnitPrice may not be replaced.
+//
+// The pending replacement is returned without waiting for it to commit, since
+// the original transaction may still win the race.
+func (c *Client) ReplaceTransaction(
+	ctx context.Context,
+	account *Account,
+	sequenceNumber uint64,
+	payload TransactionPayload,
+	opts ...BuildOption,
+) (Response[PendingTransaction], error) {
+	options := ApplyBuildOptions(opts...)
+	if options.ReplayProtectionNonce != nil {
+		return Response[PendingTransaction]{}, fmt.Errorf("cannot replace an orderless transaction")
+	}
+
+	// Explicit sequence number and gas price take precedence over caller options
+	buildOpts := make([]BuildOption, 0, len(opts)+2)
+	buildOpts = append(buildOpts, opts...)
+	buildOpts = append(buildOpts, WithSequenceNumber(sequenceNumber))
+	if options.GasUnitPrice == nil {
+		gasUnitPrice, err := c.replacementGasUnitPrice(ctx, options.ReplacedGasUnitPrice)
+		if err != nil {
+			return Response[PendingTransaction]{}, err
+		}
+		buildOpts = append(buildOpts, WithGasUnitPrice(gasUnitPrice))
+	} else if replaced := options.ReplacedGasUnitPrice; replaced != nil && *options.GasUnitPrice <= *replaced {
+		return Response[PendingTransaction]{}, fmt.Errorf("gas unit price %d is not above the replaced transaction's %d", *options.GasUnitPrice, *replaced)
+	}
+
+	rawTxn, err := c.BuildTransaction(ctx, account.Address, payload, buildOpts...)
+	if err != nil {
+		return Response[PendingTransaction]{}, fmt.Errorf("build transaction: %w", err)
+	}
+	return c.signAndSubmit(ctx, account, rawTxn)
+}
+
+// CancelTransaction cancels a pending transaction by replacing it with a
+// transfer of 0 APT from the account to itself at the same sequence number.
+// See ReplaceTransaction for how the gas unit price is chosen.
+func (c *Client) CancelTransaction(
+	ctx context.Context,
+	account *Account,
+	sequenceNumber uint64,
+	opts ...BuildOption,
+) (Response[PendingTransaction], error) {
+	payload := TransactionPayload{
+		Payload: &EntryFunction{
+			Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+			Function: "transfer",
+			Args: EntryFunctionArgs(
+				AddressArg(account.Address),
+				U64Arg(0),
+			),
+		},
+	}
+	return c.ReplaceTransaction(ctx, account, sequenceNumber, payload, opts...)
+}
+
+// replacementGasUnitPrice returns a gas unit price high enough to replace a
+// transaction that was submitted at the current estimate, or at replaced if
+// it is known and higher.
+func (c *Client) replacementGasUnitPrice(ctx context.Context, replaced *uint64) (uint64, error) {
+	if replaced != nil && *replaced == math.MaxUint64 {
+		return 0, fmt.Errorf("no gas unit price is above the replaced transaction's %d", *replaced)
+	}
+	estimate, err := c.EstimateGasPrice(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("estimate gas price: %w", err)
+	}
+	price := estimate.Data.GasEstimate * ReplacementGasPriceMultiplier
+	if estimate.Data.PrioritizedGasEstimate > price {
+		price = estimate.Data.PrioritizedGasEstimate
+	}
+	if price == 0 {
+		price = DefaultGasUnitPrice * ReplacementGasPriceMultiplier
+	}
+	if replaced != nil {
+		price = max(price, *replaced+1)
+	}
+	return price, nil
 }
 
@@ -0,0 +1,124 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CoinInfoResource is the data of a legacy coin's 0x1::coin::CoinInfo<T>
+// resource, stored at the address that deployed the coin type.
+type CoinInfoResource struct {
+	Name     string
+	Symbol   string
+	Decimals uint8
+
+	// Supply is the total supply, or nil if the supply is not tracked or is
+	// kept in an aggregator (as for APT), whose value is not stored in the
+	// resource.
+	Supply *U128
+}
+
+// UnmarshalJSON implements json.Unmarshaler for the resource data.
+func (i *CoinInfoResource) UnmarshalJSON(data []byte) error {
+	// supply is an Option<OptionalAggregator>, and OptionalAggregator holds
+	// either an aggregator or an integer, each as an Option.
+	var raw struct {
+		Name     string `json:"name"`
+		Symbol   string `json:"symbol"`
+		Decimals uint8  `json:"decimals"`
+		Supply   struct {
+			Vec []struct {
+				Integer struct {
+					Vec []struct {
+						Value U128 `json:"value"`
+					} `json:"vec"`
+				} `json:"integer"`
+			} `json:"vec"`
+		} `json:"supply"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*i = CoinInfoResource{Name: raw.Name, Symbol: raw.Symbol, Decimals: raw.Decimals}
+	if len(raw.Supply.Vec) > 0 && len(raw.Supply.Vec[0].Integer.Vec) > 0 {
+		supply := raw.Supply.Vec[0].Integer.Vec[0].Value
+		i.Supply = &supply
+	}
+	return nil
+}
+
+// CoinStoreResource is the data of a 0x1::coin::CoinStore<T> resource, which
+// holds an account's balance of a legacy coin.
+type CoinStoreResource struct {
+	Coin struct {
+		Value JSONUint64 `json:"value"`
+	} `json:"coin"`
+	Frozen bool `json:"frozen"`
+
+	DepositEvents  EventHandle `json:"deposit_events"`
+	WithdrawEvents EventHandle `json:"withdraw_events"`
+}
+
+// TransferCoinPayload returns a payload that transfers amount of a coin type,
+// such as "0x1::aptos_coin::AptosCoin", to an account using
+// 0x1::aptos_account::transfer_coins<CoinType>. Unlike 0x1::coin::transfer,
+// it registers the coin store for the recipient if needed.
+func TransferCoinPayload(coinType string, to AccountAddress, amount uint64) (TransactionPayload, error) {
+	tag, err := ParseTypeTag(strings.TrimSpace(coinType))
+	if err != nil {
+		return TransactionPayload{}, fmt.Errorf("invalid coin type: %w", err)
+	}
+	if _, ok := tag.Value.(*StructTag); !ok {
+		return TransactionPayload{}, fmt.Errorf("invalid coin type %s: not a struct", coinType)
+	}
+
+	return TransactionPayload{
+		Payload: &EntryFunction{
+			Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+			Function: "transfer_coins",
+			TypeArgs: []TypeTag{tag},
+			Args: EntryFunctionArgs(
+				AddressArg(to),
+				U64Arg(amount),
+			),
+		},
+	}, nil
+}
+
+// CoinTypeParts splits a coin type, such as "0x1::aptos_coin::AptosCoin",
+// into the address that deployed it, its module, and its struct name.
+func CoinTypeParts(coinType string) (deployer AccountAddress, module, name string, err error) {
+	coinType = strings.TrimSpace(coinType)
+	tag, err := ParseTypeTag(coinType)
+	if err != nil {
+		return AccountAddress{}, "", "", fmt.Errorf("invalid coin type: %w", err)
+	}
+	structTag, ok := tag.Value.(*StructTag)
+	if !ok {
+		return AccountAddress{}, "", "", fmt.Errorf("invalid coin type %s: not a struct", coinType)
+	}
+	return structTag.Address, structTag.Module, structTag.Name, nil
+}
+
+// GetCoinInfo retrieves the CoinInfo of a legacy coin type, such as
+// "0x1::aptos_coin::AptosCoin", from the address in the coin type.
+func (c *Client) GetCoinInfo(ctx context.Context, coinType string, opts ...RequestOption) (CoinInfoResource, error) {
+	coinType = strings.TrimSpace(coinType)
+	deployer, _, _, err := CoinTypeParts(coinType)
+	if err != nil {
+		return CoinInfoResource{}, err
+	}
+
+	resource, err := c.GetAccountResource(ctx, deployer, "0x1::coin::CoinInfo<"+coinType+">", opts...)
+	if err != nil {
+		return CoinInfoResource{}, err
+	}
+	var info CoinInfoResource
+	if err := resource.Data.DecodeData(&info); err != nil {
+		return CoinInfoResource{}, fmt.Errorf("decode coin info: %w", err)
+	}
+	return info, nil
+}
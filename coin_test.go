@@ -0,0 +1,110 @@
+package aptos
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestCoinInfoResourceUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantSupply string // empty for nil
+	}{
+		{
+			name: "aggregator supply",
+			data: `{"decimals":8,"name":"Aptos Coin","symbol":"APT","supply":{"vec":[{"aggregator":{"vec":[{"handle":"0x1","key":"0x2","limit":"340282366920938463463374607431768211455"}]},"integer":{"vec":[]}}]}}`,
+		},
+		{
+			name:       "integer supply",
+			data:       `{"decimals":6,"name":"USD Coin","symbol":"USDC","supply":{"vec":[{"aggregator":{"vec":[]},"integer":{"vec":[{"limit":"340282366920938463463374607431768211455","value":"1000000"}]}}]}}`,
+			wantSupply: "1000000",
+		},
+		{
+			name: "untracked supply",
+			data: `{"decimals":6,"name":"USD Coin","symbol":"USDC","supply":{"vec":[]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info CoinInfoResource
+			if err := json.Unmarshal([]byte(tt.data), &info); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if info.Name == "" || info.Symbol == "" || info.Decimals == 0 {
+				t.Errorf("missing fields: %+v", info)
+			}
+			switch {
+			case tt.wantSupply == "" && info.Supply != nil:
+				t.Errorf("Supply = %s, want nil", info.Supply)
+			case tt.wantSupply != "" && (info.Supply == nil || info.Supply.String() != tt.wantSupply):
+				t.Errorf("Supply = %v, want %s", info.Supply, tt.wantSupply)
+			}
+		})
+	}
+}
+
+func TestTransferCoinPayload(t *testing.T) {
+	to := MustParseAccountAddress("0xcafe")
+	payload, err := TransferCoinPayload("0x1::aptos_coin::AptosCoin", to, 500)
+	if err != nil {
+		t.Fatalf("TransferCoinPayload error: %v", err)
+	}
+	entryFunction, ok := payload.Payload.(*EntryFunction)
+	if !ok {
+		t.Fatalf("payload is %T, want *EntryFunction", payload.Payload)
+	}
+	if entryFunction.Module.Address != AccountOne || entryFunction.Module.Name != "aptos_account" || entryFunction.Function != "transfer_coins" {
+		t.Errorf("unexpected function %s::%s", entryFunction.Module.Name, entryFunction.Function)
+	}
+	if len(entryFunction.TypeArgs) != 1 || entryFunction.TypeArgs[0].String() != "0x1::aptos_coin::AptosCoin" {
+		t.Errorf("TypeArgs = %v, want [0x1::aptos_coin::AptosCoin]", entryFunction.TypeArgs)
+	}
+	wantArgs := EntryFunctionArgs(AddressArg(to), U64Arg(500))
+	if len(entryFunction.Args) != len(wantArgs) {
+		t.Fatalf("got %d args, want %d", len(entryFunction.Args), len(wantArgs))
+	}
+	for i := range wantArgs {
+		if !bytes.Equal(entryFunction.Args[i], wantArgs[i]) {
+			t.Errorf("Args[%d] = %x, want %x", i, entryFunction.Args[i], wantArgs[i])
+		}
+	}
+
+	for _, coinType := range []string{"", "u64", "0x1::aptos_coin"} {
+		if _, err := TransferCoinPayload(coinType, to, 500); err == nil {
+			t.Errorf("TransferCoinPayload(%q): expected error", coinType)
+		}
+	}
+}
+
+func TestCoinTypeParts(t *testing.T) {
+	tests := []struct {
+		coinType     string
+		wantDeployer string
+		wantModule   string
+		wantName     string
+		wantErr      bool
+	}{
+		{coinType: "0x1::aptos_coin::AptosCoin", wantDeployer: "0x1", wantModule: "aptos_coin", wantName: "AptosCoin"},
+		{coinType: " 0xbae::usdc::USDC ", wantDeployer: "0xbae", wantModule: "usdc", wantName: "USDC"},
+		{coinType: "0xcafe::lp::LP<0x1::aptos_coin::AptosCoin>", wantDeployer: "0xcafe", wantModule: "lp", wantName: "LP"},
+		{coinType: "u64", wantErr: true},
+		{coinType: "not a type", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.coinType, func(t *testing.T) {
+			deployer, module, name, err := CoinTypeParts(tt.coinType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CoinTypeParts error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if deployer != MustParseAccountAddress(tt.wantDeployer) || module != tt.wantModule || name != tt.wantName {
+				t.Errorf("CoinTypeParts = %s, %s, %s", deployer, module, name)
+			}
+		})
+	}
+}
@@ -10,25 +10,111 @@ type ClientConfig struct {
 	// NodeURL is the URL of the Aptos node REST API.
 	NodeURL string
 
+	// SubmitNodeURL is an optional URL of a separate node REST API that
+	// transactions are submitted to and simulated on (SubmitTransaction,
+	// SubmitTransactionHash, and SimulateTransaction, and the helpers built on
+	// them). All other requests go to NodeURL. Both nodes must be on the same
+	// network. If empty, NodeURL is used for everything.
+	SubmitNodeURL string
+
 	// HTTPClient is an optional custom HTTP client.
-	// If nil, a default client with 30 second timeout is used.
+	// If nil, a default client is used. A Timeout set on a custom client caps
+	// every request, including calls given a longer WithTimeout or
+	// ContextWithRequestTimeout.
 	HTTPClient *http.Client
 
-	// Timeout is the default timeout for API requests.
-	// If zero, defaults to 30 seconds.
+	// Transport is an optional RoundTripper for the default HTTP client, for
+	// wrapping requests with logging, caching, circuit breaking, or auth.
+	// Wrappers should delegate to http.DefaultTransport (or another
+	// RoundTripper) and return the response unmodified, since the client
+	// reads response metadata from the X-Aptos-* headers.
+	// Ignored if HTTPClient is set; set HTTPClient.Transport instead.
+	Transport http.RoundTripper
+
+	// Timeout is the default timeout for each API request. It can be
+	// overridden per call with the WithTimeout request option or
+	// ContextWithRequestTimeout, for example to give a large module fetch
+	// more time than quick reads.
+	// WaitForTransactionByHash retries long-polls that exceed it.
+	// If zero, defaults to 30 seconds, or to no timeout beyond the context
+	// and the client's own Timeout when HTTPClient is set.
+	//
+	// The timeout is applied to each request's context, not set as the
+	// default HTTP client's http.Client.Timeout as in earlier versions. It
+	// still covers reading the response body; a request that is retried
+	// gets the full timeout for each attempt.
 	Timeout time.Duration
+
+	// RespectRateLimit delays requests once the rate-limit headers of a
+	// response report that no requests remain in the current window, until
+	// the window resets, instead of sending them and receiving 429 errors.
+	// Has no effect against nodes that do not send rate-limit headers.
+	RespectRateLimit bool
+
+	// DeduplicateReads collapses identical concurrent reads (GET requests
+	// and view function calls with the same path and body) into a single
+	// request whose response is shared by all callers, reducing load from
+	// bursty, overlapping reads. A caller whose context is canceled stops
+	// waiting, but the shared request continues for the others.
+	DeduplicateReads bool
+
+	// MaxRetries is the number of times a failed request is retried when it
+	// was rate limited (429), failed on the server (5xx), or did not reach
+	// the node. Only requests that are safe to repeat are retried: GET
+	// requests, view functions, table item reads, and simulations.
+	// Submissions are never retried, since a failed response does not prove
+	// the transaction was rejected. If zero, requests are not retried.
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubled for each
+	// later retry up to 30 seconds. A Retry-After header from the node takes
+	// precedence, but is also capped at 30 seconds: with DeduplicateReads, a
+	// shared read keeps retrying after its callers give up, so a long
+	// Retry-After would hold it open. If zero, defaults to 200 milliseconds.
+	RetryBackoff time.Duration
+
+	// RetryJitter randomizes the delays between retries, so that clients
+	// failing together do not retry together: each delay is reduced by a
+	// random fraction of up to RetryJitter, between 0 and 1.
+	RetryJitter float64
+
+	// TrackSubmissions makes the client remember the transactions submitted
+	// through it until they expire, and reject a resubmission of the same
+	// signed transaction, or of another one from the same sender with the
+	// same sequence number and no higher gas unit price, with
+	// ErrDuplicateSubmission instead of sending it to the node. This guards
+	// at-least-once pipelines against retries that submit a transaction
+	// twice. Replacements with a higher gas unit price, as sent by
+	// ReplaceTransaction, are allowed.
+	TrackSubmissions bool
+
+	// ANSAddress is the address of the Aptos Name Service router contract,
+	// used by ResolveName and ReverseLookup. It is set in MainnetConfig and
+	// TestnetConfig; if zero, name resolution is unavailable.
+	ANSAddress AccountAddress
 }
 
+// ANS router contract addresses.
+var (
+	// MainnetANSAddress is the Aptos Name Service router on mainnet.
+	MainnetANSAddress = MustParseAccountAddress("0x867ed1f6bf916171b1de3ee92849b8978b7d1b9e0a8cc982a3d19d535dfd9c0c")
+
+	// TestnetANSAddress is the Aptos Name Service router on testnet.
+	TestnetANSAddress = MustParseAccountAddress("0x5f8fd2347449685cf41d4db97926ec3a096eaf381332be4f1318ad4d16a8497c")
+)
+
 // Predefined network configurations.
 var (
 	// MainnetConfig is the configuration for Aptos mainnet.
 	MainnetConfig = ClientConfig{
-		NodeURL: "https://fullnode.mainnet.aptoslabs.com/v1",
+		NodeURL:    "https://fullnode.mainnet.aptoslabs.com/v1",
+		ANSAddress: MainnetANSAddress,
 	}
 
 	// TestnetConfig is the configuration for Aptos testnet.
 	TestnetConfig = ClientConfig{
-		NodeURL: "https://fullnode.testnet.aptoslabs.com/v1",
+		NodeURL:    "https://fullnode.testnet.aptoslabs.com/v1",
+		ANSAddress: TestnetANSAddress,
 	}
 
 	// DevnetConfig is the configuration for Aptos devnet.
@@ -0,0 +1,141 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Type strings used when finding the addresses created by a transaction.
+const (
+	accountResourceType    = "0x1::account::Account"
+	objectCoreResourceType = "0x1::object::ObjectCore"
+)
+
+// TokenMintEventType is the event emitted when a token is minted into a
+// collection. Its "token" field holds the new token's object address, so
+// CreatedObjectAddresses(TokenMintEventType, "token") finds the minted tokens.
+const TokenMintEventType = "0x4::collection::Mint"
+
+// WriteSetChanges decodes the state changes made by a committed transaction.
+func (t *Transaction) WriteSetChanges() ([]WriteSetChange, error) {
+	return decodeWriteSetChanges(t.Changes)
+}
+
+// CreatedResourceAccountAddress returns the address of the resource account
+// created by txn for source with seed, as with
+// 0x1::account::create_resource_account or
+// 0x1::resource_account::create_resource_account. The address is derived
+// with CreateResourceAddress. The transaction must have written the account
+// at it, and the account must not have existed before the transaction.
+func (c *Client) CreatedResourceAccountAddress(ctx context.Context, txn *Transaction, source AccountAddress, seed []byte) (AccountAddress, error) {
+	address := CreateResourceAddress(source, seed)
+	if err := c.requireCreatedResource(ctx, txn, address, accountResourceType); err != nil {
+		return AccountAddress{}, fmt.Errorf("resource account %s: %w", address, err)
+	}
+	return address, nil
+}
+
+// CreatedNamedObjectAddress returns the address of the named object created
+// by txn for creator with seed, as with 0x1::object::create_named_object. The
+// address is derived with CreateObjectAddress. The transaction must have
+// written the object at it, and the object must not have existed before the
+// transaction, so a transaction that only transfers the object does not count.
+func (c *Client) CreatedNamedObjectAddress(ctx context.Context, txn *Transaction, creator AccountAddress, seed []byte) (AccountAddress, error) {
+	address := CreateObjectAddress(creator, seed)
+	if err := c.requireCreatedResource(ctx, txn, address, objectCoreResourceType); err != nil {
+		return AccountAddress{}, fmt.Errorf("named object %s: %w", address, err)
+	}
+	return address, nil
+}
+
+// CreatedObjectAddresses returns the addresses of objects whose creation is
+// announced by events of eventType, read from the event field named field, in
+// event order. This covers objects with addresses that cannot be derived in
+// advance, such as those from 0x1::object::create_object. The field may hold
+// an address or an Object<T>. The event itself is taken as proof of creation,
+// so eventType must be emitted only when an object is created, such as
+// TokenMintEventType; a transfer event would report transferred objects.
+func (t *Transaction) CreatedObjectAddresses(eventType, field string) ([]AccountAddress, error) {
+	if t.IsPending() {
+		return nil, fmt.Errorf("transaction %s is pending", t.Hash)
+	}
+
+	var addresses []AccountAddress
+	for i := range t.Events {
+		event := &t.Events[i]
+		if event.Type != eventType {
+			continue
+		}
+		address, err := eventAddressField(event, field)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// requireCreatedResource returns an error unless txn wrote the resource of
+// the given type at address and the resource did not exist before it. The
+// JSON write set does not distinguish creating a resource from modifying it,
+// so the resource is looked up at the version before the transaction.
+func (c *Client) requireCreatedResource(ctx context.Context, txn *Transaction, address AccountAddress, resourceType string) error {
+	if txn.IsPending() {
+		return fmt.Errorf("transaction %s is pending", txn.Hash)
+	}
+	changes, err := txn.WriteSetChanges()
+	if err != nil {
+		return err
+	}
+	written := false
+	for i := range changes {
+		change := &changes[i]
+		if change.Type == WriteSetChangeWriteResource && change.Address == address && change.ResourceType() == resourceType {
+			written = true
+			break
+		}
+	}
+	if !written {
+		return fmt.Errorf("transaction %s did not write %s", txn.Hash, resourceType)
+	}
+
+	// Nothing exists before genesis
+	if txn.Version == 0 {
+		return nil
+	}
+	_, err = c.GetAccountResource(ctx, address, resourceType, WithLedgerVersion(txn.Version.Uint64()-1))
+	switch {
+	case err == nil:
+		return fmt.Errorf("%s existed before transaction %s", resourceType, txn.Hash)
+	case IsResourceNotFound(err) || IsAccountNotFound(err):
+		return nil
+	default:
+		return fmt.Errorf("check %s before transaction %s: %w", resourceType, txn.Hash, err)
+	}
+}
+
+// eventAddressField decodes the address in the named field of an event's
+// data, which is either an address string or an Object<T> ({"inner": address}).
+func eventAddressField(event *Event, field string) (AccountAddress, error) {
+	var data map[string]json.RawMessage
+	if err := event.DecodeData(&data); err != nil {
+		return AccountAddress{}, fmt.Errorf("decode %s event: %w", event.Type, err)
+	}
+	raw, ok := data[field]
+	if !ok {
+		return AccountAddress{}, fmt.Errorf("%s event has no field %q", event.Type, field)
+	}
+
+	var address AccountAddress
+	if err := json.Unmarshal(raw, &address); err == nil {
+		return address, nil
+	}
+	var object struct {
+		Inner AccountAddress `json:"inner"`
+	}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return AccountAddress{}, fmt.Errorf("%s event field %q is not an address or object: %w", event.Type, field, err)
+	}
+	return object.Inner, nil
+}
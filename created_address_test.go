@@ -0,0 +1,145 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreatedAddresses(t *testing.T) {
+	creator := MustParseAccountAddress("0xa11ce")
+	resourceAccount := CreateResourceAddress(creator, []byte("vault"))
+	namedObject := CreateObjectAddress(creator, []byte("collection"))
+	transferred := CreateObjectAddress(creator, []byte("transferred"))
+	token := MustParseAccountAddress("0x7043")
+
+	// The node holds the transferred object before either transaction and
+	// nothing else.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if got := r.URL.Query().Get("ledger_version"); got != "99" {
+			t.Errorf("ledger_version = %q, want 99", got)
+		}
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/accounts/"+transferred.String()+"/"):
+			w.Write([]byte(`{"type":"0x1::object::ObjectCore","data":{"owner":"` + creator.String() + `"}}`))
+		case strings.HasPrefix(r.URL.Path, "/accounts/"+resourceAccount.String()+"/"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Account not found","error_code":"account_not_found"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Resource not found","error_code":"resource_not_found"}`))
+		}
+	}))
+	defer server.Close()
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+
+	decode := func(txnJSON string) *Transaction {
+		var txn Transaction
+		if err := json.Unmarshal([]byte(txnJSON), &txn); err != nil {
+			t.Fatalf("json.Unmarshal error: %v", err)
+		}
+		return &txn
+	}
+	created := decode(`{
+		"type": "user_transaction",
+		"hash": "0x1",
+		"version": "100",
+		"sender": "` + creator.String() + `",
+		"success": true,
+		"changes": [
+			{"type": "write_resource", "address": "` + resourceAccount.String() + `",
+			 "data": {"type": "0x1::account::Account", "data": {"sequence_number": "0"}}},
+			{"type": "write_resource", "address": "` + namedObject.String() + `",
+			 "data": {"type": "0x1::object::ObjectCore", "data": {"owner": "` + creator.String() + `"}}},
+			{"type": "write_resource", "address": "` + token.String() + `",
+			 "data": {"type": "0x1::object::ObjectCore", "data": {"owner": "` + creator.String() + `"}}}
+		],
+		"events": [
+			{"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0",
+			 "type": "0x4::collection::Mint", "data": {"collection": "` + namedObject.String() + `", "token": "` + token.String() + `"}},
+			{"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0",
+			 "type": "0xcafe::factory::Created", "data": {"object": {"inner": "` + token.String() + `"}}}
+		]
+	}`)
+	// A transfer writes ObjectCore too, but creates nothing
+	transfer := decode(`{
+		"type": "user_transaction",
+		"hash": "0x2",
+		"version": "100",
+		"sender": "` + creator.String() + `",
+		"success": true,
+		"changes": [
+			{"type": "write_resource", "address": "` + transferred.String() + `",
+			 "data": {"type": "0x1::object::ObjectCore", "data": {"owner": "0xb0b"}}}
+		],
+		"events": [
+			{"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0",
+			 "type": "0x1::object::Transfer", "data": {"object": "` + transferred.String() + `", "from": "` + creator.String() + `", "to": "0xb0b"}}
+		]
+	}`)
+
+	got, err := client.CreatedResourceAccountAddress(ctx, created, creator, []byte("vault"))
+	if err != nil || got != resourceAccount {
+		t.Errorf("CreatedResourceAccountAddress = %s, %v, want %s", got, err, resourceAccount)
+	}
+	if _, err := client.CreatedResourceAccountAddress(ctx, created, creator, []byte("other")); err == nil {
+		t.Error("CreatedResourceAccountAddress with an unused seed should fail")
+	}
+	got, err = client.CreatedNamedObjectAddress(ctx, created, creator, []byte("collection"))
+	if err != nil || got != namedObject {
+		t.Errorf("CreatedNamedObjectAddress = %s, %v, want %s", got, err, namedObject)
+	}
+	if _, err := client.CreatedNamedObjectAddress(ctx, created, creator, []byte("vault")); err == nil {
+		t.Error("CreatedNamedObjectAddress of a resource account should fail")
+	}
+	if got, err := client.CreatedNamedObjectAddress(ctx, transfer, creator, []byte("transferred")); err == nil {
+		t.Errorf("CreatedNamedObjectAddress of a transfer = %s, want an error", got)
+	}
+
+	tests := []struct {
+		name      string
+		txn       *Transaction
+		eventType string
+		field     string
+		want      []AccountAddress
+		wantErr   bool
+	}{
+		{"mint", created, TokenMintEventType, "token", []AccountAddress{token}, false},
+		{"object field", created, "0xcafe::factory::Created", "object", []AccountAddress{token}, false},
+		{"no events", created, "0xcafe::factory::Burned", "object", nil, false},
+		{"missing field", created, TokenMintEventType, "object", nil, true},
+		{"transfer only", transfer, TokenMintEventType, "token", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.txn.CreatedObjectAddresses(tt.eventType, tt.field)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreatedObjectAddresses error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("CreatedObjectAddresses = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("CreatedObjectAddresses[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	pending := &Transaction{Type: TransactionTypePending, Hash: "0x3"}
+	if _, err := pending.CreatedObjectAddresses(TokenMintEventType, "token"); err == nil {
+		t.Error("CreatedObjectAddresses of a pending transaction should fail")
+	}
+	if _, err := client.CreatedNamedObjectAddress(ctx, pending, creator, []byte("collection")); err == nil {
+		t.Error("CreatedNamedObjectAddress of a pending transaction should fail")
+	}
+}
@@ -0,0 +1,87 @@
+package crypto
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+// bip39EnglishWords is the BIP39 English wordlist, one word per line.
+//
+//go:embed bip39_english.txt
+var bip39EnglishWords string
+
+// bip39WordIndex maps each word of the English wordlist to its index.
+var bip39WordIndex = func() map[string]int {
+	words := strings.Fields(bip39EnglishWords)
+	index := make(map[string]int, len(words))
+	for i, word := range words {
+		index[word] = i
+	}
+	return index
+}()
+
+// ValidateMnemonic checks that mnemonic is a BIP39 mnemonic from the English
+// wordlist: 12, 15, 18, 21, or 24 known words with a valid checksum.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := mnemonicEntropy(mnemonic)
+	return err
+}
+
+// mnemonicEntropy returns the entropy encoded by a mnemonic, verifying its
+// checksum.
+func mnemonicEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("invalid mnemonic: %d words, want 12, 15, 18, 21, or 24", len(words))
+	}
+
+	// Each word encodes 11 bits: the entropy followed by a checksum of one
+	// bit per 32 bits of entropy.
+	bits := make([]byte, 0, len(words)*11)
+	for i, word := range words {
+		index, ok := bip39WordIndex[strings.ToLower(word)]
+		if !ok {
+			return nil, fmt.Errorf("invalid mnemonic: word %d (%q) is not in the BIP39 English wordlist", i+1, word)
+		}
+		for b := 10; b >= 0; b-- {
+			bits = append(bits, byte(index>>b)&1)
+		}
+	}
+	checksumBits := len(bits) / 33
+	entropy := make([]byte, (len(bits)-checksumBits)/8)
+	for i := range entropy {
+		for _, bit := range bits[i*8 : i*8+8] {
+			entropy[i] = entropy[i]<<1 | bit
+		}
+	}
+
+	hash := sha256.Sum256(entropy)
+	for i, bit := range bits[len(entropy)*8:] {
+		if hash[0]>>(7-i)&1 != bit {
+			return nil, fmt.Errorf("invalid mnemonic: checksum mismatch")
+		}
+	}
+	return entropy, nil
+}
+
+// MnemonicToSeed derives the 64-byte BIP39 seed of a mnemonic and optional
+// passphrase: PBKDF2-HMAC-SHA512 over the mnemonic with the salt "mnemonic"
+// followed by the passphrase, in 2048 iterations. It does not validate the
+// mnemonic; see ValidateMnemonic. Words are joined by single spaces and
+// lowercased, which for the English wordlist matches the NFKD normalization
+// BIP39 requires. It fails only in FIPS 140-only mode, whose PBKDF2 rejects
+// salts shorter than 16 bytes such as an empty passphrase's.
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(mnemonic), " "))
+	seed, err := pbkdf2.Key(sha512.New, normalized, []byte("mnemonic"+passphrase), 2048, 64)
+	if err != nil {
+		return nil, fmt.Errorf("derive BIP39 seed: %w", err)
+	}
+	return seed, nil
+}
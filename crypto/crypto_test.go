@@ -2,6 +2,8 @@ package crypto
 
 import (
 	"bytes"
+	"encoding/hex"
+	"strings"
 	"testing"
 )
 
@@ -142,6 +144,51 @@ func TestSecp256k1FromBytes(t *testing.T) {
 	}
 }
 
+func TestSecp256k1Recover(t *testing.T) {
+	priv, err := GenerateSecp256k1PrivateKey()
+	if err != nil {
+		t.Fatalf("GenerateSecp256k1PrivateKey error: %v", err)
+	}
+	signer := priv.Signer().(*Secp256k1Signer)
+
+	for _, message := range []string{"test message", "another message", ""} {
+		sig, recoveryID, err := signer.SignRecoverable([]byte(message))
+		if err != nil {
+			t.Fatalf("SignRecoverable error: %v", err)
+		}
+		plain, err := signer.Sign([]byte(message))
+		if err != nil {
+			t.Fatalf("Sign error: %v", err)
+		}
+		if !bytes.Equal(sig, plain) {
+			t.Errorf("SignRecoverable(%q) signature differs from Sign", message)
+		}
+
+		recovered, err := RecoverSecp256k1PublicKey([]byte(message), sig, recoveryID)
+		if err != nil {
+			t.Fatalf("RecoverSecp256k1PublicKey error: %v", err)
+		}
+		if !bytes.Equal(recovered, priv.PublicKey()) {
+			t.Errorf("RecoverSecp256k1PublicKey(%q) = %x, want %x", message, recovered, priv.PublicKey())
+		}
+
+		// The wrong recovery ID or message yields some other key, or none
+		if other, err := RecoverSecp256k1PublicKey([]byte(message), sig, recoveryID^1); err == nil && bytes.Equal(other, priv.PublicKey()) {
+			t.Errorf("RecoverSecp256k1PublicKey(%q) with flipped recovery ID returned the signer's key", message)
+		}
+		if other, err := RecoverSecp256k1PublicKey([]byte("tampered"), sig, recoveryID); err == nil && bytes.Equal(other, priv.PublicKey()) {
+			t.Errorf("RecoverSecp256k1PublicKey of a tampered message returned the signer's key")
+		}
+	}
+
+	if _, err := RecoverSecp256k1PublicKey([]byte("m"), make([]byte, 63), 0); err == nil {
+		t.Error("RecoverSecp256k1PublicKey should reject a short signature")
+	}
+	if _, err := RecoverSecp256k1PublicKey([]byte("m"), make([]byte, Secp256k1SignatureLength), 4); err == nil {
+		t.Error("RecoverSecp256k1PublicKey should reject recovery ID 4")
+	}
+}
+
 func TestSha3256(t *testing.T) {
 	// Test vector: SHA3-256 of empty string
 	hash := Sha3256([]byte{})
@@ -159,6 +206,47 @@ func TestSha3256(t *testing.T) {
 	}
 }
 
+func TestNormalizeSecp256k1PubKey(t *testing.T) {
+	priv, err := GenerateSecp256k1PrivateKey()
+	if err != nil {
+		t.Fatalf("GenerateSecp256k1PrivateKey error: %v", err)
+	}
+	compressed := priv.PublicKey()
+	uncompressed := priv.key.PubKey().SerializeUncompressed()
+
+	for _, pubKey := range [][]byte{compressed, uncompressed} {
+		got, err := NormalizeSecp256k1PubKey(pubKey)
+		if err != nil {
+			t.Fatalf("NormalizeSecp256k1PubKey(%d bytes) error: %v", len(pubKey), err)
+		}
+		if !bytes.Equal(got, compressed) {
+			t.Errorf("NormalizeSecp256k1PubKey(%d bytes) = %x, want %x", len(pubKey), got, compressed)
+		}
+	}
+
+	// Auth key and verification must not depend on the key encoding
+	if AuthenticationKey(uncompressed, Secp256k1Scheme) != AuthenticationKey(compressed, Secp256k1Scheme) {
+		t.Error("auth key differs between compressed and uncompressed keys")
+	}
+	message := []byte("test")
+	sig, err := priv.Signer().Sign(message)
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	if !VerifySecp256k1(uncompressed, message, sig) {
+		t.Error("signature verification with uncompressed key failed")
+	}
+
+	// Invalid keys
+	if _, err := NormalizeSecp256k1PubKey(compressed[:32]); err == nil {
+		t.Error("NormalizeSecp256k1PubKey should reject a 32-byte key")
+	}
+	invalid := append([]byte{0x05}, compressed[1:]...)
+	if _, err := NormalizeSecp256k1PubKey(invalid); err == nil {
+		t.Error("NormalizeSecp256k1PubKey should reject an invalid prefix")
+	}
+}
+
 func BenchmarkAuthenticationKey(b *testing.B) {
 	// Ed25519 public key (32 bytes)
 	pubKey := make([]byte, 32)
@@ -167,3 +255,185 @@ func BenchmarkAuthenticationKey(b *testing.B) {
 		_ = AuthenticationKey(pubKey, Ed25519Scheme)
 	}
 }
+
+func TestHashWithDomain(t *testing.T) {
+	message := []byte("intent")
+
+	// The transaction hash prefixes are the same domain separation.
+	if got, want := HashWithDomain("APTOS::RawTransaction", message), HashWithPrefix(RawTransactionHashPrefix, message); !bytes.Equal(got, want) {
+		t.Errorf("HashWithDomain = %x, want %x", got, want)
+	}
+	if bytes.Equal(HashWithDomain("MyApp::Intent", message), HashWithDomain("OtherApp::Intent", message)) {
+		t.Error("different domains should give different hashes")
+	}
+
+	priv, err := GenerateEd25519PrivateKey()
+	if err != nil {
+		t.Fatalf("GenerateEd25519PrivateKey error: %v", err)
+	}
+	signer := priv.Signer()
+	sig, err := SignWithDomain(signer, "MyApp::Intent", message)
+	if err != nil {
+		t.Fatalf("SignWithDomain error: %v", err)
+	}
+	if !VerifyEd25519(signer.PublicKey(), HashWithDomain("MyApp::Intent", message), sig) {
+		t.Error("signature verification failed")
+	}
+	if VerifyEd25519(signer.PublicKey(), HashWithDomain("OtherApp::Intent", message), sig) {
+		t.Error("signature should not verify under another domain")
+	}
+}
+
+func TestMultiEd25519Bitmap(t *testing.T) {
+	// Vector from the Aptos TypeScript SDK
+	bitmap, err := MultiEd25519Bitmap([]int{0, 2, 31})
+	if err != nil {
+		t.Fatalf("MultiEd25519Bitmap error: %v", err)
+	}
+	if want := [4]byte{0b10100000, 0b00000000, 0b00000000, 0b00000001}; bitmap != want {
+		t.Errorf("bitmap = %08b, want %08b", bitmap, want)
+	}
+	sig := MultiEd25519Signature{Bitmap: bitmap}
+	if got := sig.SignerIndices(); len(got) != 3 || got[0] != 0 || got[1] != 2 || got[2] != 31 {
+		t.Errorf("SignerIndices() = %v, want [0 2 31]", got)
+	}
+
+	for _, indices := range [][]int{{32}, {-1}, {1, 1}} {
+		if _, err := MultiEd25519Bitmap(indices); err == nil {
+			t.Errorf("MultiEd25519Bitmap(%v) succeeded, want error", indices)
+		}
+	}
+}
+
+func TestMultiEd25519SignAndVerify(t *testing.T) {
+	var signers []Signer
+	var publicKeys [][]byte
+	for i := 0; i < 3; i++ {
+		priv, err := GenerateEd25519PrivateKey()
+		if err != nil {
+			t.Fatalf("GenerateEd25519PrivateKey error: %v", err)
+		}
+		signers = append(signers, priv.Signer())
+		publicKeys = append(publicKeys, priv.PublicKey())
+	}
+	publicKey, err := NewMultiEd25519PublicKey(publicKeys, 2)
+	if err != nil {
+		t.Fatalf("NewMultiEd25519PublicKey error: %v", err)
+	}
+
+	// The auth key covers the keys, the threshold, and the scheme byte
+	keyBytes := publicKey.Bytes()
+	if len(keyBytes) != 3*Ed25519PublicKeyLength+1 || keyBytes[len(keyBytes)-1] != 2 {
+		t.Fatalf("Bytes() = %x", keyBytes)
+	}
+	if publicKey.AuthKey() != Sha3256(append(keyBytes, 0x01)) {
+		t.Error("auth key mismatch")
+	}
+	parsedKey, err := ParseMultiEd25519PublicKey(keyBytes)
+	if err != nil || !bytes.Equal(parsedKey.Bytes(), keyBytes) {
+		t.Errorf("ParseMultiEd25519PublicKey = %v, %v", parsedKey, err)
+	}
+
+	message := []byte("test message")
+	signatures := make(map[int][]byte)
+	for _, i := range []int{2, 0} {
+		if signatures[i], err = signers[i].Sign(message); err != nil {
+			t.Fatalf("Sign error: %v", err)
+		}
+	}
+	sig, err := NewMultiEd25519Signature(signatures)
+	if err != nil {
+		t.Fatalf("NewMultiEd25519Signature error: %v", err)
+	}
+	// Signatures are in key order regardless of map order
+	if !bytes.Equal(sig.Signatures[0], signatures[0]) || sig.Bitmap != [4]byte{0b10100000} {
+		t.Errorf("signature = %x, bitmap %08b", sig.Signatures, sig.Bitmap)
+	}
+	if !VerifyMultiEd25519(publicKey, message, sig) {
+		t.Error("signature verification failed")
+	}
+	if VerifyMultiEd25519(publicKey, []byte("wrong message"), sig) {
+		t.Error("signature verification should have failed")
+	}
+
+	sigBytes := sig.Bytes()
+	if len(sigBytes) != 2*Ed25519SignatureLength+MultiEd25519BitmapLength {
+		t.Fatalf("signature length = %d", len(sigBytes))
+	}
+	parsedSig, err := ParseMultiEd25519Signature(sigBytes)
+	if err != nil || !bytes.Equal(parsedSig.Bytes(), sigBytes) {
+		t.Errorf("ParseMultiEd25519Signature = %v, %v", parsedSig, err)
+	}
+
+	// One signature is below the threshold
+	one, _ := NewMultiEd25519Signature(map[int][]byte{0: signatures[0]})
+	if VerifyMultiEd25519(publicKey, message, one) {
+		t.Error("signature below the threshold should not verify")
+	}
+
+	if _, err := NewMultiEd25519PublicKey(publicKeys, 4); err == nil {
+		t.Error("threshold above the number of keys should fail")
+	}
+}
+
+func TestMnemonicToSeed(t *testing.T) {
+	// BIP39 test vector for all-zero entropy, with passphrase "TREZOR"
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		t.Fatalf("ValidateMnemonic error: %v", err)
+	}
+	want := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+	seed, err := MnemonicToSeed(mnemonic, "TREZOR")
+	if err != nil {
+		t.Fatalf("MnemonicToSeed error: %v", err)
+	}
+	if got := hex.EncodeToString(seed); got != want {
+		t.Errorf("MnemonicToSeed = %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		name     string
+		mnemonic string
+		wantErr  string
+	}{
+		{"bad checksum", strings.Repeat("abandon ", 12), "checksum"},
+		{"unknown word", strings.Replace(mnemonic, "about", "aptos", 1), "not in the BIP39 English wordlist"},
+		{"wrong word count", "abandon abandon about", "3 words"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMnemonic(tt.mnemonic)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateMnemonic error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeriveEd25519Seed(t *testing.T) {
+	// SLIP-0010 test vector 1 for ed25519
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"m", "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7"},
+		{"m/0'", "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3"},
+		{"m/0'/1'/2'/2'/1000000000'", "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793"},
+	}
+	for _, tt := range tests {
+		got, err := DeriveEd25519Seed(seed, tt.path)
+		if err != nil {
+			t.Fatalf("DeriveEd25519Seed(%s) error: %v", tt.path, err)
+		}
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("DeriveEd25519Seed(%s) = %x, want %s", tt.path, got, tt.want)
+		}
+	}
+
+	for _, path := range []string{"", "44'/637'", "m/44'/637'/0'/0/0", "m/44'/x'", "m/2147483648'", "m//0'"} {
+		if _, err := DeriveEd25519Seed(seed, path); err == nil {
+			t.Errorf("DeriveEd25519Seed(%q) succeeded, want error", path)
+		}
+	}
+}
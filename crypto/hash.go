@@ -24,6 +24,12 @@ var RawTransactionWithDataHashPrefix = sha3256Prefix("APTOS::RawTransactionWithD
 // TransactionHashPrefix is the prefix for computing signed transaction hashes.
 var TransactionHashPrefix = sha3256Prefix("APTOS::Transaction")
 
+// MessageHashPrefix is the prefix for off-chain messages, such as login
+// challenges, signed with Account.SignMessage. It differs from the
+// transaction prefixes, so a signed message can never be a valid transaction
+// signature.
+var MessageHashPrefix = sha3256Prefix("APTOS::Message")
+
 func sha3256Prefix(s string) []byte {
 	hash := sha3.Sum256([]byte(s))
 	return hash[:]
@@ -39,3 +45,12 @@ func HashWithPrefix(prefix, message []byte) []byte {
 	h.Sum(result[:0])
 	return result[:]
 }
+
+// HashWithDomain computes SHA3-256(SHA3-256(domain) || message), the same
+// domain separation used for transactions with a domain such as
+// "APTOS::RawTransaction". Protocols signing their own structured data should
+// pick a domain that names them, such as "MyApp::Intent", so that signatures
+// cannot be replayed as transactions or as another protocol's messages.
+func HashWithDomain(domain string, message []byte) []byte {
+	return HashWithPrefix(sha3256Prefix(domain), message)
+}
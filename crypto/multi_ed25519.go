@@ -0,0 +1,188 @@
+package crypto
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// MultiEd25519MaxKeys is the maximum number of keys in a MultiEd25519
+	// public key.
+	MultiEd25519MaxKeys = 32
+
+	// MultiEd25519BitmapLength is the length of the bitmap of a MultiEd25519
+	// signature.
+	MultiEd25519BitmapLength = 4
+)
+
+// MultiEd25519PublicKey is a legacy k-of-n multi-signature key made of up to
+// 32 Ed25519 public keys, of which Threshold must sign.
+type MultiEd25519PublicKey struct {
+	PublicKeys [][]byte
+	Threshold  uint8
+}
+
+// NewMultiEd25519PublicKey creates a MultiEd25519 public key that requires
+// threshold signatures from the given Ed25519 public keys. The order of the
+// keys is significant: it determines the auth key and the signer indices.
+func NewMultiEd25519PublicKey(publicKeys [][]byte, threshold uint8) (*MultiEd25519PublicKey, error) {
+	k := &MultiEd25519PublicKey{PublicKeys: publicKeys, Threshold: threshold}
+	if err := k.validate(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// ParseMultiEd25519PublicKey parses the bytes of a MultiEd25519 public key, as
+// returned by Bytes.
+func ParseMultiEd25519PublicKey(data []byte) (*MultiEd25519PublicKey, error) {
+	if len(data)%Ed25519PublicKeyLength != 1 {
+		return nil, fmt.Errorf("invalid MultiEd25519 public key length: %d", len(data))
+	}
+	n := len(data) / Ed25519PublicKeyLength
+	publicKeys := make([][]byte, n)
+	for i := range publicKeys {
+		publicKeys[i] = append([]byte(nil), data[i*Ed25519PublicKeyLength:(i+1)*Ed25519PublicKeyLength]...)
+	}
+	return NewMultiEd25519PublicKey(publicKeys, data[len(data)-1])
+}
+
+// validate checks the number of keys, their lengths, and the threshold.
+func (k *MultiEd25519PublicKey) validate() error {
+	n := len(k.PublicKeys)
+	if n == 0 || n > MultiEd25519MaxKeys {
+		return fmt.Errorf("invalid number of MultiEd25519 public keys: %d, want 1 to %d", n, MultiEd25519MaxKeys)
+	}
+	if k.Threshold == 0 || int(k.Threshold) > n {
+		return fmt.Errorf("invalid MultiEd25519 threshold: %d of %d keys", k.Threshold, n)
+	}
+	for i, publicKey := range k.PublicKeys {
+		if len(publicKey) != Ed25519PublicKeyLength {
+			return fmt.Errorf("invalid Ed25519 public key %d length: %d", i, len(publicKey))
+		}
+	}
+	return nil
+}
+
+// Bytes returns the encoding used on chain: the concatenated public keys
+// followed by the threshold byte.
+func (k *MultiEd25519PublicKey) Bytes() []byte {
+	buf := make([]byte, 0, len(k.PublicKeys)*Ed25519PublicKeyLength+1)
+	for _, publicKey := range k.PublicKeys {
+		buf = append(buf, publicKey...)
+	}
+	return append(buf, k.Threshold)
+}
+
+// AuthKey returns the authentication key of an account controlled by this
+// key: SHA3-256(Bytes() || MultiEd25519AuthScheme).
+func (k *MultiEd25519PublicKey) AuthKey() [32]byte {
+	return Sha3256(append(k.Bytes(), MultiEd25519AuthScheme))
+}
+
+// MultiEd25519Signature is a signature by some of the keys of a
+// MultiEd25519PublicKey. Bitmap marks which keys signed, and Signatures holds
+// their signatures in key order.
+type MultiEd25519Signature struct {
+	Signatures [][]byte
+	Bitmap     [MultiEd25519BitmapLength]byte
+}
+
+// NewMultiEd25519Signature creates a MultiEd25519 signature from the
+// signatures of the keys at the given indices of the public key.
+func NewMultiEd25519Signature(signatures map[int][]byte) (*MultiEd25519Signature, error) {
+	indices := make([]int, 0, len(signatures))
+	for i := range signatures {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	bitmap, err := MultiEd25519Bitmap(indices)
+	if err != nil {
+		return nil, err
+	}
+	s := &MultiEd25519Signature{Bitmap: bitmap}
+	for _, i := range indices {
+		if len(signatures[i]) != Ed25519SignatureLength {
+			return nil, fmt.Errorf("invalid Ed25519 signature %d length: %d", i, len(signatures[i]))
+		}
+		s.Signatures = append(s.Signatures, signatures[i])
+	}
+	return s, nil
+}
+
+// ParseMultiEd25519Signature parses the bytes of a MultiEd25519 signature, as
+// returned by Bytes.
+func ParseMultiEd25519Signature(data []byte) (*MultiEd25519Signature, error) {
+	if len(data)%Ed25519SignatureLength != MultiEd25519BitmapLength {
+		return nil, fmt.Errorf("invalid MultiEd25519 signature length: %d", len(data))
+	}
+	n := len(data) / Ed25519SignatureLength
+	s := &MultiEd25519Signature{Signatures: make([][]byte, n)}
+	for i := range s.Signatures {
+		s.Signatures[i] = append([]byte(nil), data[i*Ed25519SignatureLength:(i+1)*Ed25519SignatureLength]...)
+	}
+	copy(s.Bitmap[:], data[n*Ed25519SignatureLength:])
+	if got := len(s.SignerIndices()); got != n {
+		return nil, fmt.Errorf("MultiEd25519 bitmap marks %d signers for %d signatures", got, n)
+	}
+	return s, nil
+}
+
+// Bytes returns the encoding used on chain: the concatenated signatures
+// followed by the bitmap.
+func (s *MultiEd25519Signature) Bytes() []byte {
+	buf := make([]byte, 0, len(s.Signatures)*Ed25519SignatureLength+MultiEd25519BitmapLength)
+	for _, signature := range s.Signatures {
+		buf = append(buf, signature...)
+	}
+	return append(buf, s.Bitmap[:]...)
+}
+
+// SignerIndices returns the indices of the keys marked in the bitmap, in
+// ascending order.
+func (s *MultiEd25519Signature) SignerIndices() []int {
+	var indices []int
+	for i := 0; i < MultiEd25519MaxKeys; i++ {
+		if s.Bitmap[i/8]&(0x80>>(i%8)) != 0 {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// MultiEd25519Bitmap returns the bitmap marking the keys at the given indices.
+// Key i is the (i%8)-th most significant bit of byte i/8, so keys 0, 2, and
+// 31 give 0b10100000 0b00000000 0b00000000 0b00000001.
+func MultiEd25519Bitmap(indices []int) ([MultiEd25519BitmapLength]byte, error) {
+	var bitmap [MultiEd25519BitmapLength]byte
+	for _, i := range indices {
+		if i < 0 || i >= MultiEd25519MaxKeys {
+			return bitmap, fmt.Errorf("invalid MultiEd25519 signer index: %d", i)
+		}
+		bit := byte(0x80) >> (i % 8)
+		if bitmap[i/8]&bit != 0 {
+			return bitmap, fmt.Errorf("duplicate MultiEd25519 signer index: %d", i)
+		}
+		bitmap[i/8] |= bit
+	}
+	return bitmap, nil
+}
+
+// VerifyMultiEd25519 verifies a MultiEd25519 signature: it must carry at least
+// the threshold of signatures, each valid for the key its bitmap bit marks.
+func VerifyMultiEd25519(publicKey *MultiEd25519PublicKey, message []byte, signature *MultiEd25519Signature) bool {
+	if publicKey.validate() != nil {
+		return false
+	}
+	indices := signature.SignerIndices()
+	if len(indices) != len(signature.Signatures) || len(indices) < int(publicKey.Threshold) {
+		return false
+	}
+	for j, i := range indices {
+		if i >= len(publicKey.PublicKeys) || !VerifyEd25519(publicKey.PublicKeys[i], message, signature.Signatures[j]) {
+			return false
+		}
+	}
+	return true
+}
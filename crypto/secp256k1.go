@@ -15,10 +15,17 @@ const (
 	// Secp256k1PublicKeyLength is the length of a compressed secp256k1 public key.
 	Secp256k1PublicKeyLength = 33
 
+	// Secp256k1UncompressedPublicKeyLength is the length of an uncompressed secp256k1 public key.
+	Secp256k1UncompressedPublicKeyLength = 65
+
 	// Secp256k1SignatureLength is the length of a secp256k1 signature.
 	Secp256k1SignatureLength = 64
 )
 
+// secp256k1CompactMagic is added to the recovery ID in the first byte of a
+// compact signature over an uncompressed key.
+const secp256k1CompactMagic = 27
+
 // Secp256k1PrivateKey represents a secp256k1 private key.
 type Secp256k1PrivateKey struct {
 	key *secp256k1.PrivateKey
@@ -66,17 +73,25 @@ type Secp256k1Signer struct {
 // Sign signs the message with secp256k1 ECDSA.
 // The message is hashed with SHA3-256 before signing.
 func (s *Secp256k1Signer) Sign(message []byte) ([]byte, error) {
+	sig, _, err := s.SignRecoverable(message)
+	return sig, err
+}
+
+// SignRecoverable signs the message like Sign, and also returns the recovery
+// ID (0 to 3) that RecoverSecp256k1PublicKey needs to recover the public key
+// from the signature. Appending the recovery ID to the signature gives the
+// 65-byte r || s || v form; Ethereum tooling expects v = 27 + recovery ID.
+func (s *Secp256k1Signer) SignRecoverable(message []byte) ([]byte, byte, error) {
 	// Hash the message with SHA3-256
 	hash := Sha3256(message)
 
 	// Sign the hash
 	sig := ecdsa.SignCompact(s.key, hash[:], false)
-	// SignCompact returns [recovery_id || r || s] (65 bytes)
-	// We only need r || s (64 bytes)
+	// SignCompact returns [27 + recovery_id || r || s] (65 bytes)
 	if len(sig) != 65 {
-		return nil, fmt.Errorf("unexpected signature length: %d", len(sig))
+		return nil, 0, fmt.Errorf("unexpected signature length: %d", len(sig))
 	}
-	return sig[1:], nil // Remove recovery ID
+	return sig[1:], sig[0] - secp256k1CompactMagic, nil
 }
 
 // PublicKey returns the compressed secp256k1 public key (33 bytes).
@@ -84,7 +99,7 @@ func (s *Secp256k1Signer) PublicKey() []byte {
 	return s.key.PubKey().SerializeCompressed()
 }
 
-// AuthKey returns the authentication key for this signer.
+// AuthKey returns the single-key authentication key for this signer.
 func (s *Secp256k1Signer) AuthKey() [32]byte {
 	return AuthenticationKey(s.PublicKey(), Secp256k1Scheme)
 }
@@ -94,9 +109,47 @@ func (s *Secp256k1Signer) Scheme() SignatureScheme {
 	return Secp256k1Scheme
 }
 
+// NormalizeSecp256k1PubKey parses a compressed (33-byte) or uncompressed
+// (65-byte) secp256k1 public key and returns it in compressed form.
+func NormalizeSecp256k1PubKey(publicKey []byte) ([]byte, error) {
+	pubKey, err := parseSecp256k1PubKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return pubKey.SerializeCompressed(), nil
+}
+
+// UncompressSecp256k1PubKey parses a compressed (33-byte) or uncompressed
+// (65-byte) secp256k1 public key and returns it in uncompressed form, which is
+// the form used on chain in AnyPublicKey and for authentication key
+// derivation.
+func UncompressSecp256k1PubKey(publicKey []byte) ([]byte, error) {
+	pubKey, err := parseSecp256k1PubKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return pubKey.SerializeUncompressed(), nil
+}
+
+func parseSecp256k1PubKey(publicKey []byte) (*secp256k1.PublicKey, error) {
+	if len(publicKey) != Secp256k1PublicKeyLength && len(publicKey) != Secp256k1UncompressedPublicKeyLength {
+		return nil, fmt.Errorf("invalid secp256k1 public key length: got %d, want %d or %d",
+			len(publicKey), Secp256k1PublicKeyLength, Secp256k1UncompressedPublicKeyLength)
+	}
+	pubKey, err := secp256k1.ParsePubKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secp256k1 public key: %w", err)
+	}
+	return pubKey, nil
+}
+
 // VerifySecp256k1 verifies a secp256k1 ECDSA signature.
+// The public key may be compressed (33 bytes) or uncompressed (65 bytes).
 func VerifySecp256k1(publicKey, message, signature []byte) bool {
-	if len(publicKey) != Secp256k1PublicKeyLength || len(signature) != Secp256k1SignatureLength {
+	if len(signature) != Secp256k1SignatureLength {
+		return false
+	}
+	if len(publicKey) != Secp256k1PublicKeyLength && len(publicKey) != Secp256k1UncompressedPublicKeyLength {
 		return false
 	}
 
@@ -117,3 +170,27 @@ func VerifySecp256k1(publicKey, message, signature []byte) bool {
 
 	return sig.Verify(hash[:], pubKey)
 }
+
+// RecoverSecp256k1PublicKey recovers the compressed public key that produced
+// a 64-byte r || s signature of message, given the recovery ID returned by
+// SignRecoverable. As with VerifySecp256k1, the message is hashed with
+// SHA3-256. The recovered key is the signer's only if the recovery ID is the
+// one from signing; compare it against the expected key or address.
+func RecoverSecp256k1PublicKey(message, signature []byte, recoveryID byte) ([]byte, error) {
+	if len(signature) != Secp256k1SignatureLength {
+		return nil, fmt.Errorf("invalid secp256k1 signature length: got %d, want %d", len(signature), Secp256k1SignatureLength)
+	}
+	if recoveryID > 3 {
+		return nil, fmt.Errorf("invalid secp256k1 recovery ID: %d", recoveryID)
+	}
+
+	compact := make([]byte, 0, 1+Secp256k1SignatureLength)
+	compact = append(compact, secp256k1CompactMagic+recoveryID)
+	compact = append(compact, signature...)
+	hash := Sha3256(message)
+	pubKey, _, err := ecdsa.RecoverCompact(compact, hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("recover secp256k1 public key: %w", err)
+	}
+	return pubKey.SerializeCompressed(), nil
+}
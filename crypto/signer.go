@@ -1,7 +1,8 @@
 // Package crypto provides cryptographic primitives for Aptos transactions.
 package crypto
 
-// SignatureScheme represents the signature scheme used.
+// SignatureScheme represents the signature scheme used. Its values are the
+// AnyPublicKey and AnySignature variants of the single-key authenticator.
 type SignatureScheme uint8
 
 const (
@@ -9,7 +10,7 @@ const (
 	Ed25519Scheme SignatureScheme = 0
 
 	// Secp256k1Scheme is the secp256k1 ECDSA signature scheme.
-	Secp256k1Scheme SignatureScheme = 2
+	Secp256k1Scheme SignatureScheme = 1
 )
 
 // Signer is the interface for signing messages.
@@ -27,6 +28,14 @@ type Signer interface {
 	Scheme() SignatureScheme
 }
 
+// SignWithDomain signs a domain-separated message: the signature is over
+// HashWithDomain(domain, message), as transaction signatures are over the
+// prefixed transaction hash. Verify it by checking the signature against
+// HashWithDomain(domain, message) with the verifier for the signer's scheme.
+func SignWithDomain(signer Signer, domain string, message []byte) ([]byte, error) {
+	return signer.Sign(HashWithDomain(domain, message))
+}
+
 // PrivateKey represents a private key.
 type PrivateKey interface {
 	// Bytes returns the private key bytes.
@@ -36,13 +45,54 @@ type PrivateKey interface {
 	Signer() Signer
 }
 
-// AuthenticationKey derives an authentication key from a public key and scheme.
-// For single-key authenticators: SHA3-256(pubkey || scheme)
+// Authentication key scheme bytes, appended to the key material before
+// hashing. They identify the kind of authenticator an account expects.
+const (
+	// Ed25519AuthScheme is the legacy single Ed25519 key scheme.
+	Ed25519AuthScheme byte = 0
+
+	// MultiEd25519AuthScheme is the legacy k-of-n Ed25519 scheme.
+	MultiEd25519AuthScheme byte = 1
+
+	// SingleKeyAuthScheme is the single-key (AnyPublicKey) scheme.
+	SingleKeyAuthScheme byte = 2
+
+	// MultiKeyAuthScheme is the k-of-n multi-key (AnyPublicKey) scheme.
+	MultiKeyAuthScheme byte = 3
+)
+
+// AuthenticationKey derives the authentication key of an account that signs
+// with the given key. For Ed25519 this is the legacy derivation,
+// SHA3-256(pubkey || Ed25519AuthScheme), which matches accounts that sign with
+// the legacy Ed25519 authenticator; see SingleKeyAuthenticationKey for
+// single-key Ed25519 accounts. Secp256k1 keys can only sign through the
+// single-key authenticator, so they use the single-key derivation over the
+// uncompressed key, whichever form pubKey is in.
 func AuthenticationKey(pubKey []byte, scheme SignatureScheme) [32]byte {
+	if scheme == Secp256k1Scheme {
+		if uncompressed, err := UncompressSecp256k1PubKey(pubKey); err == nil {
+			pubKey = uncompressed
+		}
+		return SingleKeyAuthenticationKey(uint8(Secp256k1Scheme), pubKey)
+	}
 	// Use stack-allocated array to avoid heap allocation.
-	// Max size: 33 bytes (secp256k1 compressed) + 1 byte scheme = 34 bytes
-	var buf [34]byte
+	// Max size: 32 bytes (Ed25519) + 1 byte scheme = 33 bytes
+	var buf [33]byte
 	n := copy(buf[:], pubKey)
 	buf[n] = byte(scheme)
 	return Sha3256(buf[:n+1])
 }
+
+// SingleKeyAuthenticationKey derives the authentication key of a single-key
+// account, which signs with the single-key (AnyPublicKey) authenticator:
+// SHA3-256(bcs(AnyPublicKey) || SingleKeyAuthScheme), where bcs(AnyPublicKey)
+// is the key variant (0 for Ed25519, 1 for secp256k1) followed by the
+// length-prefixed key. Secp256k1 keys must be passed uncompressed.
+func SingleKeyAuthenticationKey(variant uint8, pubKey []byte) [32]byte {
+	// Variants and key lengths are below 128, so each ULEB128 is one byte
+	buf := make([]byte, 0, len(pubKey)+3)
+	buf = append(buf, variant, byte(len(pubKey)))
+	buf = append(buf, pubKey...)
+	buf = append(buf, SingleKeyAuthScheme)
+	return Sha3256(buf)
+}
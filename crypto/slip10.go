@@ -0,0 +1,63 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// hardenedOffset is added to the index of a hardened derivation step.
+const hardenedOffset = 0x80000000
+
+// DeriveEd25519Seed derives the Ed25519 private key seed at a derivation path,
+// such as "m/44'/637'/0'/0'/0'", from a BIP39 seed, following SLIP-0010. Ed25519
+// only supports hardened derivation, so every step of the path must be
+// hardened (marked with ').
+func DeriveEd25519Seed(seed []byte, path string) ([]byte, error) {
+	indices, err := parseHardenedPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha512.New, []byte("ed25519 seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+
+	for _, index := range indices {
+		// Hardened child: HMAC-SHA512(chainCode, 0x00 || key || index)
+		var data [1 + 32 + 4]byte
+		copy(data[1:], key)
+		binary.BigEndian.PutUint32(data[33:], index)
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data[:])
+		sum := mac.Sum(nil)
+		key, chainCode = sum[:32], sum[32:]
+	}
+	return key, nil
+}
+
+// parseHardenedPath parses a derivation path whose steps are all hardened,
+// returning the child indices with the hardened offset added.
+func parseHardenedPath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q: must start with m", path)
+	}
+	indices := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		digits, hardened := strings.CutSuffix(segment, "'")
+		if !hardened {
+			return nil, fmt.Errorf("invalid derivation path %q: step %q is not hardened", path, segment)
+		}
+		index, err := strconv.ParseUint(digits, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %q: bad step %q", path, segment)
+		}
+		indices = append(indices, uint32(index)+hardenedOffset)
+	}
+	return indices, nil
+}
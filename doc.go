@@ -38,6 +38,7 @@
 // The SDK is organized as follows:
 //
 //   - aptos: Main package with Client, Account, and core types
+//   - aptos/aptostest: Simulation-based helpers for testing Move contracts
 //   - aptos/bcs: Binary Canonical Serialization for transaction encoding
 //   - aptos/crypto: Cryptographic primitives (Ed25519, Secp256k1)
 //   - aptos/examples: Runnable examples
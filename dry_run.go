@@ -0,0 +1,152 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Write set change types, as found in the Type field of WriteSetChange.
+const (
+	WriteSetChangeWriteResource   = "write_resource"
+	WriteSetChangeDeleteResource  = "delete_resource"
+	WriteSetChangeWriteModule     = "write_module"
+	WriteSetChangeDeleteModule    = "delete_module"
+	WriteSetChangeWriteTableItem  = "write_table_item"
+	WriteSetChangeDeleteTableItem = "delete_table_item"
+)
+
+// WriteSetChange is a single state change made by a transaction. Which fields
+// are set depends on Type.
+type WriteSetChange struct {
+	Type         string `json:"type"`
+	StateKeyHash string `json:"state_key_hash"`
+
+	// Address is the account of a resource or module change.
+	Address AccountAddress `json:"address"`
+
+	// Resource is the deleted resource type of a delete_resource change, and
+	// Module the deleted module of a delete_module change.
+	Resource string `json:"resource,omitempty"`
+	Module   string `json:"module,omitempty"`
+
+	// Handle, Key, and Value are the hex-encoded table handle, key, and
+	// value of a table item change.
+	Handle string `json:"handle,omitempty"`
+	Key    string `json:"key,omitempty"`
+	Value  string `json:"value,omitempty"`
+
+	// Data is the written value: a MoveResource for write_resource, the
+	// module bytecode and ABI for write_module, and the decoded key and value
+	// of a table item, when the node can decode them.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// decodeWriteSetChanges decodes the "changes" of a transaction, which may be
+// absent.
+func decodeWriteSetChanges(raw json.RawMessage) ([]WriteSetChange, error) {
+	var changes []WriteSetChange
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &changes); err != nil {
+			return nil, fmt.Errorf("decode changes: %w", err)
+		}
+	}
+	return changes, nil
+}
+
+// ResourceType returns the resource type written or deleted by a resource
+// change, or "" for other changes.
+func (c *WriteSetChange) ResourceType() string {
+	switch c.Type {
+	case WriteSetChangeDeleteResource:
+		return c.Resource
+	case WriteSetChangeWriteResource:
+		var resource MoveResource
+		if json.Unmarshal(c.Data, &resource) != nil {
+			return ""
+		}
+		return resource.Type
+	}
+	return ""
+}
+
+// DecodeResource decodes the data of the resource written by a
+// write_resource change into v.
+func (c *WriteSetChange) DecodeResource(v interface{}) error {
+	if c.Type != WriteSetChangeWriteResource {
+		return fmt.Errorf("change is %s, not %s", c.Type, WriteSetChangeWriteResource)
+	}
+	var resource MoveResource
+	if err := json.Unmarshal(c.Data, &resource); err != nil {
+		return err
+	}
+	return resource.DecodeData(v)
+}
+
+// DryRunResult is the effect of executing a payload without committing it,
+// as returned by Client.DryRun.
+type DryRunResult struct {
+	// Success reports whether execution succeeded; VMStatus holds the reason
+	// if it did not. A failed execution still reports the gas it used.
+	Success  bool
+	VMStatus string
+	GasUsed  uint64
+
+	Events  []Event
+	Changes []WriteSetChange
+}
+
+// EventsOfType returns the events of the given type, such as
+// "0x1::fungible_asset::Deposit".
+func (r *DryRunResult) EventsOfType(eventType string) []Event {
+	var events []Event
+	for _, event := range r.Events {
+		if event.Type == eventType {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// ResourceChange returns the change to the resource of the given type at
+// address, if the execution wrote or deleted it.
+func (r *DryRunResult) ResourceChange(address AccountAddress, resourceType string) (*WriteSetChange, bool) {
+	for i := range r.Changes {
+		change := &r.Changes[i]
+		if change.Address == address && change.ResourceType() == resourceType {
+			return change, true
+		}
+	}
+	return nil, false
+}
+
+// DryRun executes payload as account through simulation, without committing
+// it, and returns the events it emits and the state changes it makes. Unlike
+// View, which only calls #[view] functions, it can preview the effects of any
+// entry function or script. A failed execution is reported in the result,
+// not as an error.
+func (c *Client) DryRun(ctx context.Context, account *Account, payload TransactionPayload, opts ...BuildOption) (Response[DryRunResult], error) {
+	result, err := c.SimulatePayload(ctx, account, payload, opts...)
+	if err != nil {
+		return Response[DryRunResult]{}, err
+	}
+	if len(result.Data) != 1 {
+		return Response[DryRunResult]{}, fmt.Errorf("expected 1 simulated transaction, got %d", len(result.Data))
+	}
+
+	txn := result.Data[0]
+	changes, err := decodeWriteSetChanges(txn.Changes)
+	if err != nil {
+		return Response[DryRunResult]{}, err
+	}
+	return Response[DryRunResult]{
+		Data: DryRunResult{
+			Success:  txn.Success,
+			VMStatus: txn.VMStatus,
+			GasUsed:  txn.GasUsed.Uint64(),
+			Events:   txn.Events,
+			Changes:  changes,
+		},
+		Metadata: result.Metadata,
+	}, nil
+}
@@ -2,9 +2,12 @@ package aptos
 
 import (
 	"encoding/binary"
+	"fmt"
 	"math/big"
+	"strconv"
 
 	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/internal/hex"
 )
 
 // EntryFunctionArg represents a BCS-encoded entry function argument.
@@ -103,6 +106,19 @@ func VectorU8Arg(v []byte) EntryFunctionArg {
 	return BytesArg(v)
 }
 
+// VectorU16Arg creates a BCS-encoded vector<u16> argument.
+func VectorU16Arg(values []uint16) EntryFunctionArg {
+	ser := bcs.AcquireSerializer()
+	ser.Uleb128(uint32(len(values)))
+	for _, v := range values {
+		ser.U16(v)
+	}
+	// Must copy since we're releasing the serializer
+	result := append([]byte(nil), ser.ToBytes()...)
+	bcs.ReleaseSerializer(ser)
+	return result
+}
+
 // VectorU64Arg creates a BCS-encoded vector<u64> argument.
 func VectorU64Arg(values []uint64) EntryFunctionArg {
 	ser := bcs.AcquireSerializer()
@@ -129,6 +145,16 @@ func VectorAddressArg(addrs []AccountAddress) EntryFunctionArg {
 	return result
 }
 
+// VectorBytesArg creates a BCS-encoded vector<vector<u8>> argument.
+func VectorBytesArg(values [][]byte) EntryFunctionArg {
+	ser := bcs.AcquireSerializer()
+	ser.BytesVector(values)
+	// Must copy since we're releasing the serializer
+	result := append([]byte(nil), ser.ToBytes()...)
+	bcs.ReleaseSerializer(ser)
+	return result
+}
+
 // VectorStringArg creates a BCS-encoded vector<string> argument.
 func VectorStringArg(values []string) EntryFunctionArg {
 	ser := bcs.AcquireSerializer()
@@ -190,7 +216,251 @@ func OptionStringArg(v *string) EntryFunctionArg {
 	return result
 }
 
-// ObjectArg creates a BCS-encoded Object<T> argument (same as address).
+// OptionArg creates a BCS-encoded Option<T> argument from any argument builder.
+// Pass nil for None, or a pointer to a value for Some, e.g.
+// OptionArg(&data, BytesArg) for Option<vector<u8>>.
+func OptionArg[T any](v *T, encode func(T) EntryFunctionArg) EntryFunctionArg {
+	if v == nil {
+		return []byte{0} // None
+	}
+	inner := encode(*v)
+	result := make([]byte, 1, 1+len(inner))
+	result[0] = 1 // Some
+	return append(result, inner...)
+}
+
+// OptionBoolArg creates a BCS-encoded Option<bool> argument.
+// Pass nil for None, or a pointer to a value for Some.
+func OptionBoolArg(v *bool) EntryFunctionArg {
+	return OptionArg(v, BoolArg)
+}
+
+// OptionU128Arg creates a BCS-encoded Option<u128> argument.
+// Pass nil for None, or a value for Some.
+func OptionU128Arg(v *big.Int) EntryFunctionArg {
+	if v == nil {
+		return []byte{0} // None
+	}
+	return OptionArg(&v, U128Arg)
+}
+
+// ObjectArg creates a BCS-encoded Object<T> argument.
+// An Object<T> is passed as the object's address, so this encodes the same
+// bytes as AddressArg; the type parameter T is checked by the node against
+// the resources stored at the address.
 func ObjectArg(addr AccountAddress) EntryFunctionArg {
 	return AddressArg(addr)
 }
+
+// VectorObjectArg creates a BCS-encoded vector<Object<T>> argument
+// (same bytes as VectorAddressArg).
+func VectorObjectArg(objs []AccountAddress) EntryFunctionArg {
+	return VectorAddressArg(objs)
+}
+
+// DecodeEntryFunctionArgs decodes BCS-encoded entry function arguments into Go
+// values using the function's parameter types from its ABI.
+//
+// Leading signer parameters (as listed in the ABI) are skipped, since they are
+// not passed as arguments. Values are decoded as follows:
+//   - bool, u8, u16, u32, u64: bool, uint8, uint16, uint32, uint64
+//   - u128, u256: U128, U256
+//   - address, 0x1::object::Object<T>: AccountAddress
+//   - 0x1::string::String: string
+//   - vector<u8>: []byte
+//   - vector<T>: []interface{}
+//   - 0x1::option::Option<T>: nil for None, or the decoded value for Some
+func DecodeEntryFunctionArgs(args [][]byte, paramTypes []TypeTag) ([]interface{}, error) {
+	for len(paramTypes) > 0 && isSignerParam(paramTypes[0]) {
+		paramTypes = paramTypes[1:]
+	}
+	if len(args) != len(paramTypes) {
+		return nil, fmt.Errorf("argument count mismatch: got %d arguments, want %d", len(args), len(paramTypes))
+	}
+
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		value, err := DecodeEntryFunctionArg(arg, paramTypes[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %w", i, paramTypes[i], err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// DecodeEntryFunctionArg decodes a single BCS-encoded entry function argument
+// of the given type, as produced by the Arg constructors in this package. The
+// Go types of the results are those listed for DecodeEntryFunctionArgs. It
+// fails if data holds more than one value.
+func DecodeEntryFunctionArg(data []byte, tag TypeTag) (interface{}, error) {
+	des := bcs.NewDeserializer(data)
+	value := decodeMoveValue(des, tag)
+	if err := des.Error(); err != nil {
+		return nil, err
+	}
+	if des.Remaining() > 0 {
+		return nil, fmt.Errorf("%d bytes remaining after decoding", des.Remaining())
+	}
+	return value, nil
+}
+
+// EntryFunctionArgsToJSON decodes BCS-encoded entry function arguments, as
+// DecodeEntryFunctionArgs does, and converts them to the JSON forms the REST
+// API expects, e.g. for ViewRequest.Arguments:
+//   - bool, u8, u16, u32: JSON booleans and numbers
+//   - u64, u128, u256: decimal strings
+//   - address, 0x1::object::Object<T>: 0x-prefixed hex strings
+//   - 0x1::string::String: strings
+//   - vector<u8>: 0x-prefixed hex strings
+//   - vector<T>: arrays
+//   - 0x1::option::Option<T>: {"vec": []} for None, {"vec": [value]} for Some
+func EntryFunctionArgsToJSON(args [][]byte, paramTypes []TypeTag) ([]interface{}, error) {
+	for len(paramTypes) > 0 && isSignerParam(paramTypes[0]) {
+		paramTypes = paramTypes[1:]
+	}
+	values, err := DecodeEntryFunctionArgs(args, paramTypes)
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		values[i] = moveValueToJSON(value, paramTypes[i])
+	}
+	return values, nil
+}
+
+// moveValueToJSON converts a value returned by decodeMoveValue for tag to
+// its JSON argument form.
+func moveValueToJSON(value interface{}, tag TypeTag) interface{} {
+	switch t := tag.Value.(type) {
+	case *VectorTag:
+		if elems, ok := value.([]interface{}); ok {
+			out := make([]interface{}, len(elems))
+			for i, elem := range elems {
+				out[i] = moveValueToJSON(elem, t.ElementType)
+			}
+			return out
+		}
+	case *StructTag:
+		if t.Module == "option" && t.Name == "Option" {
+			// Option<T> decodes to nil for None
+			vec := []interface{}{}
+			if value != nil {
+				vec = append(vec, moveValueToJSON(value, t.TypeParams[0]))
+			}
+			return map[string]interface{}{"vec": vec}
+		}
+	}
+
+	switch v := value.(type) {
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case U128:
+		return v.String()
+	case U256:
+		return v.String()
+	case AccountAddress:
+		return v.String()
+	case []byte:
+		return hex.Encode(v)
+	}
+	return value
+}
+
+// isSignerParam reports whether a parameter type is signer or &signer.
+func isSignerParam(tag TypeTag) bool {
+	if ref, ok := tag.Value.(*ReferenceTag); ok {
+		tag = ref.To
+	}
+	return tag.Value != nil && tag.Value.typeTagVariant() == TypeTagSigner
+}
+
+// decodeMoveValue decodes a single Move value of the given type.
+// Errors are recorded on the deserializer.
+func decodeMoveValue(des *bcs.Deserializer, tag TypeTag) interface{} {
+	if tag.Value == nil {
+		des.SetError(fmt.Errorf("TypeTag value is nil"))
+		return nil
+	}
+	switch tag.Value.typeTagVariant() {
+	case TypeTagBool:
+		return des.Bool()
+	case TypeTagU8:
+		return des.U8()
+	case TypeTagU16:
+		return des.U16()
+	case TypeTagU32:
+		return des.U32()
+	case TypeTagU64:
+		return des.U64()
+	case TypeTagU128:
+		var v U128
+		v.UnmarshalBCS(des)
+		return v
+	case TypeTagU256:
+		var v U256
+		v.UnmarshalBCS(des)
+		return v
+	case TypeTagAddress:
+		var addr AccountAddress
+		addr.UnmarshalBCS(des)
+		return addr
+	case TypeTagVector:
+		return decodeMoveVector(des, tag.Value.(*VectorTag).ElementType)
+	case TypeTagStruct:
+		return decodeMoveStruct(des, tag.Value.(*StructTag))
+	default:
+		des.SetError(fmt.Errorf("unsupported argument type: %s", tag))
+		return nil
+	}
+}
+
+func decodeMoveVector(des *bcs.Deserializer, elemType TypeTag) interface{} {
+	if elemType.Value != nil && elemType.Value.typeTagVariant() == TypeTagU8 {
+		return des.Bytes()
+	}
+	length := des.Uleb128()
+	if des.Error() != nil {
+		return nil
+	}
+	values := make([]interface{}, 0, min(int(length), des.Remaining()))
+	for i := uint32(0); i < length; i++ {
+		value := decodeMoveValue(des, elemType)
+		if des.Error() != nil {
+			return nil
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func decodeMoveStruct(des *bcs.Deserializer, tag *StructTag) interface{} {
+	if tag.Address != AccountOne {
+		des.SetError(fmt.Errorf("unsupported argument type: %s", tag))
+		return nil
+	}
+	switch {
+	case tag.Module == "string" && tag.Name == "String":
+		return des.String()
+	case tag.Module == "object" && tag.Name == "Object":
+		var addr AccountAddress
+		addr.UnmarshalBCS(des)
+		return addr
+	case tag.Module == "option" && tag.Name == "Option" && len(tag.TypeParams) == 1:
+		// Option<T> is encoded as a vector of zero or one elements
+		switch length := des.Uleb128(); {
+		case des.Error() != nil:
+			return nil
+		case length == 0:
+			return nil
+		case length == 1:
+			return decodeMoveValue(des, tag.TypeParams[0])
+		default:
+			des.SetError(fmt.Errorf("invalid option length: %d", length))
+			return nil
+		}
+	default:
+		des.SetError(fmt.Errorf("unsupported argument type: %s", tag))
+		return nil
+	}
+}
@@ -0,0 +1,246 @@
+package aptos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeEntryFunctionArgs(t *testing.T) {
+	recipient := MustParseAccountAddress("0x123")
+	some := uint64(42)
+	name := "aptos"
+
+	args := EntryFunctionArgs(
+		AddressArg(recipient),
+		U64Arg(1000),
+		BoolArg(true),
+		StringArg(name),
+		BytesArg([]byte{1, 2, 3}),
+		VectorU64Arg([]uint64{7, 8}),
+		OptionU64Arg(&some),
+		OptionAddressArg(nil),
+	)
+	paramTypes := mustParseTypeTags(t,
+		"signer",
+		"address",
+		"u64",
+		"bool",
+		"0x1::string::String",
+		"vector<u8>",
+		"vector<u64>",
+		"0x1::option::Option<u64>",
+		"0x1::option::Option<address>",
+	)
+
+	values, err := DecodeEntryFunctionArgs(args, paramTypes)
+	if err != nil {
+		t.Fatalf("DecodeEntryFunctionArgs error: %v", err)
+	}
+	if len(values) != len(args) {
+		t.Fatalf("got %d values, want %d", len(values), len(args))
+	}
+	if got := values[0].(AccountAddress); got != recipient {
+		t.Errorf("address = %v, want %v", got, recipient)
+	}
+	if got := values[1].(uint64); got != 1000 {
+		t.Errorf("u64 = %v, want 1000", got)
+	}
+	if got := values[2].(bool); !got {
+		t.Errorf("bool = %v, want true", got)
+	}
+	if got := values[3].(string); got != name {
+		t.Errorf("string = %q, want %q", got, name)
+	}
+	if got := values[4].([]byte); !bytes.Equal(got, []byte{1, 2, 3}) {
+		t.Errorf("vector<u8> = %v, want [1 2 3]", got)
+	}
+	if got := values[5].([]interface{}); len(got) != 2 || got[0].(uint64) != 7 || got[1].(uint64) != 8 {
+		t.Errorf("vector<u64> = %v, want [7 8]", got)
+	}
+	if got := values[6].(uint64); got != some {
+		t.Errorf("Option<u64> = %v, want %d", got, some)
+	}
+	if values[7] != nil {
+		t.Errorf("Option<address> = %v, want nil", values[7])
+	}
+}
+
+func TestDecodeEntryFunctionArgRoundTrip(t *testing.T) {
+	addr := MustParseAccountAddress("0xcafe")
+	u64 := uint64(42)
+	str := "aptos"
+	flag := true
+	data := []byte{1, 2}
+	big128, _ := new(big.Int).SetString("340282366920938463463374607431768211455", 10)
+	big256, _ := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+
+	tests := []struct {
+		name    string
+		arg     EntryFunctionArg
+		argType string
+		want    interface{}
+	}{
+		{"bool", BoolArg(true), "bool", true},
+		{"u8", U8Arg(7), "u8", uint8(7)},
+		{"u16", U16Arg(700), "u16", uint16(700)},
+		{"u32", U32Arg(70000), "u32", uint32(70000)},
+		{"u64", U64Arg(u64), "u64", u64},
+		{"u128", U128Arg(big128), "u128", NewU128FromBigInt(big128)},
+		{"u256", U256Arg(big256), "u256", NewU256FromBigInt(big256)},
+		{"address", AddressArg(addr), "address", addr},
+		{"string", StringArg(str), "0x1::string::String", str},
+		{"bytes", BytesArg(data), "vector<u8>", data},
+		{"vector u8", VectorU8Arg(data), "vector<u8>", data},
+		{"vector u16", VectorU16Arg([]uint16{1, 2}), "vector<u16>", []interface{}{uint16(1), uint16(2)}},
+		{"vector u64", VectorU64Arg([]uint64{3, 4}), "vector<u64>", []interface{}{uint64(3), uint64(4)}},
+		{"vector address", VectorAddressArg([]AccountAddress{addr, AccountOne}), "vector<address>", []interface{}{addr, AccountOne}},
+		{"vector bytes", VectorBytesArg([][]byte{data, {}}), "vector<vector<u8>>", []interface{}{data, []byte{}}},
+		{"vector string", VectorStringArg([]string{str, ""}), "vector<0x1::string::String>", []interface{}{str, ""}},
+		{"option u64 some", OptionU64Arg(&u64), "0x1::option::Option<u64>", u64},
+		{"option u64 none", OptionU64Arg(nil), "0x1::option::Option<u64>", nil},
+		{"option address", OptionAddressArg(&addr), "0x1::option::Option<address>", addr},
+		{"option string", OptionStringArg(&str), "0x1::option::Option<0x1::string::String>", str},
+		{"option bool", OptionBoolArg(&flag), "0x1::option::Option<bool>", true},
+		{"option u128", OptionU128Arg(big128), "0x1::option::Option<u128>", NewU128FromBigInt(big128)},
+		{"option bytes", OptionArg(&data, BytesArg), "0x1::option::Option<vector<u8>>", data},
+		{"object", ObjectArg(addr), "0x1::object::Object<0x1::object::ObjectCore>", addr},
+		{"vector object", VectorObjectArg([]AccountAddress{addr}), "vector<0x1::object::Object<0x1::object::ObjectCore>>", []interface{}{addr}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeEntryFunctionArg(tt.arg, mustParseTypeTags(t, tt.argType)[0])
+			if err != nil {
+				t.Fatalf("DecodeEntryFunctionArg error: %v", err)
+			}
+			if reflect.TypeOf(got) != reflect.TypeOf(tt.want) || fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("DecodeEntryFunctionArg = %T %v, want %T %v", got, got, tt.want, tt.want)
+			}
+		})
+	}
+
+	// Nested vectors have no constructor: vector<vector<u64>> [[1], []]
+	nested := []byte{2, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0}
+	got, err := DecodeEntryFunctionArg(nested, mustParseTypeTags(t, "vector<vector<u64>>")[0])
+	if err != nil {
+		t.Fatalf("DecodeEntryFunctionArg error: %v", err)
+	}
+	if fmt.Sprint(got) != "[[1] []]" {
+		t.Errorf("vector<vector<u64>> = %v, want [[1] []]", got)
+	}
+}
+
+func TestDecodeEntryFunctionArgsErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       [][]byte
+		paramTypes []string
+	}{
+		{"count mismatch", EntryFunctionArgs(U64Arg(1)), []string{"u64", "u64"}},
+		{"short input", [][]byte{{1, 2}}, []string{"u64"}},
+		{"trailing bytes", EntryFunctionArgs(U64Arg(1)), []string{"u32"}},
+		{"unsupported struct", EntryFunctionArgs(U64Arg(1)), []string{"0x1::coin::Coin"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeEntryFunctionArgs(tt.args, mustParseTypeTags(t, tt.paramTypes...))
+			if err == nil {
+				t.Error("DecodeEntryFunctionArgs should have failed")
+			}
+		})
+	}
+}
+
+func TestEntryFunctionArgsToJSON(t *testing.T) {
+	recipient := MustParseAccountAddress("0x123")
+	some := uint64(42)
+	data := []byte{0xab}
+
+	args := EntryFunctionArgs(
+		AddressArg(recipient),
+		U64Arg(1000),
+		U8Arg(7),
+		BoolArg(true),
+		StringArg("aptos"),
+		BytesArg([]byte{1, 2, 3}),
+		VectorU64Arg([]uint64{7, 8}),
+		U128Arg(big.NewInt(5)),
+		OptionU64Arg(&some),
+		OptionAddressArg(nil),
+		OptionArg(&data, BytesArg),
+	)
+	paramTypes := append([]TypeTag{mustParseMoveType(t, "&signer")}, mustParseTypeTags(t,
+		"address",
+		"u64",
+		"u8",
+		"bool",
+		"0x1::string::String",
+		"vector<u8>",
+		"vector<u64>",
+		"u128",
+		"0x1::option::Option<u64>",
+		"0x1::option::Option<address>",
+		"0x1::option::Option<vector<u8>>",
+	)...)
+
+	values, err := EntryFunctionArgsToJSON(args, paramTypes)
+	if err != nil {
+		t.Fatalf("EntryFunctionArgsToJSON error: %v", err)
+	}
+	got, err := json.Marshal(values)
+	if err != nil {
+		t.Fatalf("json.Marshal error: %v", err)
+	}
+	want := `["` + recipient.String() + `","1000",7,true,"aptos","0x010203",["7","8"],"5",{"vec":["42"]},{"vec":[]},{"vec":["0xab"]}]`
+	if string(got) != want {
+		t.Errorf("EntryFunctionArgsToJSON =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := EntryFunctionArgsToJSON(EntryFunctionArgs(U64Arg(1)), mustParseTypeTags(t, "u32")); err == nil {
+		t.Error("EntryFunctionArgsToJSON should fail on mismatched types")
+	}
+}
+
+func mustParseTypeTags(t *testing.T, types ...string) []TypeTag {
+	t.Helper()
+	tags := make([]TypeTag, len(types))
+	for i, s := range types {
+		tag, err := ParseTypeTag(s)
+		if err != nil {
+			t.Fatalf("ParseTypeTag(%q) error: %v", s, err)
+		}
+		tags[i] = tag
+	}
+	return tags
+}
+
+func TestOptionArg(t *testing.T) {
+	flag := true
+	data := []byte{0xaa, 0xbb}
+	some := uint64(5)
+
+	tests := []struct {
+		name string
+		got  EntryFunctionArg
+		want []byte
+	}{
+		{"none", OptionArg[uint64](nil, U64Arg), []byte{0}},
+		{"some u64 matches OptionU64Arg", OptionArg(&some, U64Arg), OptionU64Arg(&some)},
+		{"some bool", OptionBoolArg(&flag), []byte{1, 1}},
+		{"none bool", OptionBoolArg(nil), []byte{0}},
+		{"some u128", OptionU128Arg(big.NewInt(1)), append([]byte{1, 1}, make([]byte, 15)...)},
+		{"none u128", OptionU128Arg(nil), []byte{0}},
+		{"some vector<u8>", OptionArg(&data, BytesArg), []byte{1, 2, 0xaa, 0xbb}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !bytes.Equal(tt.got, tt.want) {
+				t.Errorf("got %x, want %x", []byte(tt.got), tt.want)
+			}
+		})
+	}
+}
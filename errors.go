@@ -1,20 +1,25 @@
 package aptos
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"strings"
 )
 
 // Common error codes returned by the Aptos API.
 const (
-	ErrCodeAccountNotFound  = "account_not_found"
-	ErrCodeResourceNotFound = "resource_not_found"
-	ErrCodeModuleNotFound   = "module_not_found"
-	ErrCodeVersionPruned    = "version_pruned"
-	ErrCodeInvalidInput     = "invalid_input"
-	ErrCodeMempoolFull      = "mempool_is_full"
-	ErrCodeVMError          = "vm_error"
-	ErrCodeInternalError    = "internal_error"
+	ErrCodeAccountNotFound     = "account_not_found"
+	ErrCodeResourceNotFound    = "resource_not_found"
+	ErrCodeModuleNotFound      = "module_not_found"
+	ErrCodeTransactionNotFound = "transaction_not_found"
+	ErrCodeVersionPruned       = "version_pruned"
+	ErrCodeInvalidInput        = "invalid_input"
+	ErrCodeMempoolFull         = "mempool_is_full"
+	ErrCodeVMError             = "vm_error"
+	ErrCodeInternalError       = "internal_error"
 )
 
 // APIError represents an error response from the Aptos API.
@@ -60,6 +65,11 @@ var (
 	// ErrModuleNotFound is returned when the requested module does not exist.
 	ErrModuleNotFound = &APIError{ErrorCode: ErrCodeModuleNotFound}
 
+	// ErrTransactionNotFound is returned when the requested transaction does
+	// not exist, e.g. by GetTransactionByHash for a hash that has not been
+	// committed yet. It matches only the node's transaction_not_found code.
+	ErrTransactionNotFound = &APIError{ErrorCode: ErrCodeTransactionNotFound}
+
 	// ErrVersionPruned is returned when the requested version has been pruned.
 	ErrVersionPruned = &APIError{ErrorCode: ErrCodeVersionPruned}
 
@@ -70,11 +80,14 @@ var (
 	ErrMempoolFull = &APIError{ErrorCode: ErrCodeMempoolFull}
 )
 
-// IsNotFound returns true if the error indicates a resource was not found.
+// IsNotFound returns true if the error carries one of the node's not-found
+// error codes: account, resource, module, or transaction not found. Unlike
+// ErrorKind, it does not treat a 404 without such a code as not found.
 func IsNotFound(err error) bool {
 	return errors.Is(err, ErrAccountNotFound) ||
 		errors.Is(err, ErrResourceNotFound) ||
-		errors.Is(err, ErrModuleNotFound)
+		errors.Is(err, ErrModuleNotFound) ||
+		errors.Is(err, ErrTransactionNotFound)
 }
 
 // IsAccountNotFound returns true if the error indicates the account was not found.
@@ -87,6 +100,12 @@ func IsResourceNotFound(err error) bool {
 	return errors.Is(err, ErrResourceNotFound)
 }
 
+// IsTransactionNotFound returns true if the error indicates the transaction
+// was not found, e.g. because it has not been submitted or committed yet.
+func IsTransactionNotFound(err error) bool {
+	return errors.Is(err, ErrTransactionNotFound)
+}
+
 // IsVersionPruned returns true if the error indicates the version was pruned.
 func IsVersionPruned(err error) bool {
 	return errors.Is(err, ErrVersionPruned)
@@ -96,3 +115,211 @@ func IsVersionPruned(err error) bool {
 func IsMempoolFull(err error) bool {
 	return errors.Is(err, ErrMempoolFull)
 }
+
+// ErrEndpointDeprecated is returned when the node no longer serves an endpoint,
+// such as the per-account event handle endpoints that have been superseded by
+// the indexer. The underlying *APIError is still available via errors.As.
+var ErrEndpointDeprecated = errors.New("aptos: endpoint is deprecated or unsupported by this node; query the indexer instead")
+
+// IsEndpointDeprecated returns true if the error indicates the endpoint is deprecated.
+func IsEndpointDeprecated(err error) bool {
+	return errors.Is(err, ErrEndpointDeprecated)
+}
+
+// wrapDeprecatedEndpoint wraps err with ErrEndpointDeprecated if the node
+// reported the endpoint as gone, unimplemented, or deprecated.
+func wrapDeprecatedEndpoint(err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	if apiErr.StatusCode == http.StatusGone ||
+		apiErr.StatusCode == http.StatusNotImplemented ||
+		strings.Contains(strings.ToLower(apiErr.Message), "deprecated") {
+		return fmt.Errorf("%w: %w", ErrEndpointDeprecated, err)
+	}
+	return err
+}
+
+// ErrNodeLagging is returned by HealthCheckWithin when the node's latest
+// ledger timestamp is older than the allowed lag. The underlying *APIError is
+// still available via errors.As.
+var ErrNodeLagging = errors.New("aptos: node is lagging behind the network")
+
+// IsNodeLagging returns true if the error indicates the node is lagging.
+func IsNodeLagging(err error) bool {
+	return errors.Is(err, ErrNodeLagging)
+}
+
+// ErrNonceReused is returned by NonceSource.Reserve when a replay protection
+// nonce was already used by a transaction that may not have expired yet.
+var ErrNonceReused = errors.New("aptos: replay protection nonce already used")
+
+// ErrNameNotFound is returned by ResolveName when a name is not registered,
+// has expired, or has no target address, and by ReverseLookup when an
+// address has no primary name.
+var ErrNameNotFound = errors.New("aptos: name not found")
+
+// ErrDuplicateSubmission is returned by SubmitTransaction and
+// SubmitTransactionHash, when ClientConfig.TrackSubmissions is set, for a
+// transaction that was already submitted through the client and has not
+// expired, or that reuses the sequence number of such a transaction without
+// raising the gas unit price. The node is not contacted.
+var ErrDuplicateSubmission = errors.New("aptos: duplicate transaction submission")
+
+// ErrTransactionTimeout is returned by WaitForTransactionByHash when the
+// context's deadline passes while the transaction is still pending. The
+// transaction may yet commit; it wraps context.DeadlineExceeded, so ErrorKind
+// reports ErrKindTimeout.
+var ErrTransactionTimeout = errors.New("aptos: transaction still pending at deadline")
+
+// isTransient reports whether a request may succeed if retried: it was rate
+// limited, failed on the server, timed out, or did not reach the node.
+func isTransient(err error) bool {
+	switch ErrorKind(err) {
+	case ErrKindRateLimited, ErrKindServerError, ErrKindTimeout, ErrKindNetworkError:
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrKind is a stable classification of an error returned by the SDK.
+type ErrKind int
+
+const (
+	// ErrKindUnknown is any error that does not fit another kind, including nil.
+	ErrKindUnknown ErrKind = iota
+
+	// ErrKindNotFound is an API error for a missing account, resource,
+	// module, transaction, or other object.
+	ErrKindNotFound
+
+	// ErrKindRateLimited is an API error with status 429 Too Many Requests.
+	ErrKindRateLimited
+
+	// ErrKindServerError is an API error with a 5xx status, such as an
+	// internal error or a full mempool.
+	ErrKindServerError
+
+	// ErrKindTimeout is a request that exceeded its context deadline or the
+	// HTTP client timeout.
+	ErrKindTimeout
+
+	// ErrKindNetworkError is a transport failure, such as a refused
+	// connection or a DNS error, before the node answered.
+	ErrKindNetworkError
+
+	// ErrKindInvalidInput is an API error with a 400 status, other than a VM error.
+	ErrKindInvalidInput
+
+	// ErrKindVMError is an API error reported by the Move VM, e.g. a
+	// transaction rejected during validation or a failed view function.
+	ErrKindVMError
+
+	// ErrKindCanceled is a request whose context was canceled.
+	ErrKindCanceled
+
+	// ErrKindInsufficientBalance is a VM error for an account that cannot
+	// pay for the transaction or its transfers; see IsInsufficientBalance.
+	ErrKindInsufficientBalance
+)
+
+// String returns the name of the kind.
+func (k ErrKind) String() string {
+	switch k {
+	case ErrKindNotFound:
+		return "not_found"
+	case ErrKindRateLimited:
+		return "rate_limited"
+	case ErrKindServerError:
+		return "server_error"
+	case ErrKindTimeout:
+		return "timeout"
+	case ErrKindNetworkError:
+		return "network_error"
+	case ErrKindInvalidInput:
+		return "invalid_input"
+	case ErrKindVMError:
+		return "vm_error"
+	case ErrKindCanceled:
+		return "canceled"
+	case ErrKindInsufficientBalance:
+		return "insufficient_balance"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrorKind classifies err so callers can switch on a single value instead of
+// inspecting API errors, transport errors, and context errors separately.
+// API errors are classified by error code and then by status code.
+func ErrorKind(err error) ErrKind {
+	if err == nil {
+		return ErrKindUnknown
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return ErrKindRateLimited
+		case isInsufficientBalanceError(apiErr):
+			return ErrKindInsufficientBalance
+		case apiErr.ErrorCode == ErrCodeVMError || apiErr.VMErrorCode != nil:
+			return ErrKindVMError
+		case IsNotFound(apiErr) || apiErr.StatusCode == http.StatusNotFound:
+			return ErrKindNotFound
+		case apiErr.ErrorCode == ErrCodeInvalidInput || apiErr.StatusCode == http.StatusBadRequest:
+			return ErrKindInvalidInput
+		case apiErr.StatusCode >= 500:
+			return ErrKindServerError
+		default:
+			return ErrKindUnknown
+		}
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return ErrKindCanceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrKindTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrKindTimeout
+		}
+		return ErrKindNetworkError
+	}
+	return ErrKindUnknown
+}
+
+// vmStatusInsufficientBalanceForFee is the VM error code of the
+// INSUFFICIENT_BALANCE_FOR_TRANSACTION_FEE validation status.
+const vmStatusInsufficientBalanceForFee = 5
+
+// IsInsufficientBalance returns true if the error indicates the sender cannot
+// pay for a transaction: the node rejected it with
+// INSUFFICIENT_BALANCE_FOR_TRANSACTION_FEE, or it aborted with
+// EINSUFFICIENT_BALANCE. Use UserTransaction.IsInsufficientBalance for the
+// result of a simulation or a committed transaction.
+func IsInsufficientBalance(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && isInsufficientBalanceError(apiErr)
+}
+
+func isInsufficientBalanceError(e *APIError) bool {
+	if e.VMErrorCode != nil && *e.VMErrorCode == vmStatusInsufficientBalanceForFee {
+		return true
+	}
+	return isInsufficientBalanceStatus(e.Message)
+}
+
+// isInsufficientBalanceStatus reports whether a VM status describes missing
+// funds. It matches both the INSUFFICIENT_BALANCE_FOR_TRANSACTION_FEE
+// validation status and EINSUFFICIENT_BALANCE aborts, such as
+// "Move abort in 0x1::coin: EINSUFFICIENT_BALANCE(0x10006): ...".
+func isInsufficientBalanceStatus(status string) bool {
+	return strings.Contains(status, "INSUFFICIENT_BALANCE")
+}
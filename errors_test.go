@@ -0,0 +1,121 @@
+package aptos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestWrapDeprecatedEndpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"gone", &APIError{StatusCode: http.StatusGone, Message: "gone"}, true},
+		{"not implemented", &APIError{StatusCode: http.StatusNotImplemented}, true},
+		{"deprecated message", &APIError{StatusCode: http.StatusBadRequest, Message: "This API is Deprecated"}, true},
+		{"not found", &APIError{StatusCode: http.StatusNotFound, ErrorCode: ErrCodeResourceNotFound}, false},
+		{"other error", fmt.Errorf("request failed"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapDeprecatedEndpoint(tt.err)
+			if got := IsEndpointDeprecated(err); got != tt.want {
+				t.Errorf("IsEndpointDeprecated() = %v, want %v", got, tt.want)
+			}
+			// The original error must remain accessible
+			if !errors.Is(err, tt.err) {
+				t.Errorf("wrapped error does not match the original")
+			}
+		})
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	vmCode := uint64(4016)
+	tests := []struct {
+		name string
+		err  error
+		want ErrKind
+	}{
+		{"nil", nil, ErrKindUnknown},
+		{"not found code", &APIError{StatusCode: http.StatusNotFound, ErrorCode: ErrCodeAccountNotFound}, ErrKindNotFound},
+		{"not found status", &APIError{StatusCode: http.StatusNotFound, Message: "no route"}, ErrKindNotFound},
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, ErrKindRateLimited},
+		{"server error", &APIError{StatusCode: http.StatusInternalServerError, ErrorCode: ErrCodeInternalError}, ErrKindServerError},
+		{"mempool full", &APIError{StatusCode: http.StatusInsufficientStorage, ErrorCode: ErrCodeMempoolFull}, ErrKindServerError},
+		{"invalid input", &APIError{StatusCode: http.StatusBadRequest, ErrorCode: ErrCodeInvalidInput}, ErrKindInvalidInput},
+		{"vm error", &APIError{StatusCode: http.StatusBadRequest, ErrorCode: ErrCodeVMError, VMErrorCode: &vmCode}, ErrKindVMError},
+		{"wrapped api error", fmt.Errorf("get account: %w", &APIError{StatusCode: http.StatusBadGateway}), ErrKindServerError},
+		{"canceled", fmt.Errorf("request failed: %w", &url.Error{Op: "Get", URL: "http://node", Err: context.Canceled}), ErrKindCanceled},
+		{"deadline", fmt.Errorf("request failed: %w", &url.Error{Op: "Get", URL: "http://node", Err: context.DeadlineExceeded}), ErrKindTimeout},
+		{"network timeout", &net.OpError{Op: "dial", Err: timeoutError{}}, ErrKindTimeout},
+		{"network error", fmt.Errorf("request failed: %w", &url.Error{Op: "Get", URL: "http://node", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}), ErrKindNetworkError},
+		{"other", errors.New("boom"), ErrKindUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorKind(tt.err); got != tt.want {
+				t.Errorf("ErrorKind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsInsufficientBalance(t *testing.T) {
+	feeCode := uint64(vmStatusInsufficientBalanceForFee)
+	otherCode := uint64(3)
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"fee validation code", &APIError{StatusCode: http.StatusBadRequest, ErrorCode: ErrCodeVMError, VMErrorCode: &feeCode}, true},
+		{"fee validation message", fmt.Errorf("submit: %w", &APIError{StatusCode: http.StatusBadRequest, ErrorCode: ErrCodeVMError,
+			Message: "Invalid transaction: Type: Validation Code: INSUFFICIENT_BALANCE_FOR_TRANSACTION_FEE"}), true},
+		{"other vm error", &APIError{StatusCode: http.StatusBadRequest, ErrorCode: ErrCodeVMError, VMErrorCode: &otherCode,
+			Message: "Invalid transaction: Type: Validation Code: SEQUENCE_NUMBER_TOO_OLD"}, false},
+		{"not an api error", errors.New("INSUFFICIENT_BALANCE"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsInsufficientBalance(tt.err); got != tt.want {
+				t.Errorf("IsInsufficientBalance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := ErrorKind(&APIError{StatusCode: http.StatusBadRequest, VMErrorCode: &feeCode}); got != ErrKindInsufficientBalance {
+		t.Errorf("ErrorKind() = %v, want %v", got, ErrKindInsufficientBalance)
+	}
+
+	statuses := []struct {
+		success  bool
+		vmStatus string
+		want     bool
+	}{
+		{false, "Move abort in 0x1::coin: EINSUFFICIENT_BALANCE(0x10006): Not enough coins to complete transaction", true},
+		{false, "Move abort in 0x1::fungible_asset: EINSUFFICIENT_BALANCE(0x10004): Insufficient balance.", true},
+		{false, "INSUFFICIENT_BALANCE_FOR_TRANSACTION_FEE", true},
+		{false, "Out of gas", false},
+		{true, "Executed successfully", false},
+	}
+	for _, tt := range statuses {
+		txn := UserTransaction{Success: tt.success, VMStatus: tt.vmStatus}
+		if got := txn.IsInsufficientBalance(); got != tt.want {
+			t.Errorf("IsInsufficientBalance(%q) = %v, want %v", tt.vmStatus, got, tt.want)
+		}
+	}
+}
@@ -1,27 +1,100 @@
 package aptos
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"sync"
+)
 
 // Event represents an on-chain event.
 type Event struct {
 	GUID           EventGUID       `json:"guid"`
-	SequenceNumber string          `json:"sequence_number"`
+	SequenceNumber JSONUint64      `json:"sequence_number"`
 	Type           string          `json:"type"`
 	Data           json.RawMessage `json:"data"`
 }
 
+// IsModuleEvent reports whether this is a module event (AIP-44), emitted with
+// 0x1::event::emit. Module events are identified by their type alone and
+// are not tied to an account's event handle, so the node reports them with a
+// zero GUID and sequence number; they can only be found by scanning
+// transaction events or through the indexer, not with GetEventsByEventHandle.
+// Handle-based events always have the owning account's address in their GUID.
+func (e *Event) IsModuleEvent() bool {
+	if e.GUID.AccountAddress == "" {
+		return true
+	}
+	addr, err := ParseAccountAddress(e.GUID.AccountAddress)
+	return err == nil && addr.IsZero() && e.GUID.CreationNumber == 0
+}
+
 // EventGUID is the globally unique identifier for an event stream.
 type EventGUID struct {
-	CreationNumber string `json:"creation_number"`
-	AccountAddress string `json:"account_address"`
+	CreationNumber JSONUint64 `json:"creation_number"`
+	AccountAddress string     `json:"account_address"`
 }
 
 // SequenceNumberUint64 returns the sequence number as uint64.
 func (e *Event) SequenceNumberUint64() uint64 {
-	return parseStringToUint64(e.SequenceNumber)
+	return e.SequenceNumber.Uint64()
 }
 
 // DecodeData decodes the event data into the provided type.
 func (e *Event) DecodeData(v interface{}) error {
 	return json.Unmarshal(e.Data, v)
 }
+
+// eventTypes maps event types to constructors of the Go values their data
+// decodes into, for Event.Decode.
+var (
+	eventTypesMu sync.RWMutex
+	eventTypes   = map[string]func() interface{}{
+		FeeStatementEventType: func() interface{} { return new(FeeStatement) },
+	}
+)
+
+// RegisterEventType registers the Go type that Event.Decode decodes events of
+// eventType into. newData returns a pointer to a new value of that type, e.g.
+// func() interface{} { return new(MyEvent) }. Registering a type again
+// replaces the previous registration.
+func RegisterEventType(eventType string, newData func() interface{}) {
+	eventTypesMu.Lock()
+	defer eventTypesMu.Unlock()
+	eventTypes[eventType] = newData
+}
+
+// Decode decodes the event data into the Go type registered for the event's
+// type with RegisterEventType, and returns a pointer to it. A
+// 0x1::transaction_fee::FeeStatement event decodes to a *FeeStatement. It
+// reports false if no type is registered for the event's type.
+func (e *Event) Decode() (interface{}, bool, error) {
+	eventTypesMu.RLock()
+	newData, ok := eventTypes[e.Type]
+	eventTypesMu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	v := newData()
+	if err := e.DecodeData(v); err != nil {
+		return nil, true, err
+	}
+	return v, true, nil
+}
+
+// EventHandle is a 0x1::event::EventHandle stored in a resource, as in the
+// transfer_events field of ObjectCoreResource. Its events can be read with
+// GetEventsByEventHandle using the resource type and field name, or with
+// GetEventsByCreationNumber using GUID.ID.
+type EventHandle struct {
+	// Counter is the number of events emitted to the handle.
+	Counter JSONUint64 `json:"counter"`
+	GUID    struct {
+		ID EventHandleID `json:"id"`
+	} `json:"guid"`
+}
+
+// EventHandleID identifies an event handle by the address that created it
+// and its creation number.
+type EventHandleID struct {
+	Address        AccountAddress `json:"addr"`
+	CreationNumber JSONUint64     `json:"creation_num"`
+}
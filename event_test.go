@@ -0,0 +1,67 @@
+package aptos
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEventIsModuleEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want bool
+	}{
+		{
+			name: "module event",
+			json: `{"guid":{"creation_number":"0","account_address":"0x0"},"sequence_number":"0","type":"0x1::fungible_asset::Deposit","data":{}}`,
+			want: true,
+		},
+		{
+			name: "handle event",
+			json: `{"guid":{"creation_number":"2","account_address":"0xcafe"},"sequence_number":"5","type":"0x1::coin::DepositEvent","data":{}}`,
+			want: false,
+		},
+		{
+			name: "first handle of an account",
+			json: `{"guid":{"creation_number":"0","account_address":"0xcafe"},"sequence_number":"0","type":"0x1::account::CoinRegisterEvent","data":{}}`,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event Event
+			if err := json.Unmarshal([]byte(tt.json), &event); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if got := event.IsModuleEvent(); got != tt.want {
+				t.Errorf("IsModuleEvent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventDecode(t *testing.T) {
+	type mintEvent struct {
+		Amount JSONUint64 `json:"amount"`
+	}
+	RegisterEventType("0xcafe::token::Mint", func() interface{} { return new(mintEvent) })
+
+	event := Event{Type: "0xcafe::token::Mint", Data: json.RawMessage(`{"amount":"42"}`)}
+	v, ok, err := event.Decode()
+	if err != nil || !ok {
+		t.Fatalf("Decode() = %v, %v", ok, err)
+	}
+	if mint, isMint := v.(*mintEvent); !isMint || mint.Amount.Uint64() != 42 {
+		t.Errorf("Decode() = %#v, want &mintEvent{Amount: 42}", v)
+	}
+
+	event = Event{Type: "0xcafe::token::Mint", Data: json.RawMessage(`{"amount":true}`)}
+	if _, ok, err := event.Decode(); !ok || err == nil {
+		t.Errorf("Decode() of invalid data = %v, %v; want true and an error", ok, err)
+	}
+
+	event = Event{Type: "0xcafe::token::Burn", Data: json.RawMessage(`{}`)}
+	if v, ok, err := event.Decode(); ok || err != nil || v != nil {
+		t.Errorf("Decode() of unregistered type = %v, %v, %v; want nil, false, nil", v, ok, err)
+	}
+}
@@ -0,0 +1,51 @@
+// Package main demonstrates wrapping the client's HTTP transport, here with a
+// RoundTripper that logs every request.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	aptos "github.com/0xbe1/aptopher"
+)
+
+// loggingTransport logs each request and its latency, then returns the
+// response unmodified so the client can still read the X-Aptos-* headers.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		log.Printf("%s %s failed after %s: %v", req.Method, req.URL.Path, time.Since(start), err)
+		return nil, err
+	}
+	log.Printf("%s %s -> %d in %s", req.Method, req.URL.Path, resp.StatusCode, time.Since(start))
+	return resp, nil
+}
+
+func main() {
+	config := aptos.MainnetConfig
+	config.Transport = &loggingTransport{next: http.DefaultTransport}
+
+	client, err := aptos.NewClient(config)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Response metadata is parsed from headers that pass through the wrapper
+	account, err := client.GetAccount(ctx, aptos.AccountOne)
+	if err != nil {
+		log.Fatalf("Failed to get account: %v", err)
+	}
+	fmt.Printf("Sequence Number: %s\n", account.Data.SequenceNumber)
+	fmt.Printf("Chain ID: %d\n", account.Metadata.ChainID)
+	fmt.Printf("Ledger Version: %d\n", account.Metadata.LedgerVersion)
+}
@@ -60,23 +60,9 @@ func main() {
 		ChainID:                 4,          // Devnet
 	}
 
-	// Create a fake signature for simulation (all zeros)
-	fakeSignedTxn := &aptos.SignedTransaction{
-		RawTxn: rawTxn,
-		Authenticator: aptos.TransactionAuthenticator{
-			Variant: aptos.TransactionAuthenticatorSingleSender,
-			Auth: &aptos.AccountAuthenticatorSingleKey{
-				PublicKey: aptos.AnyPublicKey{
-					Variant:   account.Signer.Scheme(),
-					PublicKey: account.Signer.PublicKey(),
-				},
-				Signature: aptos.AnySignature{
-					Variant:   account.Signer.Scheme(),
-					Signature: make([]byte, 64), // Zero signature for simulation
-				},
-			},
-		},
-	}
+	// Create a fake signature for simulation (all zeros), with the
+	// authenticator that matches how the account's address was derived
+	fakeSignedTxn := rawTxn.SimulationTransaction(account.Signer.PublicKey(), account.Signer.Scheme())
 
 	txnBytes, err := fakeSignedTxn.Bytes()
 	if err != nil {
@@ -0,0 +1,89 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// orderlessTransactionsFeature is the on-chain feature flag for orderless
+// transactions in 0x1::features.
+const orderlessTransactionsFeature = 94
+
+// NodeFeatures describes optional capabilities of a node and its network,
+// as detected by Client.DetectFeatures.
+type NodeFeatures struct {
+	// GitHash is the node's build, as reported by GetNodeInfo.
+	GitHash string
+
+	// SupportsBalanceEndpoint reports whether the node serves
+	// GET /accounts/{address}/balance/{asset_type} (used by GetAccountBalance).
+	SupportsBalanceEndpoint bool
+
+	// SupportsOrderless reports whether orderless transactions
+	// (WithReplayProtectionNonce) are enabled on-chain.
+	SupportsOrderless bool
+}
+
+// DetectFeatures probes the node for optional capabilities so callers can
+// choose code paths at startup instead of failing on unsupported features.
+// Probes that fail with an API error mark the feature as unsupported; network
+// errors are returned.
+func (c *Client) DetectFeatures(ctx context.Context) (NodeFeatures, error) {
+	var features NodeFeatures
+
+	info, err := c.GetNodeInfo(ctx)
+	if err != nil {
+		return NodeFeatures{}, err
+	}
+	features.GitHash = info.Data.GitHash
+
+	_, err = c.GetAccountBalance(ctx, AccountOne, AptosCoinType)
+	if features.SupportsBalanceEndpoint, err = probeEndpoint(err); err != nil {
+		return NodeFeatures{}, err
+	}
+
+	features.SupportsOrderless, err = c.isFeatureEnabled(ctx, orderlessTransactionsFeature)
+	if err != nil {
+		return NodeFeatures{}, err
+	}
+
+	return features, nil
+}
+
+// probeEndpoint interprets the error of a probe request. The endpoint exists
+// if the request succeeded or the API answered with a structured error (e.g.
+// resource_not_found); an unstructured API error means the route is unknown.
+func probeEndpoint(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false, err
+	}
+	return apiErr.ErrorCode != "", nil
+}
+
+// isFeatureEnabled reports whether an on-chain feature flag is enabled.
+// Nodes that cannot answer the query report the feature as disabled.
+func (c *Client) isFeatureEnabled(ctx context.Context, feature uint64) (bool, error) {
+	result, err := c.View(ctx, ViewRequest{
+		Function:  "0x1::features::is_enabled",
+		Arguments: []interface{}{strconv.FormatUint(feature, 10)},
+	})
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var enabled bool
+	if len(result.Data) == 0 || json.Unmarshal(result.Data[0], &enabled) != nil {
+		return false, nil
+	}
+	return enabled, nil
+}
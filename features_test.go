@@ -0,0 +1,31 @@
+package aptos
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProbeEndpoint(t *testing.T) {
+	networkErr := errors.New("connection refused")
+
+	tests := []struct {
+		name      string
+		err       error
+		supported bool
+		wantErr   error
+	}{
+		{"success", nil, true, nil},
+		{"structured api error", &APIError{StatusCode: 404, ErrorCode: ErrCodeResourceNotFound}, true, nil},
+		{"unknown route", &APIError{StatusCode: 404, Message: "not found"}, false, nil},
+		{"network error", networkErr, false, networkErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			supported, err := probeEndpoint(tt.err)
+			if supported != tt.supported || err != tt.wantErr {
+				t.Errorf("probeEndpoint(%v) = (%v, %v), want (%v, %v)", tt.err, supported, err, tt.supported, tt.wantErr)
+			}
+		})
+	}
+}
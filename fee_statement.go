@@ -0,0 +1,49 @@
+package aptos
+
+// FeeStatementEventType is the type of the event that reports the fee
+// breakdown of every committed user transaction.
+const FeeStatementEventType = "0x1::transaction_fee::FeeStatement"
+
+// FeeStatement is the gas and storage fee breakdown of a committed user
+// transaction, from its 0x1::transaction_fee::FeeStatement event.
+//
+// The gas fee is TotalChargeGasUnits times the gas unit price, and includes
+// StorageFeeOctas. StorageFeeRefundOctas is refunded to the gas payer for
+// freed storage, so the net cost is the gas fee minus the refund.
+type FeeStatement struct {
+	TotalChargeGasUnits   uint64 `json:"total_charge_gas_units,string"`
+	ExecutionGasUnits     uint64 `json:"execution_gas_units,string"`
+	IOGasUnits            uint64 `json:"io_gas_units,string"`
+	StorageFeeOctas       uint64 `json:"storage_fee_octas,string"`
+	StorageFeeRefundOctas uint64 `json:"storage_fee_refund_octas,string"`
+}
+
+// GasFeeOctas returns the gas fee charged at gasUnitPrice, before the
+// storage refund.
+func (fs *FeeStatement) GasFeeOctas(gasUnitPrice uint64) uint64 {
+	return fs.TotalChargeGasUnits * gasUnitPrice
+}
+
+// NetFeeOctas returns the gas fee charged at gasUnitPrice minus the storage
+// refund. It is negative when the transaction freed more storage than it
+// paid for in gas.
+func (fs *FeeStatement) NetFeeOctas(gasUnitPrice uint64) int64 {
+	return int64(fs.GasFeeOctas(gasUnitPrice)) - int64(fs.StorageFeeRefundOctas)
+}
+
+// FeeStatement returns the fee breakdown of a committed user transaction.
+// Returns false if the transaction has no fee statement event, e.g. because
+// it is pending or not a user transaction.
+func (t *Transaction) FeeStatement() (*FeeStatement, bool) {
+	for i := range t.Events {
+		if t.Events[i].Type != FeeStatementEventType {
+			continue
+		}
+		var fs FeeStatement
+		if err := t.Events[i].DecodeData(&fs); err != nil {
+			return nil, false
+		}
+		return &fs, true
+	}
+	return nil, false
+}
@@ -5,6 +5,7 @@ go 1.24.0
 require (
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.0
 	golang.org/x/crypto v0.46.0
+	golang.org/x/sync v0.19.0
 )
 
 require golang.org/x/sys v0.39.0 // indirect
@@ -0,0 +1,298 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/0xbe1/aptopher/internal/hex"
+)
+
+// GovernanceProposalType is the proposal type of on-chain governance, whose
+// voting forum is stored at 0x1.
+const GovernanceProposalType = "0x1::governance_proposal::GovernanceProposal"
+
+// governanceVotingForumType is the voting forum resource holding governance
+// proposals.
+const governanceVotingForumType = "0x1::voting::VotingForum<" + GovernanceProposalType + ">"
+
+// ProposalState is the state of a proposal, as returned by
+// 0x1::voting::get_proposal_state.
+type ProposalState uint8
+
+const (
+	// ProposalStatePending means voting is still open.
+	ProposalStatePending ProposalState = 0
+
+	// ProposalStateSucceeded means voting closed with the proposal passing;
+	// it can be resolved, if it has not been already.
+	ProposalStateSucceeded ProposalState = 1
+
+	// ProposalStateFailed means voting closed without the proposal passing.
+	ProposalStateFailed ProposalState = 3
+)
+
+// String returns the name of the state.
+func (s ProposalState) String() string {
+	switch s {
+	case ProposalStatePending:
+		return "pending"
+	case ProposalStateSucceeded:
+		return "succeeded"
+	case ProposalStateFailed:
+		return "failed"
+	}
+	return "unknown(" + strconv.Itoa(int(s)) + ")"
+}
+
+// ProposalVotes is the vote tally of a proposal, in units of stake.
+type ProposalVotes struct {
+	Yes U128
+	No  U128
+
+	// MinVoteThreshold is the minimum total of yes and no votes for the
+	// proposal to pass.
+	MinVoteThreshold U128
+
+	// EarlyResolutionVoteThreshold, if set, closes voting before the
+	// expiration once either yes or no votes reach it.
+	EarlyResolutionVoteThreshold *U128
+}
+
+// Passing reports whether the proposal would pass if voting closed now: it
+// has more yes than no votes, and enough votes in total.
+func (v ProposalVotes) Passing() bool {
+	yes, no := v.Yes.BigInt(), v.No.BigInt()
+	total := new(big.Int).Add(yes, no)
+	return yes.Cmp(no) > 0 && total.Cmp(v.MinVoteThreshold.BigInt()) >= 0
+}
+
+// earlyResolvable reports whether yes or no votes reached the early
+// resolution threshold.
+func (v ProposalVotes) earlyResolvable() bool {
+	if v.EarlyResolutionVoteThreshold == nil {
+		return false
+	}
+	threshold := v.EarlyResolutionVoteThreshold.BigInt()
+	return v.Yes.BigInt().Cmp(threshold) >= 0 || v.No.BigInt().Cmp(threshold) >= 0
+}
+
+// GovernanceProposal is an on-chain governance proposal, stored in the
+// 0x1::voting::VotingForum at 0x1.
+type GovernanceProposal struct {
+	ID       uint64
+	Proposer AccountAddress
+
+	// Metadata holds the proposal's metadata entries, such as
+	// "metadata_location" (a URL to the proposal description) and
+	// "metadata_hash".
+	Metadata map[string][]byte
+
+	// ExecutionHash is the hash of the script that resolves the proposal.
+	ExecutionHash []byte
+
+	CreationTimeSecs uint64
+	ExpirationSecs   uint64
+	Votes            ProposalVotes
+
+	// State is the state at the ledger timestamp of the response the
+	// proposal was read from.
+	State ProposalState
+
+	IsResolved         bool
+	ResolutionTimeSecs uint64
+}
+
+// MetadataLocation returns the URL of the proposal description, or "" if it
+// has none.
+func (p *GovernanceProposal) MetadataLocation() string {
+	return string(p.Metadata["metadata_location"])
+}
+
+// UnmarshalJSON implements json.Unmarshaler for a 0x1::voting::Proposal. ID and
+// State are not part of the resource and are left unset.
+func (p *GovernanceProposal) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Proposer AccountAddress `json:"proposer"`
+		Metadata struct {
+			Data []struct {
+				Key   string `json:"key"`
+				Value string `json:"value"`
+			} `json:"data"`
+		} `json:"metadata"`
+		ExecutionHash                string     `json:"execution_hash"`
+		CreationTimeSecs             JSONUint64 `json:"creation_time_secs"`
+		ExpirationSecs               JSONUint64 `json:"expiration_secs"`
+		MinVoteThreshold             U128       `json:"min_vote_threshold"`
+		EarlyResolutionVoteThreshold struct {
+			Vec []U128 `json:"vec"`
+		} `json:"early_resolution_vote_threshold"`
+		YesVotes           U128       `json:"yes_votes"`
+		NoVotes            U128       `json:"no_votes"`
+		IsResolved         bool       `json:"is_resolved"`
+		ResolutionTimeSecs JSONUint64 `json:"resolution_time_secs"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	executionHash, err := hex.Decode(raw.ExecutionHash)
+	if err != nil {
+		return fmt.Errorf("execution_hash: %w", err)
+	}
+	*p = GovernanceProposal{
+		Proposer:      raw.Proposer,
+		Metadata:      make(map[string][]byte, len(raw.Metadata.Data)),
+		ExecutionHash: executionHash,
+		Votes: ProposalVotes{
+			Yes:              raw.YesVotes,
+			No:               raw.NoVotes,
+			MinVoteThreshold: raw.MinVoteThreshold,
+		},
+		CreationTimeSecs:   raw.CreationTimeSecs.Uint64(),
+		ExpirationSecs:     raw.ExpirationSecs.Uint64(),
+		IsResolved:         raw.IsResolved,
+		ResolutionTimeSecs: raw.ResolutionTimeSecs.Uint64(),
+	}
+	for _, entry := range raw.Metadata.Data {
+		value, err := hex.Decode(entry.Value)
+		if err != nil {
+			return fmt.Errorf("metadata %s: %w", entry.Key, err)
+		}
+		p.Metadata[entry.Key] = value
+	}
+	if len(raw.EarlyResolutionVoteThreshold.Vec) > 0 {
+		threshold := raw.EarlyResolutionVoteThreshold.Vec[0]
+		p.Votes.EarlyResolutionVoteThreshold = &threshold
+	}
+	return nil
+}
+
+// stateAt returns the state of the proposal at now, following
+// 0x1::voting::get_proposal_state: voting closes once the expiration has
+// passed (is_voting_period_over compares with >, so votes are still accepted
+// at ExpirationSecs itself), or early once the early resolution threshold is
+// reached.
+func (p *GovernanceProposal) stateAt(now time.Time) ProposalState {
+	closed := p.Votes.earlyResolvable() || now.Unix() > int64(p.ExpirationSecs)
+	switch {
+	case !closed:
+		return ProposalStatePending
+	case p.Votes.Passing():
+		return ProposalStateSucceeded
+	default:
+		return ProposalStateFailed
+	}
+}
+
+// governanceVotingForum is the part of the voting forum resource needed to
+// look up proposals.
+type governanceVotingForum struct {
+	Proposals struct {
+		Handle string `json:"handle"`
+	} `json:"proposals"`
+	NextProposalID JSONUint64 `json:"next_proposal_id"`
+}
+
+// getGovernanceVotingForum reads the governance voting forum at 0x1.
+func (c *Client) getGovernanceVotingForum(ctx context.Context, opts ...RequestOption) (Response[governanceVotingForum], error) {
+	resource, err := c.GetAccountResource(ctx, AccountOne, governanceVotingForumType, opts...)
+	if err != nil {
+		return Response[governanceVotingForum]{}, err
+	}
+	var forum governanceVotingForum
+	if err := resource.Data.DecodeData(&forum); err != nil {
+		return Response[governanceVotingForum]{}, fmt.Errorf("decode voting forum: %w", err)
+	}
+	return Response[governanceVotingForum]{Data: forum, Metadata: resource.Metadata}, nil
+}
+
+// getGovernanceProposal reads a proposal from the forum's proposals table.
+func (c *Client) getGovernanceProposal(ctx context.Context, tableHandle string, id uint64, opts ...RequestOption) (Response[GovernanceProposal], error) {
+	item, err := c.GetTableItem(ctx, tableHandle, TableItemRequest{
+		KeyType:   "u64",
+		ValueType: "0x1::voting::Proposal<" + GovernanceProposalType + ">",
+		Key:       strconv.FormatUint(id, 10),
+	}, opts...)
+	if err != nil {
+		return Response[GovernanceProposal]{}, err
+	}
+	var proposal GovernanceProposal
+	if err := json.Unmarshal(item.Data, &proposal); err != nil {
+		return Response[GovernanceProposal]{}, fmt.Errorf("decode proposal %d: %w", id, err)
+	}
+	proposal.ID = id
+	// An unset State would read as pending, so fail rather than guess
+	now := item.Metadata.Time()
+	if now.IsZero() {
+		return Response[GovernanceProposal]{}, fmt.Errorf("proposal %d: response has no ledger timestamp to compute its state at", id)
+	}
+	proposal.State = proposal.stateAt(now)
+	return Response[GovernanceProposal]{Data: proposal, Metadata: item.Metadata}, nil
+}
+
+// forumVersion pins proposal reads to the ledger version the voting forum was
+// read at: the requested version, if any, or else the latest version when it
+// was read.
+func forumVersion(ctx context.Context, metadata ResponseMetadata, opts []RequestOption) RequestOption {
+	if options := ApplyOptionsContext(ctx, opts...); options.LedgerVersion != nil {
+		return WithLedgerVersion(*options.LedgerVersion)
+	}
+	return WithLedgerVersion(metadata.LedgerVersion)
+}
+
+// GetGovernanceProposal retrieves a governance proposal by ID. Its State is
+// computed at the ledger timestamp of the response, and an error is returned
+// if the node did not send one. An unknown ID yields an
+// *APIError with the table_item_not_found error code.
+func (c *Client) GetGovernanceProposal(ctx context.Context, proposalID uint64, opts ...RequestOption) (Response[GovernanceProposal], error) {
+	forum, err := c.getGovernanceVotingForum(ctx, opts...)
+	if err != nil {
+		return Response[GovernanceProposal]{}, err
+	}
+	return c.getGovernanceProposal(ctx, forum.Data.Proposals.Handle, proposalID, forumVersion(ctx, forum.Metadata, opts))
+}
+
+// GetProposalVotes retrieves the vote tally of a governance proposal. Use
+// GetGovernanceProposal for its state and resolution status as well.
+func (c *Client) GetProposalVotes(ctx context.Context, proposalID uint64, opts ...RequestOption) (Response[ProposalVotes], error) {
+	proposal, err := c.GetGovernanceProposal(ctx, proposalID, opts...)
+	if err != nil {
+		return Response[ProposalVotes]{}, err
+	}
+	return Response[ProposalVotes]{Data: proposal.Data.Votes, Metadata: proposal.Metadata}, nil
+}
+
+// GetGovernanceProposals retrieves every governance proposal, in ID order,
+// all read at the same ledger version. Each proposal is a separate request,
+// issued a few at a time. Filter on State to find the proposals still open
+// for voting (ProposalStatePending), or on IsResolved for those that passed
+// and await execution.
+func (c *Client) GetGovernanceProposals(ctx context.Context, opts ...RequestOption) (Response[[]GovernanceProposal], error) {
+	forum, err := c.getGovernanceVotingForum(ctx, opts...)
+	if err != nil {
+		return Response[[]GovernanceProposal]{}, err
+	}
+
+	n := int(forum.Data.NextProposalID.Uint64())
+	proposals := make([]GovernanceProposal, n)
+	version := forumVersion(ctx, forum.Metadata, opts)
+	errs := forEachConcurrent(ctx, n, 0, func(i int) error {
+		proposal, err := c.getGovernanceProposal(ctx, forum.Data.Proposals.Handle, uint64(i), version)
+		proposals[i] = proposal.Data
+		return err
+	})
+	for i, err := range errs {
+		if err != nil {
+			errs[i] = fmt.Errorf("get proposal %d: %w", i, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return Response[[]GovernanceProposal]{}, err
+	}
+	return Response[[]GovernanceProposal]{Data: proposals, Metadata: forum.Metadata}, nil
+}
@@ -0,0 +1,125 @@
+package aptos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGovernanceProposalStateAt(t *testing.T) {
+	const expiration = 1700000000
+	passing := ProposalVotes{Yes: NewU128(60), No: NewU128(40), MinVoteThreshold: NewU128(50)}
+	failing := ProposalVotes{Yes: NewU128(40), No: NewU128(60), MinVoteThreshold: NewU128(50)}
+	threshold := NewU128(60)
+	early := passing
+	early.EarlyResolutionVoteThreshold = &threshold
+
+	tests := []struct {
+		name  string
+		votes ProposalVotes
+		now   int64
+		want  ProposalState
+	}{
+		{"before expiration", passing, expiration - 1, ProposalStatePending},
+		{"at expiration", passing, expiration, ProposalStatePending},
+		{"after expiration", passing, expiration + 1, ProposalStateSucceeded},
+		{"failed after expiration", failing, expiration + 1, ProposalStateFailed},
+		{"early resolution", early, expiration - 1, ProposalStateSucceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := GovernanceProposal{ExpirationSecs: expiration, Votes: tt.votes}
+			if got := p.stateAt(time.Unix(tt.now, 0)); got != tt.want {
+				t.Errorf("stateAt(%d) = %s, want %s", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGovernanceProposals(t *testing.T) {
+	// The ledger timestamp is 1700000000; proposal 1 expires after it
+	proposals := []string{
+		`{"proposer":"0xa","metadata":{"data":[{"key":"metadata_location","value":"0x68747470733a2f2f61"}]},"execution_hash":"0x0102",
+			"creation_time_secs":"1600000000","expiration_secs":"1600604800","min_vote_threshold":"100","early_resolution_vote_threshold":{"vec":[]},
+			"yes_votes":"150","no_votes":"10","is_resolved":true,"resolution_time_secs":"1600700000"}`,
+		`{"proposer":"0xb","metadata":{"data":[]},"execution_hash":"0x",
+			"creation_time_secs":"1699900000","expiration_secs":"1700504800","min_vote_threshold":"100","early_resolution_vote_threshold":{"vec":["1000"]},
+			"yes_votes":"500","no_votes":"0","is_resolved":false,"resolution_time_secs":"0"}`,
+		`{"proposer":"0xc","metadata":{"data":[]},"execution_hash":"0x",
+			"creation_time_secs":"1699900000","expiration_secs":"1700504800","min_vote_threshold":"100","early_resolution_vote_threshold":{"vec":["1000"]},
+			"yes_votes":"0","no_votes":"1000","is_resolved":false,"resolution_time_secs":"0"}`,
+	}
+	var noTimestamp atomic.Bool
+	client, closeServer := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Aptos-Ledger-Version", "77")
+		if !noTimestamp.Load() {
+			w.Header().Set("X-Aptos-Ledger-TimestampUsec", "1700000000000000")
+		}
+		switch r.URL.Path {
+		case "/accounts/0x0000000000000000000000000000000000000000000000000000000000000001/resource/0x1::voting::VotingForum<0x1::governance_proposal::GovernanceProposal>":
+			w.Write([]byte(`{"type":"0x1::voting::VotingForum<0x1::governance_proposal::GovernanceProposal>","data":{
+				"events":{},"next_proposal_id":"3","proposals":{"handle":"0xfeed"}
+			}}`))
+		case "/tables/0xfeed/item":
+			if v := r.URL.Query().Get("ledger_version"); v != "77" {
+				t.Errorf("ledger_version = %q, want 77", v)
+			}
+			var req TableItemRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			id, _ := strconv.Atoi(req.Key.(string))
+			if req.KeyType != "u64" || id >= len(proposals) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message":"not found","error_code":"table_item_not_found"}`))
+				return
+			}
+			w.Write([]byte(proposals[id]))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer closeServer()
+	ctx := context.Background()
+
+	result, err := client.GetGovernanceProposals(ctx)
+	if err != nil {
+		t.Fatalf("GetGovernanceProposals error: %v", err)
+	}
+	if len(result.Data) != 3 {
+		t.Fatalf("got %d proposals, want 3", len(result.Data))
+	}
+	first := result.Data[0]
+	if first.ID != 0 || first.Proposer != MustParseAccountAddress("0xa") || first.MetadataLocation() != "https://a" ||
+		!bytes.Equal(first.ExecutionHash, []byte{1, 2}) || !first.IsResolved || first.ResolutionTimeSecs != 1600700000 {
+		t.Errorf("unexpected proposal 0: %+v", first)
+	}
+	for i, want := range []ProposalState{ProposalStateSucceeded, ProposalStatePending, ProposalStateFailed} {
+		if got := result.Data[i].State; got != want {
+			t.Errorf("proposal %d state = %s, want %s", i, got, want)
+		}
+	}
+
+	votes, err := client.GetProposalVotes(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetProposalVotes error: %v", err)
+	}
+	v := votes.Data
+	if v.Yes.Uint64() != 500 || v.No.Uint64() != 0 || v.MinVoteThreshold.Uint64() != 100 ||
+		v.EarlyResolutionVoteThreshold == nil || v.EarlyResolutionVoteThreshold.Uint64() != 1000 || !v.Passing() {
+		t.Errorf("unexpected votes: %+v", v)
+	}
+
+	if _, err := client.GetGovernanceProposal(ctx, 3); err == nil {
+		t.Error("GetGovernanceProposal(3) succeeded, want not found")
+	}
+
+	// Without a ledger timestamp the state cannot be computed
+	noTimestamp.Store(true)
+	if got, err := client.GetGovernanceProposal(ctx, 1); err == nil {
+		t.Errorf("GetGovernanceProposal without a ledger timestamp = %+v, want an error", got.Data)
+	}
+}
@@ -9,12 +9,30 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // httpClient handles HTTP communication with the Aptos node.
 type httpClient struct {
 	baseURL    string
 	httpClient *http.Client
+
+	// rateLimiter delays requests while the rate-limit quota is exhausted;
+	// nil unless ClientConfig.RespectRateLimit is set.
+	rateLimiter *rateLimiter
+
+	// timeout is the default per-request timeout; zero means none.
+	timeout time.Duration
+
+	// group collapses identical concurrent reads into one request; nil
+	// unless ClientConfig.DeduplicateReads is set.
+	group *singleflight.Group
+
+	// retry resends failed requests that are safe to repeat; nil unless
+	// ClientConfig.MaxRetries is set.
+	retry *retryPolicy
 }
 
 // newHTTPClient creates a new HTTP client for the Aptos API.
@@ -58,6 +76,11 @@ func (c *httpClient) postBCS(ctx context.Context, path string, body []byte, resu
 	return c.doRequestWithContentType(ctx, http.MethodPost, path, bytes.NewReader(body), "application/x.aptos.signed_transaction+bcs", result)
 }
 
+// postBCSGetBCS performs a POST request with a BCS body and returns the raw BCS response.
+func (c *httpClient) postBCSGetBCS(ctx context.Context, path string, body []byte) ([]byte, ResponseMetadata, error) {
+	return c.doRequestBCSWithContentType(ctx, http.MethodPost, path, bytes.NewReader(body), "application/x.aptos.signed_transaction+bcs")
+}
+
 // postJSONGetBCS performs a POST request with JSON body and returns raw BCS response.
 func (c *httpClient) postJSONGetBCS(ctx context.Context, path string, body interface{}) ([]byte, ResponseMetadata, error) {
 	var bodyReader io.Reader
@@ -80,45 +103,9 @@ func (c *httpClient) doRequest(ctx context.Context, method, path string, body io
 }
 
 func (c *httpClient) doRequestWithContentType(ctx context.Context, method, path string, body io.Reader, contentType string, result interface{}) (ResponseMetadata, error) {
-	url := c.baseURL + path
-
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	respBody, metadata, err := c.send(ctx, method, path, body, contentType, "application/json")
 	if err != nil {
-		return ResponseMetadata{}, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Accept", "application/json")
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return ResponseMetadata{}, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Parse response metadata from headers
-	metadata := parseResponseHeaders(resp.Header)
-
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return metadata, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Check for error responses
-	if resp.StatusCode >= 400 {
-		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err != nil {
-			// If we can't parse the error, return a generic one
-			return metadata, &APIError{
-				StatusCode: resp.StatusCode,
-				Message:    string(respBody),
-			}
-		}
-		apiErr.StatusCode = resp.StatusCode
-		return metadata, &apiErr
+		return metadata, err
 	}
 
 	// Decode successful response
@@ -136,14 +123,80 @@ func (c *httpClient) doRequestBCS(ctx context.Context, method, path string, body
 }
 
 func (c *httpClient) doRequestBCSWithContentType(ctx context.Context, method, path string, body io.Reader, contentType string) ([]byte, ResponseMetadata, error) {
+	return c.send(ctx, method, path, body, contentType, "application/x-bcs")
+}
+
+// sharedResponse is the result of a request shared by deduplicated callers.
+type sharedResponse struct {
+	body     []byte
+	metadata ResponseMetadata
+}
+
+// send performs a request and returns the body of a successful response.
+// When deduplication is enabled, identical concurrent reads share a single
+// request.
+func (c *httpClient) send(ctx context.Context, method, path string, body io.Reader, contentType, accept string) ([]byte, ResponseMetadata, error) {
+	if c.group == nil || !isIdempotentRead(method, path) {
+		return c.roundTripWithRetries(ctx, method, path, body, contentType, accept)
+	}
+
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		if bodyBytes, err = io.ReadAll(body); err != nil {
+			return nil, ResponseMetadata{}, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+	key := method + " " + path + "\n" + accept + "\n" + string(bodyBytes)
+
+	// The shared request must not fail because the caller that started it
+	// gave up, so it ignores cancellation; every caller still stops waiting
+	// once its own context is done.
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		var body io.Reader
+		if bodyBytes != nil {
+			body = bytes.NewReader(bodyBytes)
+		}
+		respBody, metadata, err := c.roundTripWithRetries(context.WithoutCancel(ctx), method, path, body, contentType, accept)
+		return sharedResponse{body: respBody, metadata: metadata}, err
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ResponseMetadata{}, fmt.Errorf("request failed: %w", ctx.Err())
+	case res := <-ch:
+		resp := res.Val.(sharedResponse)
+		if res.Shared {
+			// Callers may modify the returned bytes.
+			resp.body = bytes.Clone(resp.body)
+		}
+		return resp.body, resp.metadata, res.Err
+	}
+}
+
+// isIdempotentRead reports whether a request only reads state, so that
+// identical concurrent requests can share a response.
+func isIdempotentRead(method, path string) bool {
+	return method == http.MethodGet || (method == http.MethodPost && strings.HasPrefix(path, "/view"))
+}
+
+// roundTrip sends a single request and returns the body of a successful
+// response. Error responses, which are JSON for every Accept type, are
+// returned as *APIError.
+func (c *httpClient) roundTrip(ctx context.Context, method, path string, body io.Reader, contentType, accept string) ([]byte, ResponseMetadata, error) {
 	url := c.baseURL + path
 
+	if err := c.rateLimiter.wait(ctx); err != nil {
+		return nil, ResponseMetadata{}, err
+	}
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, ResponseMetadata{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Accept", "application/x-bcs")
+	req.Header.Set("Accept", accept)
 	if contentType != "" {
 		req.Header.Set("Content-Type", contentType)
 	}
@@ -156,6 +209,7 @@ func (c *httpClient) doRequestBCSWithContentType(ctx context.Context, method, pa
 
 	// Parse response metadata from headers
 	metadata := parseResponseHeaders(resp.Header)
+	c.rateLimiter.observe(metadata)
 
 	// Read response body
 	respBody, err := io.ReadAll(resp.Body)
@@ -163,10 +217,11 @@ func (c *httpClient) doRequestBCSWithContentType(ctx context.Context, method, pa
 		return nil, metadata, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	// Check for error responses (errors are still JSON)
+	// Check for error responses
 	if resp.StatusCode >= 400 {
 		var apiErr APIError
 		if err := json.Unmarshal(respBody, &apiErr); err != nil {
+			// If we can't parse the error, return a generic one
 			return nil, metadata, &APIError{
 				StatusCode: resp.StatusCode,
 				Message:    string(respBody),
@@ -179,6 +234,19 @@ func (c *httpClient) doRequestBCSWithContentType(ctx context.Context, method, pa
 	return respBody, metadata, nil
 }
 
+// requestContext bounds a request by the timeout set with WithTimeout or
+// ContextWithRequestTimeout, or else by the client's default timeout.
+func (c *httpClient) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.timeout
+	if d, ok := requestTimeoutFromContext(ctx); ok {
+		timeout = d
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // parseResponseHeaders extracts metadata from Aptos API response headers.
 func parseResponseHeaders(h http.Header) ResponseMetadata {
 	return ResponseMetadata{
@@ -190,7 +258,48 @@ func parseResponseHeaders(h http.Header) ResponseMetadata {
 		BlockHeight:         parseHeaderUint64(h.Get("X-Aptos-Block-Height")),
 		OldestBlockHeight:   parseHeaderUint64(h.Get("X-Aptos-Oldest-Block-Height")),
 		Cursor:              h.Get("X-Aptos-Cursor"),
+		RateLimit:           parseHeaderUint64(rateLimitHeader(h, "Limit")),
+		RateLimitRemaining:  parseHeaderUint64(rateLimitHeader(h, "Remaining")),
+		RateLimitReset:      parseRateLimitReset(rateLimitHeader(h, "Reset"), time.Now()),
+		RetryAfter:          parseRetryAfter(h.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(s string, now time.Time) time.Duration {
+	if s == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(s); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// rateLimitHeader returns the X-RateLimit-<name> header, falling back to the
+// unprefixed RateLimit-<name> form.
+func rateLimitHeader(h http.Header, name string) string {
+	if v := h.Get("X-RateLimit-" + name); v != "" {
+		return v
+	}
+	return h.Get("RateLimit-" + name)
+}
+
+// parseRateLimitReset parses a rate-limit reset header, which gateways send
+// either as seconds until the reset or as a Unix timestamp in seconds.
+func parseRateLimitReset(s string, now time.Time) time.Time {
+	v := parseHeaderUint64(s)
+	if v == 0 {
+		return time.Time{}
+	}
+	if v >= 1_000_000_000 {
+		return time.Unix(int64(v), 0)
 	}
+	return now.Add(time.Duration(v) * time.Second)
 }
 
 func parseHeaderUint8(s string) uint8 {
@@ -0,0 +1,2184 @@
+package aptos
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/crypto"
+)
+
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestClient starts a node that serves requests with handler and returns a
+// client for it, along with a function that stops the node.
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, func()) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		server.Close()
+		t.Fatalf("NewClient error: %v", err)
+	}
+	return client, server.Close
+}
+
+func TestCustomTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Aptos-Chain-Id", "2")
+		w.Header().Set("X-Aptos-Ledger-Version", "12345")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sequence_number":"7","authentication_key":"0x01"}`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client, err := NewClient(ClientConfig{NodeURL: server.URL, Transport: transport})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+
+	account, err := client.GetAccount(context.Background(), AccountOne)
+	if err != nil {
+		t.Fatalf("GetAccount error: %v", err)
+	}
+	if transport.requests != 1 {
+		t.Errorf("transport saw %d requests, want 1", transport.requests)
+	}
+	if account.Data.SequenceNumber != 7 {
+		t.Errorf("SequenceNumber = %s, want 7", account.Data.SequenceNumber)
+	}
+	if account.Metadata.ChainID != 2 || account.Metadata.LedgerVersion != 12345 {
+		t.Errorf("unexpected metadata: %+v", account.Metadata)
+	}
+
+	chainID, err := client.GetChainID(context.Background())
+	if err != nil {
+		t.Fatalf("GetChainID error: %v", err)
+	}
+	if chainID != 2 {
+		t.Errorf("GetChainID = %d, want 2", chainID)
+	}
+}
+
+func TestSubmitTransactionHash(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/x-bcs" {
+			t.Errorf("Accept = %q, want application/x-bcs", r.Header.Get("Accept"))
+		}
+		w.Header().Set("X-Aptos-Chain-Id", "4")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	account, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+	rawTxn, err := NewRawTransaction(account.Address, TransactionPayload{
+		Payload: &EntryFunction{
+			Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+			Function: "transfer",
+			Args:     EntryFunctionArgs(AddressArg(AccountOne), U64Arg(1)),
+		},
+	}, 4, WithSequenceNumber(0), WithGasUnitPrice(100))
+	if err != nil {
+		t.Fatalf("NewRawTransaction error: %v", err)
+	}
+	signedTxn, err := account.SignTransaction(rawTxn)
+	if err != nil {
+		t.Fatalf("SignTransaction error: %v", err)
+	}
+	txnBytes, err := signedTxn.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes error: %v", err)
+	}
+	wantHash, err := signedTxn.Hash()
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	result, err := client.SubmitTransactionHash(context.Background(), txnBytes)
+	if err != nil {
+		t.Fatalf("SubmitTransactionHash error: %v", err)
+	}
+	if result.Data != wantHash {
+		t.Errorf("hash = %s, want %s", result.Data, wantHash)
+	}
+	if result.Metadata.ChainID != 4 {
+		t.Errorf("ChainID = %d, want 4", result.Metadata.ChainID)
+	}
+}
+
+func TestGetTransactionsByHashes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hash := strings.TrimPrefix(r.URL.Path, "/transactions/by_hash/")
+		w.Header().Set("Content-Type", "application/json")
+		switch hash {
+		case "0xmissing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Transaction not found","error_code":"transaction_not_found"}`))
+		case "0xbroken":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"internal error","error_code":"internal_error"}`))
+		default:
+			w.Write([]byte(`{"type":"user_transaction","hash":"` + hash + `","version":"1"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+
+	hashes := []string{"0xa", "0xmissing", "0xb", "0xbroken", "0xc"}
+	responses, err := client.GetTransactionsByHashes(context.Background(), hashes, 2)
+	if len(responses) != len(hashes) {
+		t.Fatalf("got %d responses, want %d", len(responses), len(hashes))
+	}
+	for _, i := range []int{0, 2, 4} {
+		if responses[i].Data.Hash != hashes[i] {
+			t.Errorf("responses[%d].Hash = %s, want %s", i, responses[i].Data.Hash, hashes[i])
+		}
+	}
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+	for _, want := range []string{"get transaction 1 (0xmissing)", "get transaction 3 (0xbroken)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	if _, err := client.GetTransactionsByHashes(context.Background(), []string{"0xa", "0xb"}, 0); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name  string
+		reset string
+		want  time.Time
+	}{
+		{"missing", "", time.Time{}},
+		{"seconds until reset", "30", now.Add(30 * time.Second)},
+		{"unix timestamp", "1700000060", time.Unix(1700000060, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRateLimitReset(tt.reset, now); !got.Equal(tt.want) {
+				t.Errorf("parseRateLimitReset(%q) = %v, want %v", tt.reset, got, tt.want)
+			}
+		})
+	}
+
+	h := http.Header{}
+	h.Set("RateLimit-Limit", "100")
+	h.Set("X-RateLimit-Remaining", "0")
+	metadata := parseResponseHeaders(h)
+	if metadata.RateLimit != 100 || metadata.RateLimitRemaining != 0 || !metadata.RateLimitReset.IsZero() {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestRespectRateLimit(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "10")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"chain_id":2}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL, RespectRateLimit: true})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	info, err := client.GetLedgerInfo(context.Background())
+	if err != nil {
+		t.Fatalf("GetLedgerInfo error: %v", err)
+	}
+	if info.Metadata.RateLimit != 10 || info.Metadata.RateLimitRemaining != 0 {
+		t.Errorf("unexpected metadata: %+v", info.Metadata)
+	}
+	if until := time.Until(info.Metadata.RateLimitReset); until < 50*time.Second || until > 60*time.Second {
+		t.Errorf("RateLimitReset in %v, want about 60s", until)
+	}
+
+	// The quota is exhausted, so the next request waits for the reset and
+	// gives up when the context expires, without reaching the server.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetLedgerInfo(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if requests != 1 {
+		t.Errorf("server saw %d requests, want 1", requests)
+	}
+}
+
+func TestGetSequenceNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    uint64
+		wantErr bool
+	}{
+		{"valid", `{"sequence_number":"42","authentication_key":"0x01"}`, 42, false},
+		{"malformed", `{"sequence_number":"forty-two","authentication_key":"0x01"}`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client, err := NewClient(ClientConfig{NodeURL: server.URL})
+			if err != nil {
+				t.Fatalf("NewClient error: %v", err)
+			}
+			got, err := client.GetSequenceNumber(context.Background(), AccountOne)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSequenceNumber error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetSequenceNumber = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubmitNodeURL(t *testing.T) {
+	newServer := func(paths *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*paths = append(*paths, r.Method+" "+r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.URL.Path == "/transactions/simulate":
+				w.Write([]byte(`[]`))
+			case r.Method == http.MethodPost:
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte(`{"hash":"0x1"}`))
+			default:
+				w.Write([]byte(`{"sequence_number":"0","authentication_key":"0x01"}`))
+			}
+		}))
+	}
+	var readPaths, submitPaths []string
+	readServer := newServer(&readPaths)
+	defer readServer.Close()
+	submitServer := newServer(&submitPaths)
+	defer submitServer.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: readServer.URL, SubmitNodeURL: submitServer.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := client.GetAccount(ctx, AccountOne); err != nil {
+		t.Fatalf("GetAccount error: %v", err)
+	}
+	if _, err := client.SubmitTransaction(ctx, []byte{0x01}); err != nil {
+		t.Fatalf("SubmitTransaction error: %v", err)
+	}
+	if _, err := client.SimulateTransaction(ctx, []byte{0x01}); err != nil {
+		t.Fatalf("SimulateTransaction error: %v", err)
+	}
+
+	if len(readPaths) != 1 || !strings.HasPrefix(readPaths[0], "GET /accounts/") {
+		t.Errorf("read node saw %v, want only GetAccount", readPaths)
+	}
+	wantSubmit := []string{"POST /transactions", "POST /transactions/simulate"}
+	if strings.Join(submitPaths, ",") != strings.Join(wantSubmit, ",") {
+		t.Errorf("submit node saw %v, want %v", submitPaths, wantSubmit)
+	}
+}
+
+func TestWaitForEvent(t *testing.T) {
+	// The handle does not exist on the first poll; afterwards each poll
+	// reveals one more deposit event.
+	var polls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		polls++
+		if polls == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Resource not found","error_code":"resource_not_found"}`))
+			return
+		}
+		start, _ := strconv.ParseUint(r.URL.Query().Get("start"), 10, 64)
+		var events []string
+		for seq := start; seq < uint64(polls-1); seq++ {
+			events = append(events, fmt.Sprintf(`{"sequence_number":"%d","type":"0x1::coin::DepositEvent","data":{"amount":"%d"}}`, seq, (seq+1)*100))
+		}
+		w.Write([]byte("[" + strings.Join(events, ",") + "]"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	bigDeposit := func(e Event) bool {
+		var data struct {
+			Amount JSONUint64 `json:"amount"`
+		}
+		return e.DecodeData(&data) == nil && data.Amount >= 300
+	}
+	event, err := client.WaitForEvent(ctx, AccountOne, "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>", "deposit_events", bigDeposit, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForEvent error: %v", err)
+	}
+	if got := event.Data.SequenceNumberUint64(); got != 2 {
+		t.Errorf("sequence number = %d, want 2", got)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	never := func(Event) bool { return false }
+	if _, err := client.WaitForEvent(ctx, AccountOne, "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>", "deposit_events", never, time.Millisecond, WithStart(1000)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForEvent error = %v, want context.DeadlineExceeded", err)
+	}
+	if _, err := client.WaitForEvent(context.Background(), AccountOne, "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>", "deposit_events", nil, 0); err == nil {
+		t.Error("WaitForEvent accepted a zero poll interval")
+	}
+
+	// Other errors: a 503 for AccountThree is retried, a 400 for AccountOne
+	// is returned at once
+	var unavailablePolls, badPolls atomic.Int32
+	errServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, AccountThree.String()) {
+			unavailablePolls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			badPolls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write([]byte(`{"message":"failed","error_code":"internal_error"}`))
+	}))
+	defer errServer.Close()
+	errClient, err := NewClient(ClientConfig{NodeURL: errServer.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = errClient.WaitForEvent(ctx, AccountThree, "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>", "deposit_events", nil, time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "failed") {
+		t.Errorf("WaitForEvent error = %v, want context.DeadlineExceeded with the last error", err)
+	}
+	if unavailablePolls.Load() < 2 {
+		t.Errorf("WaitForEvent polled %d times on a 503, want retries", unavailablePolls.Load())
+	}
+
+	_, err = errClient.WaitForEvent(context.Background(), AccountOne, "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>", "deposit_events", nil, time.Millisecond)
+	if ErrorKind(err) != ErrKindInvalidInput || badPolls.Load() != 1 {
+		t.Errorf("WaitForEvent error = %v after %d polls, want the 400 after 1", err, badPolls.Load())
+	}
+}
+
+func TestContextWithRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"chain_id":1}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		defaultTimeout time.Duration
+		callTimeout    time.Duration // zero leaves the default in place
+		wantErr        bool
+	}{
+		{"default too short", 10 * time.Millisecond, 0, true},
+		{"call extends default", 10 * time.Millisecond, time.Second, false},
+		{"call shortens default", time.Second, 10 * time.Millisecond, true},
+		{"call disables default", 10 * time.Millisecond, -1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(ClientConfig{NodeURL: server.URL, Timeout: tt.defaultTimeout})
+			if err != nil {
+				t.Fatalf("NewClient error: %v", err)
+			}
+			ctx := context.Background()
+			if tt.callTimeout != 0 {
+				ctx = ContextWithRequestTimeout(ctx, tt.callTimeout)
+			}
+			_, err = client.GetLedgerInfo(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetLedgerInfo error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && ErrorKind(err) != ErrKindTimeout {
+				t.Errorf("ErrorKind = %v, want %v", ErrorKind(err), ErrKindTimeout)
+			}
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sequence_number":"1","authentication_key":"0x01"}`))
+	}))
+	defer server.Close()
+
+	short, err := NewClient(ClientConfig{NodeURL: server.URL, Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	long, err := NewClient(ClientConfig{NodeURL: server.URL, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := short.GetAccount(ctx, AccountOne); ErrorKind(err) != ErrKindTimeout {
+		t.Errorf("GetAccount error = %v, want a timeout", err)
+	}
+	if _, err := short.GetAccount(ctx, AccountOne, WithTimeout(time.Second)); err != nil {
+		t.Errorf("GetAccount with a longer timeout error: %v", err)
+	}
+	if _, err := long.GetAccount(ctx, AccountOne, WithTimeout(10*time.Millisecond)); ErrorKind(err) != ErrKindTimeout {
+		t.Errorf("GetAccount with a shorter timeout error = %v, want a timeout", err)
+	}
+	// The option takes precedence over the context
+	shortCtx := ContextWithRequestTimeout(ctx, 10*time.Millisecond)
+	if _, err := long.GetAccount(shortCtx, AccountOne, WithTimeout(time.Second)); err != nil {
+		t.Errorf("GetAccount with WithTimeout over ContextWithRequestTimeout error: %v", err)
+	}
+}
+
+func TestWithTreatNotFoundAsEmpty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Aptos-Ledger-Version", "7")
+		if strings.HasPrefix(r.URL.Path, "/accounts/0x0000000000000000000000000000000000000000000000000000000000000002/") {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"internal error","error_code":"internal_error"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Account not found","error_code":"account_not_found"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := client.GetAccountResources(ctx, AccountOne); !IsAccountNotFound(err) {
+		t.Errorf("GetAccountResources error = %v, want account not found", err)
+	}
+
+	resources, err := client.GetAccountResources(ctx, AccountOne, WithTreatNotFoundAsEmpty())
+	if err != nil {
+		t.Fatalf("GetAccountResources error: %v", err)
+	}
+	if resources.Data != nil {
+		t.Errorf("resources = %v, want nil", resources.Data)
+	}
+	if resources.Metadata.LedgerVersion != 7 {
+		t.Errorf("ledger version = %d, want 7", resources.Metadata.LedgerVersion)
+	}
+
+	modules, err := client.GetAccountModules(ctx, AccountOne, WithTreatNotFoundAsEmpty())
+	if err != nil || modules.Data != nil {
+		t.Errorf("GetAccountModules = %v, %v, want nil, nil", modules.Data, err)
+	}
+
+	if _, err := client.GetAccountResources(ctx, MustParseAccountAddress("0x2"), WithTreatNotFoundAsEmpty()); err == nil {
+		t.Error("GetAccountResources should still fail on other errors")
+	}
+}
+
+func TestGasPriority(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Aptos-Chain-Id", "4")
+		switch {
+		case r.URL.Path == "/estimate_gas_price":
+			w.Write([]byte(`{"deprioritized_gas_estimate":100,"gas_estimate":150,"prioritized_gas_estimate":1000}`))
+		case strings.HasPrefix(r.URL.Path, "/accounts/"):
+			w.Write([]byte(`{"sequence_number":"3","authentication_key":"0x01"}`))
+		default:
+			w.Write([]byte(`{"message":"success"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		priority GasPriority
+		want     uint64
+	}{
+		{GasPriorityLow, 100},
+		{GasPriorityNormal, 150},
+		{GasPriorityHigh, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.priority.String(), func(t *testing.T) {
+			got, err := client.RecommendGasUnitPrice(ctx, tt.priority)
+			if err != nil {
+				t.Fatalf("RecommendGasUnitPrice error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RecommendGasUnitPrice = %d, want %d", got, tt.want)
+			}
+
+			rawTxn, err := client.BuildTransaction(ctx, AccountOne, TransactionPayload{}, WithGasPriority(tt.priority))
+			if err != nil {
+				t.Fatalf("BuildTransaction error: %v", err)
+			}
+			if rawTxn.GasUnitPrice != tt.want {
+				t.Errorf("GasUnitPrice = %d, want %d", rawTxn.GasUnitPrice, tt.want)
+			}
+		})
+	}
+
+	if got := (GasEstimation{GasEstimate: 150}).UnitPrice(GasPriorityHigh); got != 150 {
+		t.Errorf("UnitPrice without prioritized estimate = %d, want 150", got)
+	}
+}
+
+func TestHealthCheckWithin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("duration_secs") {
+		case "60":
+			w.Write([]byte(`{"message":"aptos-node:ok"}`))
+		case "1":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"message":"The latest ledger info timestamp is 1700000000000000, which is beyond the allowed latency 1s","error_code":"health_check_failed"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"internal error","error_code":"internal_error"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := client.HealthCheckWithin(ctx, 60); err != nil {
+		t.Errorf("HealthCheckWithin(60) error: %v", err)
+	}
+
+	err = client.HealthCheckWithin(ctx, 1)
+	if !IsNodeLagging(err) {
+		t.Errorf("HealthCheckWithin(1) error = %v, want ErrNodeLagging", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("HealthCheckWithin(1) error = %v, want wrapped *APIError with status 503", err)
+	}
+
+	if err := client.HealthCheckWithin(ctx, 5); err == nil || IsNodeLagging(err) {
+		t.Errorf("HealthCheckWithin(5) error = %v, want non-lagging error", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	account, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+
+	var submitted bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Aptos-Chain-Id", "4")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/transactions":
+			submitted = true
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"hash":"0xabc"}`))
+		case strings.HasPrefix(r.URL.Path, "/transactions/wait_by_hash/"):
+			w.Write([]byte(`{"type":"user_transaction","hash":"0xabc","success":true,"vm_status":"Executed successfully"}`))
+		case r.URL.Path == "/estimate_gas_price":
+			w.Write([]byte(`{"gas_estimate":100}`))
+		case strings.HasPrefix(r.URL.Path, "/accounts/"):
+			w.Write([]byte(`{"sequence_number":"0","authentication_key":"0x01"}`))
+		default:
+			w.Write([]byte(`{"message":"success"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	result, err := client.Ping(context.Background(), account)
+	if err != nil {
+		t.Fatalf("Ping error: %v", err)
+	}
+	if !submitted {
+		t.Error("Ping did not submit a transaction")
+	}
+	if !result.Success || result.Hash != "0xabc" || result.Latency <= 0 {
+		t.Errorf("Ping = %+v, want successful result for 0xabc with latency", result)
+	}
+}
+
+func TestBuildTransactionWithoutNetworkCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client, err := NewClient(ClientConfig{NodeURL: server.URL, Transport: transport})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+
+	rawTxn, err := client.BuildTransaction(context.Background(), AccountOne, TransactionPayload{},
+		WithAccountData(AccountData{SequenceNumber: 9}),
+		WithChainID(4),
+		WithGasUnitPrice(120),
+	)
+	if err != nil {
+		t.Fatalf("BuildTransaction error: %v", err)
+	}
+	if transport.requests != 0 {
+		t.Errorf("transport saw %d requests, want 0", transport.requests)
+	}
+	if rawTxn.SequenceNumber != 9 || rawTxn.ChainID != 4 || rawTxn.GasUnitPrice != 120 {
+		t.Errorf("unexpected transaction: seq=%d chain=%d gas=%d", rawTxn.SequenceNumber, rawTxn.ChainID, rawTxn.GasUnitPrice)
+	}
+}
+
+func TestGetValidatorSet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/accounts/"+AccountOne.String()+"/resource/0x1::stake::ValidatorSet" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Aptos-Epoch", "42")
+		w.Write([]byte(`{"type":"0x1::stake::ValidatorSet","data":{
+			"active_validators":[
+				{"addr":"0xa1","config":{"consensus_pubkey":"0x8f","fullnode_addresses":"0x01","network_addresses":"0x02","validator_index":"0"},"voting_power":"300"},
+				{"addr":"0xa2","config":{"consensus_pubkey":"0x9e","fullnode_addresses":"0x","network_addresses":"0x","validator_index":"1"},"voting_power":"200"}
+			],
+			"consensus_scheme":0,
+			"pending_active":[{"addr":"0xb1","config":{"consensus_pubkey":"0x7d","fullnode_addresses":"0x","network_addresses":"0x","validator_index":"0"},"voting_power":"100"}],
+			"pending_inactive":[],
+			"total_joining_power":"100",
+			"total_voting_power":"500"
+		}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	set, err := client.GetValidatorSet(context.Background())
+	if err != nil {
+		t.Fatalf("GetValidatorSet error: %v", err)
+	}
+
+	if len(set.Data.ActiveValidators) != 2 || len(set.Data.PendingActive) != 1 || len(set.Data.PendingInactive) != 0 {
+		t.Fatalf("unexpected validator counts: %+v", set.Data)
+	}
+	active := set.Data.ActiveValidators[1]
+	if active.Address != MustParseAccountAddress("0xa2") || active.VotingPower != 200 || active.Config.ValidatorIndex != 1 {
+		t.Errorf("unexpected validator: %+v", active)
+	}
+	if set.Data.ActiveValidators[0].Config.ConsensusPubkey != "0x8f" {
+		t.Errorf("ConsensusPubkey = %s, want 0x8f", set.Data.ActiveValidators[0].Config.ConsensusPubkey)
+	}
+	if set.Data.TotalVotingPower.String() != "500" || set.Data.TotalJoiningPower.String() != "100" {
+		t.Errorf("total power = %s, joining = %s", set.Data.TotalVotingPower.String(), set.Data.TotalJoiningPower.String())
+	}
+	if set.Metadata.Epoch != 42 {
+		t.Errorf("Epoch = %d, want 42", set.Metadata.Epoch)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	account, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+	owner := MustParseAccountAddress("0xcafe")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Aptos-Chain-Id", "4")
+		switch {
+		case r.URL.Path == "/transactions/simulate":
+			w.Write([]byte(`[{"success":true,"vm_status":"Executed successfully","gas_used":"12",
+				"events":[
+					{"type":"0xcafe::counter::Incremented","guid":{"creation_number":"0","account_address":"0x0"},"sequence_number":"0","data":{"value":"2"}},
+					{"type":"0x1::transaction_fee::FeeStatement","guid":{"creation_number":"0","account_address":"0x0"},"sequence_number":"0","data":{}}
+				],
+				"changes":[
+					{"type":"write_resource","address":"0xcafe","state_key_hash":"0x01","data":{"type":"0xcafe::counter::Counter","data":{"value":"2"}}},
+					{"type":"delete_resource","address":"0xcafe","state_key_hash":"0x02","resource":"0xcafe::counter::Pending"},
+					{"type":"write_table_item","state_key_hash":"0x03","handle":"0x04","key":"0x05","value":"0x06","data":null}
+				]}]`))
+		case r.URL.Path == "/estimate_gas_price":
+			w.Write([]byte(`{"gas_estimate":100}`))
+		case strings.HasPrefix(r.URL.Path, "/accounts/"):
+			w.Write([]byte(`{"sequence_number":"0","authentication_key":"0x01"}`))
+		default:
+			w.Write([]byte(`{"chain_id":4,"epoch":"1","ledger_version":"1","oldest_ledger_version":"0","ledger_timestamp":"1","node_role":"full_node","oldest_block_height":"0","block_height":"1"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	result, err := client.DryRun(context.Background(), account, TransactionPayload{Payload: &EntryFunction{
+		Module:   ModuleId{Address: owner, Name: "counter"},
+		Function: "increment",
+	}})
+	if err != nil {
+		t.Fatalf("DryRun error: %v", err)
+	}
+	dryRun := result.Data
+	if !dryRun.Success || dryRun.GasUsed != 12 || len(dryRun.Events) != 2 || len(dryRun.Changes) != 3 {
+		t.Fatalf("unexpected result: %+v", dryRun)
+	}
+	if events := dryRun.EventsOfType("0xcafe::counter::Incremented"); len(events) != 1 {
+		t.Errorf("EventsOfType returned %d events, want 1", len(events))
+	}
+
+	change, ok := dryRun.ResourceChange(owner, "0xcafe::counter::Counter")
+	if !ok {
+		t.Fatal("ResourceChange did not find the counter")
+	}
+	var counter struct {
+		Value JSONUint64 `json:"value"`
+	}
+	if err := change.DecodeResource(&counter); err != nil {
+		t.Fatalf("DecodeResource error: %v", err)
+	}
+	if counter.Value != 2 {
+		t.Errorf("counter = %d, want 2", counter.Value)
+	}
+
+	deleted, ok := dryRun.ResourceChange(owner, "0xcafe::counter::Pending")
+	if !ok || deleted.Type != WriteSetChangeDeleteResource {
+		t.Errorf("ResourceChange for deleted resource = %+v, %v", deleted, ok)
+	}
+	if err := deleted.DecodeResource(&counter); err == nil {
+		t.Error("DecodeResource of a deleted resource should fail")
+	}
+	if table := dryRun.Changes[2]; table.Handle != "0x04" || table.ResourceType() != "" {
+		t.Errorf("unexpected table item change: %+v", table)
+	}
+}
+
+func TestDeduplicateReads(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Aptos-Ledger-Version", "77")
+		w.Write([]byte(`{"chain_id":4,"ledger_version":"77"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL, DeduplicateReads: true})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+
+	// A caller that gives up must not fail the shared request for the others.
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go func() {
+		_, err := client.GetLedgerInfo(canceledCtx)
+		canceled <- err
+	}()
+
+	const callers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info, err := client.GetLedgerInfo(context.Background())
+			if err == nil && info.Data.LedgerVersion != 77 {
+				err = fmt.Errorf("LedgerVersion = %d, want 77", info.Data.LedgerVersion)
+			}
+			errs <- err
+		}()
+	}
+
+	// Give every caller time to join the in-flight request.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-canceled; !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled caller error = %v, want context.Canceled", err)
+	}
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetLedgerInfo error: %v", err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+}
+
+func TestGetAccountResourceTypes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Aptos-Ledger-Version", "9")
+		if !strings.HasSuffix(r.URL.Path, "/resources") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`[
+			{"type":"0x1::account::Account","data":{"sequence_number":"3","guid_creation_num":"4"}},
+			{"type":"0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>","data":{"coin":{"value":"100"},"frozen":false}}
+		]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+
+	types, err := client.GetAccountResourceTypes(ctx, AccountOne)
+	if err != nil {
+		t.Fatalf("GetAccountResourceTypes error: %v", err)
+	}
+	want := []string{"0x1::account::Account", "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>"}
+	if len(types.Data) != len(want) || types.Data[0] != want[0] || types.Data[1] != want[1] {
+		t.Errorf("GetAccountResourceTypes = %v, want %v", types.Data, want)
+	}
+	if types.Metadata.LedgerVersion != 9 {
+		t.Errorf("LedgerVersion = %d, want 9", types.Metadata.LedgerVersion)
+	}
+
+	tags, err := client.GetAccountResourceStructTags(ctx, AccountOne)
+	if err != nil {
+		t.Fatalf("GetAccountResourceStructTags error: %v", err)
+	}
+	if len(tags.Data) != 2 || tags.Data[1].Module != "coin" || tags.Data[1].Name != "CoinStore" || len(tags.Data[1].TypeParams) != 1 {
+		t.Errorf("unexpected struct tags: %+v", tags.Data)
+	}
+}
+
+func TestGetTransactionsInBlock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/blocks/by_height/5":
+			if r.URL.Query().Get("with_transactions") != "true" {
+				t.Errorf("block requested without transactions")
+			}
+			// The node truncated the block's transactions to one page.
+			w.Write([]byte(`{"block_height":"5","block_hash":"0xb","block_timestamp":"1","first_version":"10","last_version":"12",
+				"transactions":[
+					{"type":"block_metadata_transaction","version":"10","hash":"0x10"},
+					{"type":"user_transaction","version":"11","hash":"0x11","success":true}
+				]}`))
+		case "/transactions":
+			if got := r.URL.Query().Get("start"); got != "12" {
+				t.Errorf("start = %s, want 12", got)
+			}
+			if got := r.URL.Query().Get("limit"); got != "1" {
+				t.Errorf("limit = %s, want 1", got)
+			}
+			w.Write([]byte(`[{"type":"state_checkpoint_transaction","version":"12","hash":"0x12"}]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+
+	block, err := client.GetBlockByHeight(ctx, 5, true)
+	if err != nil {
+		t.Fatalf("GetBlockByHeight error: %v", err)
+	}
+	if got := block.Data.TransactionCount(); got != 3 {
+		t.Errorf("TransactionCount = %d, want 3", got)
+	}
+	if !block.Data.ContainsVersion(12) || block.Data.ContainsVersion(13) || block.Data.ContainsVersion(9) {
+		t.Error("ContainsVersion does not match the block's version range")
+	}
+	var versions []uint64
+	err = block.Data.ForEachTransaction(func(version uint64, txn *Transaction) error {
+		if txn.Version.Uint64() != version {
+			t.Errorf("transaction version %d at position for %d", txn.Version, version)
+		}
+		versions = append(versions, version)
+		return nil
+	})
+	if err != nil || len(versions) != 2 {
+		t.Errorf("ForEachTransaction visited %v, err %v", versions, err)
+	}
+
+	txns, err := client.GetTransactionsInBlock(ctx, 5)
+	if err != nil {
+		t.Fatalf("GetTransactionsInBlock error: %v", err)
+	}
+	if len(txns.Data) != 3 {
+		t.Fatalf("got %d transactions, want 3", len(txns.Data))
+	}
+	for i, txn := range txns.Data {
+		if txn.Version.Uint64() != 10+uint64(i) {
+			t.Errorf("transaction %d has version %d", i, txn.Version)
+		}
+	}
+	if txns.Data[1].Type != TransactionTypeUser || !txns.Data[1].Success {
+		t.Errorf("unexpected user transaction: %+v", txns.Data[1])
+	}
+}
+
+func TestPollersWithCanceledContext(t *testing.T) {
+	transport := &countingTransport{}
+	client, err := NewClient(ClientConfig{NodeURL: "http://127.0.0.1:1", Transport: transport})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.PollForTransaction(ctx, "0xabc", time.Millisecond); !errors.Is(err, context.Canceled) {
+		t.Errorf("PollForTransaction error = %v, want context.Canceled", err)
+	}
+	if _, err := client.WaitForLedgerVersion(ctx, 1, time.Millisecond); !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitForLedgerVersion error = %v, want context.Canceled", err)
+	}
+	if _, err := client.WaitForEvent(ctx, AccountOne, "0x1::coin::CoinStore", "deposit_events", nil, time.Millisecond); !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitForEvent error = %v, want context.Canceled", err)
+	}
+	if transport.requests != 0 {
+		t.Errorf("transport saw %d requests, want 0", transport.requests)
+	}
+}
+
+func TestWaitForLedgerVersion(t *testing.T) {
+	// The node answers 5, then a 503, then 8, then 10, ...
+	versions := []string{"5", "", "8", "10", "11"}
+	var polls atomic.Int32
+	var forbidden, unavailable atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if forbidden.Load() {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"API key rejected","error_code":"web_framework_error"}`))
+			return
+		}
+		version := versions[min(int(polls.Add(1))-1, len(versions)-1)]
+		if version == "" || unavailable.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"message":"Service unavailable","error_code":"internal_error"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"chain_id":4,"ledger_version":%q}`, version)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := client.WaitForLedgerVersion(ctx, 10, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForLedgerVersion error: %v", err)
+	}
+	if info.Data.LedgerVersion != 10 || polls.Load() != 4 {
+		t.Errorf("WaitForLedgerVersion = version %d after %d polls, want 10 after 4", info.Data.LedgerVersion, polls.Load())
+	}
+
+	if _, err := client.WaitForLedgerVersion(ctx, 10, 0); err == nil {
+		t.Error("WaitForLedgerVersion accepted a zero poll interval")
+	}
+
+	// A permanent error is returned without polling again
+	forbidden.Store(true)
+	polls.Store(0)
+	_, err = client.WaitForLedgerVersion(ctx, 100, time.Millisecond)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("WaitForLedgerVersion error = %v, want the 403 response", err)
+	}
+	if ctx.Err() != nil {
+		t.Error("WaitForLedgerVersion polled until the deadline on a permanent error")
+	}
+
+	// A transient error is retried until the deadline and then reported
+	forbidden.Store(false)
+	unavailable.Store(true)
+	polls.Store(0)
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer shortCancel()
+	_, err = client.WaitForLedgerVersion(shortCtx, 100, time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || ErrorKind(err) != ErrKindTimeout {
+		t.Errorf("WaitForLedgerVersion error = %v, want context.DeadlineExceeded", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "Service unavailable") {
+		t.Errorf("WaitForLedgerVersion error = %v, want it to include the last error", err)
+	}
+	if polls.Load() < 2 {
+		t.Errorf("WaitForLedgerVersion polled %d times, want retries", polls.Load())
+	}
+}
+
+func TestTransactionNotFound(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		switch r.URL.Path {
+		case "/transactions/by_hash/0xbare":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("not found"))
+		case "/transactions/by_hash/0xinvalid":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"invalid hash","error_code":"invalid_input"}`))
+		case "/transactions/by_hash/0xlater":
+			if n < 3 {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message":"not found","error_code":"transaction_not_found"}`))
+				return
+			}
+			w.Write([]byte(`{"type":"user_transaction","hash":"0xlater","success":true}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+
+	_, err = client.GetTransactionByHash(ctx, "0xlater")
+	if !IsTransactionNotFound(err) || !errors.Is(err, ErrTransactionNotFound) || !IsNotFound(err) {
+		t.Errorf("transaction_not_found error = %v, want ErrTransactionNotFound", err)
+	}
+
+	// A 404 without the node's error code, e.g. from a proxy in front of a
+	// wrong URL, is not a missing transaction, and polling gives up on it
+	_, err = client.GetTransactionByHash(ctx, "0xbare")
+	if IsTransactionNotFound(err) || IsNotFound(err) || ErrorKind(err) != ErrKindNotFound {
+		t.Errorf("bare 404 error = %v, want a plain 404", err)
+	}
+	requests.Store(0)
+	if _, err := client.PollForTransaction(ctx, "0xbare", time.Millisecond); ErrorKind(err) != ErrKindNotFound || requests.Load() != 1 {
+		t.Errorf("PollForTransaction error = %v after %d requests, want the bare 404 after 1", err, requests.Load())
+	}
+
+	requests.Store(0)
+	txn, err := client.PollForTransaction(ctx, "0xlater", time.Millisecond)
+	if err != nil {
+		t.Fatalf("PollForTransaction error: %v", err)
+	}
+	if txn.Data.Hash != "0xlater" || requests.Load() != 3 {
+		t.Errorf("PollForTransaction = %s after %d requests, want 0xlater after 3", txn.Data.Hash, requests.Load())
+	}
+
+	_, err = client.PollForTransaction(ctx, "0xinvalid", time.Millisecond)
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("PollForTransaction error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestWaitForTransactionByHash(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		switch r.URL.Path {
+		case "/transactions/wait_by_hash/0xlater":
+			switch n {
+			case 1:
+				// Outlive the request timeout
+				time.Sleep(200 * time.Millisecond)
+				return
+			case 2:
+				w.Write([]byte(`{"type":"pending_transaction","hash":"0xlater"}`))
+				return
+			}
+			w.Write([]byte(`{"type":"user_transaction","hash":"0xlater","success":true}`))
+		case "/transactions/wait_by_hash/0xpending":
+			w.Write([]byte(`{"type":"pending_transaction","hash":"0xpending"}`))
+		case "/transactions/wait_by_hash/0xfailing":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"internal error","error_code":"internal_error"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL, Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	txn, err := client.WaitForTransactionByHash(ctx, "0xlater")
+	if err != nil {
+		t.Fatalf("WaitForTransactionByHash error: %v", err)
+	}
+	if txn.Data.Hash != "0xlater" || txn.Data.IsPending() || requests.Load() != 3 {
+		t.Errorf("WaitForTransactionByHash = %s (%s) after %d requests, want committed 0xlater after 3",
+			txn.Data.Hash, txn.Data.Type, requests.Load())
+	}
+
+	shortCtx, cancelShort := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancelShort()
+	_, err = client.WaitForTransactionByHash(shortCtx, "0xpending")
+	if !errors.Is(err, ErrTransactionTimeout) || ErrorKind(err) != ErrKindTimeout {
+		t.Errorf("pending error = %v, want ErrTransactionTimeout", err)
+	}
+
+	requests.Store(0)
+	_, err = client.WaitForTransactionByHash(ctx, "0xfailing")
+	if errors.Is(err, ErrTransactionTimeout) || ErrorKind(err) != ErrKindServerError || requests.Load() != 1 {
+		t.Errorf("failing error = %v after %d requests, want server error after 1", err, requests.Load())
+	}
+}
+
+func TestAPTMetadataAddress(t *testing.T) {
+	type reply struct {
+		status int
+		body   string
+	}
+	paired := reply{http.StatusOK, `[{"vec":[{"inner":"0xa"}]}]`}
+	tests := []struct {
+		name    string
+		replies []reply // one per call; the last is repeated
+		want    []string
+	}{
+		{"paired metadata is cached", []reply{paired}, []string{"0xa", "0xa"}},
+		{
+			name:    "missing function falls back",
+			replies: []reply{{http.StatusBadRequest, `{"message":"function not found","error_code":"invalid_input"}`}},
+			want:    []string{"0xa", "0xa"},
+		},
+		{
+			name:    "missing module falls back",
+			replies: []reply{{http.StatusNotFound, `{"message":"Module not found","error_code":"module_not_found"}`}},
+			want:    []string{"0xa", "0xa"},
+		},
+		{
+			name:    "transient error is not cached",
+			replies: []reply{{http.StatusServiceUnavailable, `{"message":"unavailable","error_code":"internal_error"}`}, paired},
+			want:    []string{"error", "0xa", "0xa"},
+		},
+		{
+			name:    "other 4xx is returned",
+			replies: []reply{{http.StatusBadRequest, `{"message":"invalid type argument","error_code":"invalid_input"}`}, paired},
+			want:    []string{"error", "0xa"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/view" {
+					t.Errorf("path = %s, want /view", r.URL.Path)
+				}
+				reply := tt.replies[min(int(requests.Add(1)), len(tt.replies))-1]
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(reply.status)
+				w.Write([]byte(reply.body))
+			}))
+			defer server.Close()
+
+			client, err := NewClient(ClientConfig{NodeURL: server.URL})
+			if err != nil {
+				t.Fatalf("NewClient error: %v", err)
+			}
+			wantRequests := 0
+			for i, want := range tt.want {
+				address, err := client.APTMetadataAddress(context.Background())
+				switch {
+				case want == "error":
+					if err == nil {
+						t.Errorf("call %d: APTMetadataAddress = %s, want an error", i, address)
+					}
+					wantRequests++
+				case err != nil:
+					t.Errorf("call %d: APTMetadataAddress error: %v", i, err)
+				case address.ShortString() != want:
+					t.Errorf("call %d: APTMetadataAddress = %s, want %s", i, address.ShortString(), want)
+				}
+			}
+			// Each error is followed by one request that succeeds and is cached
+			if wantRequests++; int(requests.Load()) != wantRequests {
+				t.Errorf("made %d requests, want %d", requests.Load(), wantRequests)
+			}
+		})
+	}
+}
+
+func TestBuildTransactionWithParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client, err := NewClient(ClientConfig{NodeURL: server.URL, Transport: transport})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+
+	seqNum := uint64(0)
+	rawTxn, err := client.BuildTransactionWithParams(ctx, AccountOne, TransactionPayload{}, BuildParams{
+		SequenceNumber:          &seqNum,
+		GasUnitPrice:            150,
+		MaxGasAmount:            5000,
+		ExpirationTimestampSecs: 1700000000,
+		ChainID:                 2,
+	})
+	if err != nil {
+		t.Fatalf("BuildTransactionWithParams error: %v", err)
+	}
+	if transport.requests != 0 {
+		t.Errorf("transport saw %d requests, want 0", transport.requests)
+	}
+	if rawTxn.SequenceNumber != 0 || rawTxn.GasUnitPrice != 150 || rawTxn.MaxGasAmount != 5000 ||
+		rawTxn.ExpirationTimestampSecs != 1700000000 || rawTxn.ChainID != 2 {
+		t.Errorf("unexpected transaction: %+v", rawTxn)
+	}
+
+	nonce := uint64(7)
+	_, err = client.BuildTransactionWithParams(ctx, AccountOne, TransactionPayload{}, BuildParams{
+		SequenceNumber:        &seqNum,
+		ReplayProtectionNonce: &nonce,
+		ChainID:               2,
+	})
+	if err == nil {
+		t.Error("expected an error for both SequenceNumber and ReplayProtectionNonce")
+	}
+}
+
+func TestTrackSubmissions(t *testing.T) {
+	var submissions atomic.Int32
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		submissions.Add(1)
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"hash":"0x1"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL, TrackSubmissions: true})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	account, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+	ctx := context.Background()
+	sign := func(seqNum, amount, gasUnitPrice uint64) []byte {
+		t.Helper()
+		payload, err := TransferCoinPayload(AptosCoinType, AccountOne, amount)
+		if err != nil {
+			t.Fatalf("TransferCoinPayload error: %v", err)
+		}
+		rawTxn, err := NewRawTransaction(account.Address, payload, 4,
+			WithSequenceNumber(seqNum), WithGasUnitPrice(gasUnitPrice), WithExpirationDuration(time.Minute))
+		if err != nil {
+			t.Fatalf("NewRawTransaction error: %v", err)
+		}
+		signedTxn, err := account.SignTransaction(rawTxn)
+		if err != nil {
+			t.Fatalf("SignTransaction error: %v", err)
+		}
+		txnBytes, err := signedTxn.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes error: %v", err)
+		}
+		return txnBytes
+	}
+
+	first := sign(0, 1, 100)
+	if _, err := client.SubmitTransaction(ctx, first); err != nil {
+		t.Fatalf("SubmitTransaction error: %v", err)
+	}
+	tests := []struct {
+		name      string
+		txn       []byte
+		duplicate bool
+	}{
+		{"same transaction", first, true},
+		{"same sequence number", sign(0, 2, 100), true},
+		{"replacement with higher gas", sign(0, 2, 150), false},
+		{"next sequence number", sign(1, 1, 100), false},
+	}
+	for _, tt := range tests {
+		before := submissions.Load()
+		_, err := client.SubmitTransactionHash(ctx, tt.txn)
+		if got := errors.Is(err, ErrDuplicateSubmission); got != tt.duplicate {
+			t.Errorf("%s: error = %v, want duplicate %v", tt.name, err, tt.duplicate)
+		}
+		if sent := submissions.Load() != before; sent == tt.duplicate {
+			t.Errorf("%s: sent to node = %v, want %v", tt.name, sent, !tt.duplicate)
+		}
+	}
+
+	// A submission the node rejected can be retried
+	fail.Store(true)
+	rejected := sign(2, 1, 100)
+	if _, err := client.SubmitTransaction(ctx, rejected); err == nil || errors.Is(err, ErrDuplicateSubmission) {
+		t.Fatalf("SubmitTransaction error = %v, want the node's error", err)
+	}
+	fail.Store(false)
+	if _, err := client.SubmitTransaction(ctx, rejected); err != nil {
+		t.Errorf("retry after rejection: %v", err)
+	}
+
+	// Expired transactions are forgotten
+	tracker := newSubmissionTracker()
+	sub, err := tracker.begin(first)
+	if err != nil {
+		t.Fatalf("begin error: %v", err)
+	}
+	sub.expiresAt = time.Now().Add(-time.Second)
+	if _, err := tracker.begin(first); err != nil {
+		t.Errorf("begin after expiry: %v", err)
+	}
+}
+
+func TestTransactionIterators(t *testing.T) {
+	const total = 5
+	var requests atomic.Int32
+	var queries []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Aptos-Ledger-Version", strconv.Itoa(total-1))
+
+		var page []string
+		for i := start; i < start+limit && i < total; i++ {
+			switch r.URL.Path {
+			case "/transactions":
+				page = append(page, fmt.Sprintf(`{"type":"user_transaction","hash":"0x%d","version":"%d"}`, i, i))
+			default:
+				page = append(page, fmt.Sprintf(`{"type":"user_transaction","hash":"0x%d","sequence_number":"%d"}`, i, i))
+			}
+		}
+		w.Write([]byte("[" + strings.Join(page, ",") + "]"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		txns         iter.Seq2[Transaction, error]
+		wantRequests int32
+	}{
+		// The ledger version shows the third page is the last
+		{"transactions", client.Transactions(ctx, WithLimit(2)), 3},
+		// Account transactions end with an empty page
+		{"account transactions", client.AccountTransactions(ctx, AccountOne, WithLimit(2)), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			queries = nil
+			seen := make(map[string]int)
+			var order []string
+			for txn, err := range tt.txns {
+				if err != nil {
+					t.Fatalf("iteration error: %v", err)
+				}
+				seen[txn.Hash]++
+				order = append(order, txn.Hash)
+			}
+			if len(order) != total || len(seen) != total {
+				t.Errorf("yielded %v, want %d distinct transactions once each", order, total)
+			}
+			for i, hash := range order {
+				if want := fmt.Sprintf("0x%d", i); hash != want {
+					t.Errorf("transaction %d = %s, want %s", i, hash, want)
+				}
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("made %d requests, want %d: %v", got, tt.wantRequests, queries)
+			}
+			if queries[0] != "start=0&limit=2" || queries[1] != "start=2&limit=2" {
+				t.Errorf("queries = %v, want start=0 then start=2 with limit=2", queries)
+			}
+		})
+	}
+
+	// Breaking out of the loop stops fetching
+	requests.Store(0)
+	for range client.AccountTransactions(ctx, AccountOne, WithStart(1), WithLimit(2)) {
+		break
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("made %d requests after break, want 1", got)
+	}
+}
+
+func TestBCSResponses(t *testing.T) {
+	objectGroup := StructTag{Address: AccountOne, Module: "object", Name: "ObjectGroup"}
+	objectCore := StructTag{Address: AccountOne, Module: "object", Name: "ObjectCore"}
+	account := StructTag{Address: AccountOne, Module: "account", Name: "Account"}
+
+	ser := bcs.NewSerializer()
+	ser.Uleb128(1)
+	objectCore.MarshalBCS(ser)
+	ser.Bytes([]byte{1, 2})
+	groupBytes := ser.ToBytes()
+
+	ser = bcs.NewSerializer()
+	ser.Uleb128(2)
+	account.MarshalBCS(ser)
+	ser.Bytes([]byte{3})
+	objectGroup.MarshalBCS(ser)
+	ser.Bytes(groupBytes)
+	resourcesBytes := ser.ToBytes()
+
+	checkpoint := bytes.Repeat([]byte{0xab}, 32)
+	output := []byte{9, 8, 7}
+	ser = bcs.NewSerializer()
+	ser.U64(42)
+	ser.Uleb128(uint32(TransactionVariantStateCheckpoint))
+	ser.Bytes(checkpoint)
+	ser.FixedBytes(output)
+	txnBytes := ser.ToBytes()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/x-bcs" {
+			t.Errorf("%s: Accept = %q, want application/x-bcs", r.URL.Path, r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "application/x-bcs")
+		w.Header().Set("X-Aptos-Chain-Id", "2")
+		w.Header().Set("X-Aptos-Ledger-Version", "100")
+		w.Header().Set("X-Aptos-Epoch", "7")
+		switch r.URL.Path {
+		case "/accounts/" + AccountOne.String() + "/resources":
+			w.Write(resourcesBytes)
+		case "/transactions/by_version/42":
+			w.Write(txnBytes)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+	wantMetadata := func(t *testing.T, metadata ResponseMetadata) {
+		t.Helper()
+		if metadata.ChainID != 2 || metadata.LedgerVersion != 100 || metadata.Epoch != 7 {
+			t.Errorf("unexpected metadata: %+v", metadata)
+		}
+	}
+
+	resources, err := client.GetAccountResourcesBCS(ctx, AccountOne)
+	if err != nil {
+		t.Fatalf("GetAccountResourcesBCS error: %v", err)
+	}
+	wantMetadata(t, resources.Metadata)
+	all, err := DecodeResourceGroup(resources.Data)
+	if err != nil {
+		t.Fatalf("DecodeResourceGroup error: %v", err)
+	}
+	if data, ok := all.Member("0x1::account::Account"); !ok || !bytes.Equal(data, []byte{3}) {
+		t.Errorf("Member(Account) = %v, %v; want [3], true", data, ok)
+	}
+	data, ok := all.Member("0x1::object::ObjectGroup")
+	if !ok {
+		t.Fatal("resources have no ObjectGroup")
+	}
+	group, err := DecodeResourceGroup(data)
+	if err != nil {
+		t.Fatalf("DecodeResourceGroup(ObjectGroup) error: %v", err)
+	}
+	if data, ok := group.Member("0x1::object::ObjectCore"); !ok || !bytes.Equal(data, []byte{1, 2}) {
+		t.Errorf("Member(ObjectCore) = %v, %v; want [1 2], true", data, ok)
+	}
+
+	txn, err := client.GetTransactionByVersionBCS(ctx, 42)
+	if err != nil {
+		t.Fatalf("GetTransactionByVersionBCS error: %v", err)
+	}
+	wantMetadata(t, txn.Metadata)
+	var onChain TransactionOnChainBCS
+	if err := bcs.Deserialize(txn.Data, &onChain); err != nil {
+		t.Fatalf("BCS deserialize error: %v", err)
+	}
+	if onChain.Version != 42 || onChain.Transaction.Variant != TransactionVariantStateCheckpoint ||
+		!bytes.Equal(onChain.Transaction.StateCheckpoint[:], checkpoint) || !bytes.Equal(onChain.Output, output) {
+		t.Errorf("decoded transaction = %+v", onChain)
+	}
+
+	if _, err := DecodeResourceGroup([]byte{1}); err == nil {
+		t.Error("DecodeResourceGroup of truncated data should fail")
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		status       int
+		retryAfter   string
+		call         func(*Client) error
+		wantRequests int32
+		wantDelay    time.Duration
+		wantErr      bool
+	}{
+		{
+			name:     "server errors",
+			failures: 2,
+			status:   http.StatusServiceUnavailable,
+			call: func(c *Client) error {
+				_, err := c.GetLedgerInfo(context.Background())
+				return err
+			},
+			wantRequests: 3,
+			wantDelay:    30 * time.Millisecond, // 10ms, then 20ms
+		},
+		{
+			name:       "retry after",
+			failures:   1,
+			status:     http.StatusTooManyRequests,
+			retryAfter: "1",
+			call: func(c *Client) error {
+				_, err := c.GetLedgerInfo(context.Background())
+				return err
+			},
+			wantRequests: 2,
+			wantDelay:    time.Second,
+		},
+		{
+			name:     "view",
+			failures: 2,
+			status:   http.StatusInternalServerError,
+			call: func(c *Client) error {
+				_, err := c.View(context.Background(), ViewRequest{Function: "0x1::chain_id::get"})
+				return err
+			},
+			wantRequests: 3,
+			wantDelay:    30 * time.Millisecond,
+		},
+		{
+			name:     "retries exhausted",
+			failures: 4,
+			status:   http.StatusBadGateway,
+			call: func(c *Client) error {
+				_, err := c.GetLedgerInfo(context.Background())
+				return err
+			},
+			wantRequests: 3,
+			wantErr:      true,
+		},
+		{
+			name:     "client errors are not retried",
+			failures: 2,
+			status:   http.StatusBadRequest,
+			call: func(c *Client) error {
+				_, err := c.GetLedgerInfo(context.Background())
+				return err
+			},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		{
+			name:     "submissions are not retried",
+			failures: 2,
+			status:   http.StatusServiceUnavailable,
+			call: func(c *Client) error {
+				_, err := c.SubmitTransaction(context.Background(), []byte{1})
+				return err
+			},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					if body, _ := io.ReadAll(r.Body); len(body) == 0 {
+						t.Error("retried request has an empty body")
+					}
+				}
+				if int(requests.Add(1)) <= tt.failures {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.status)
+					w.Write([]byte(`{"message":"unavailable","error_code":"internal_error"}`))
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == "/view" {
+					w.Write([]byte(`["4"]`))
+					return
+				}
+				w.Write([]byte(`{"chain_id":4}`))
+			}))
+			defer server.Close()
+
+			client, err := NewClient(ClientConfig{NodeURL: server.URL, MaxRetries: 2, RetryBackoff: 10 * time.Millisecond})
+			if err != nil {
+				t.Fatalf("NewClient error: %v", err)
+			}
+			start := time.Now()
+			err = tt.call(client)
+			elapsed := time.Since(start)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", got, tt.wantRequests)
+			}
+			if elapsed < tt.wantDelay {
+				t.Errorf("took %v, want at least %v", elapsed, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := newRetryPolicy(ClientConfig{MaxRetries: 10, RetryBackoff: time.Second, RetryJitter: 0.5})
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		d := policy.delay(attempt, ResponseMetadata{})
+		if d > want || d < want/2 {
+			t.Errorf("delay(%d) = %v, want between %v and %v", attempt, d, want/2, want)
+		}
+	}
+	if d := policy.delay(20, ResponseMetadata{}); d > maxRetryBackoff {
+		t.Errorf("delay(20) = %v, want at most %v", d, maxRetryBackoff)
+	}
+	if d := policy.delay(0, ResponseMetadata{RetryAfter: 3 * time.Second}); d != 3*time.Second {
+		t.Errorf("delay with Retry-After = %v, want 3s", d)
+	}
+	if d := policy.delay(0, ResponseMetadata{RetryAfter: 24 * time.Hour}); d != maxRetryBackoff {
+		t.Errorf("delay with a day's Retry-After = %v, want %v", d, maxRetryBackoff)
+	}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if d := parseRetryAfter("Thu, 01 Jan 2026 00:00:05 GMT", now); d != 5*time.Second {
+		t.Errorf("parseRetryAfter(date) = %v, want 5s", d)
+	}
+	if d := parseRetryAfter("soon", now); d != 0 {
+		t.Errorf("parseRetryAfter(invalid) = %v, want 0", d)
+	}
+}
+
+func TestReplaceTransaction(t *testing.T) {
+	account, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+	payload, err := TransferCoinPayload(AptosCoinType, AccountOne, 5)
+	if err != nil {
+		t.Fatalf("TransferCoinPayload error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		estimate     string
+		replace      func(*Client) (Response[PendingTransaction], error)
+		wantGasPrice uint64
+		wantPayload  *EntryFunction
+	}{
+		{
+			name:     "replace at twice the estimate",
+			estimate: `{"gas_estimate":150,"prioritized_gas_estimate":200}`,
+			replace: func(c *Client) (Response[PendingTransaction], error) {
+				return c.ReplaceTransaction(context.Background(), account, 7, payload)
+			},
+			wantGasPrice: 150 * ReplacementGasPriceMultiplier,
+			wantPayload:  payload.Payload.(*EntryFunction),
+		},
+		{
+			name:     "prioritized estimate when higher",
+			estimate: `{"gas_estimate":100,"prioritized_gas_estimate":500}`,
+			replace: func(c *Client) (Response[PendingTransaction], error) {
+				return c.ReplaceTransaction(context.Background(), account, 7, payload)
+			},
+			wantGasPrice: 500,
+			wantPayload:  payload.Payload.(*EntryFunction),
+		},
+		{
+			name:     "default price without an estimate",
+			estimate: `{"gas_estimate":0}`,
+			replace: func(c *Client) (Response[PendingTransaction], error) {
+				return c.ReplaceTransaction(context.Background(), account, 7, payload)
+			},
+			wantGasPrice: DefaultGasUnitPrice * ReplacementGasPriceMultiplier,
+			wantPayload:  payload.Payload.(*EntryFunction),
+		},
+		{
+			name:     "explicit gas price",
+			estimate: `{"gas_estimate":150}`,
+			replace: func(c *Client) (Response[PendingTransaction], error) {
+				return c.ReplaceTransaction(context.Background(), account, 7, payload, WithGasUnitPrice(1000))
+			},
+			wantGasPrice: 1000,
+			wantPayload:  payload.Payload.(*EntryFunction),
+		},
+		{
+			name:     "above a replaced price higher than the estimate",
+			estimate: `{"gas_estimate":150}`,
+			replace: func(c *Client) (Response[PendingTransaction], error) {
+				return c.ReplaceTransaction(context.Background(), account, 7, payload, WithReplacedGasUnitPrice(1000))
+			},
+			wantGasPrice: 1001,
+			wantPayload:  payload.Payload.(*EntryFunction),
+		},
+		{
+			name:     "estimate when above the replaced price",
+			estimate: `{"gas_estimate":150}`,
+			replace: func(c *Client) (Response[PendingTransaction], error) {
+				return c.ReplaceTransaction(context.Background(), account, 7, payload, WithReplacedGasUnitPrice(100))
+			},
+			wantGasPrice: 150 * ReplacementGasPriceMultiplier,
+			wantPayload:  payload.Payload.(*EntryFunction),
+		},
+		{
+			name:     "cancel",
+			estimate: `{"gas_estimate":150}`,
+			replace: func(c *Client) (Response[PendingTransaction], error) {
+				return c.CancelTransaction(context.Background(), account, 7)
+			},
+			wantGasPrice: 150 * ReplacementGasPriceMultiplier,
+			wantPayload: &EntryFunction{
+				Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+				Function: "transfer",
+				Args:     EntryFunctionArgs(AddressArg(account.Address), U64Arg(0)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var submitted *SignedTransaction
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Aptos-Chain-Id", "4")
+				switch {
+				case r.URL.Path == "/-/healthy":
+				case r.URL.Path == "/estimate_gas_price":
+					w.Write([]byte(tt.estimate))
+				case r.Method == http.MethodPost && r.URL.Path == "/transactions":
+					body, _ := io.ReadAll(r.Body)
+					submitted = &SignedTransaction{}
+					if err := bcs.Deserialize(body, submitted); err != nil {
+						t.Errorf("decode submitted transaction: %v", err)
+					}
+					w.WriteHeader(http.StatusAccepted)
+					w.Write([]byte(`{"hash":"0x1"}`))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			client, err := NewClient(ClientConfig{NodeURL: server.URL})
+			if err != nil {
+				t.Fatalf("NewClient error: %v", err)
+			}
+			if _, err := tt.replace(client); err != nil {
+				t.Fatalf("replace error: %v", err)
+			}
+			if submitted == nil {
+				t.Fatal("no transaction was submitted")
+			}
+			rawTxn := submitted.RawTxn
+			if rawTxn.Sender != account.Address || rawTxn.SequenceNumber != 7 {
+				t.Errorf("sender, sequence number = %s, %d; want %s, 7", rawTxn.Sender, rawTxn.SequenceNumber, account.Address)
+			}
+			if rawTxn.GasUnitPrice != tt.wantGasPrice {
+				t.Errorf("gas unit price = %d, want %d", rawTxn.GasUnitPrice, tt.wantGasPrice)
+			}
+			ef, ok := rawTxn.Payload.Payload.(*EntryFunction)
+			if !ok {
+				t.Fatalf("payload = %T, want *EntryFunction", rawTxn.Payload.Payload)
+			}
+			if ef.Module != tt.wantPayload.Module || ef.Function != tt.wantPayload.Function || len(ef.Args) != len(tt.wantPayload.Args) {
+				t.Fatalf("payload = %+v, want %+v", ef, tt.wantPayload)
+			}
+			for i := range ef.Args {
+				if !bytes.Equal(ef.Args[i], tt.wantPayload.Args[i]) {
+					t.Errorf("argument %d = %x, want %x", i, ef.Args[i], tt.wantPayload.Args[i])
+				}
+			}
+		})
+	}
+
+	// Orderless transactions have no sequence number to replace
+	client, err := NewClient(ClientConfig{NodeURL: "http://127.0.0.1:0"})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	if _, err := client.ReplaceTransaction(context.Background(), account, 7, payload, WithReplayProtectionNonce(1)); err == nil {
+		t.Error("replacing an orderless transaction should fail")
+	}
+
+	// An explicit price must still be above the replaced one
+	if _, err := client.ReplaceTransaction(context.Background(), account, 7, payload, WithGasUnitPrice(500), WithReplacedGasUnitPrice(500)); err == nil {
+		t.Error("replacing at the replaced gas unit price should fail")
+	}
+}
+
+func TestSimulatePayloadWithPublicKey(t *testing.T) {
+	// Public keys only; there is no private key for either sender.
+	ed25519Key, _ := hex.DecodeString("de19e5d1880cac87d57484ce9ed2e84cf0f9599f12e7cc3a52e4e7657a763f2c")
+	secp256k1Key, _ := hex.DecodeString("04acdd16651b839c24665b7e2033b55225f384554949fef46c397b5275f37f6ee95554d70fb5d9f93c5831ebf695c7206e7477ce708f03ae9bb2862dc6c9e033ea")
+	sender := AccountThree
+	payload, err := TransferCoinPayload(AptosCoinType, AccountOne, 5)
+	if err != nil {
+		t.Fatalf("TransferCoinPayload error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		publicKey []byte
+		scheme    crypto.SignatureScheme
+		check     func(t *testing.T, auth TransactionAuthenticator)
+	}{
+		{
+			name:      "ed25519",
+			publicKey: ed25519Key,
+			scheme:    crypto.Ed25519Scheme,
+			check: func(t *testing.T, auth TransactionAuthenticator) {
+				ed, ok := auth.Auth.(*AccountAuthenticatorEd25519)
+				if auth.Variant != TransactionAuthenticatorEd25519 || !ok {
+					t.Fatalf("authenticator = %d %T, want Ed25519", auth.Variant, auth.Auth)
+				}
+				if !bytes.Equal(ed.PublicKey[:], ed25519Key) {
+					t.Errorf("public key = %x, want %x", ed.PublicKey, ed25519Key)
+				}
+				if ed.Signature != [64]byte{} {
+					t.Errorf("signature = %x, want all zeros", ed.Signature)
+				}
+			},
+		},
+		{
+			name:      "secp256k1",
+			publicKey: secp256k1Key,
+			scheme:    crypto.Secp256k1Scheme,
+			check: func(t *testing.T, auth TransactionAuthenticator) {
+				single, ok := auth.Auth.(*AccountAuthenticatorSingleKey)
+				if auth.Variant != TransactionAuthenticatorSingleSender || !ok {
+					t.Fatalf("authenticator = %d %T, want SingleSender", auth.Variant, auth.Auth)
+				}
+				if single.PublicKey.Variant != crypto.Secp256k1Scheme || !bytes.Equal(single.PublicKey.PublicKey, secp256k1Key) {
+					t.Errorf("public key = %d %x, want secp256k1 %x", single.PublicKey.Variant, single.PublicKey.PublicKey, secp256k1Key)
+				}
+				if single.Signature.Variant != crypto.Secp256k1Scheme || !bytes.Equal(single.Signature.Signature, make([]byte, 64)) {
+					t.Errorf("signature = %d %x, want secp256k1 all zeros", single.Signature.Variant, single.Signature.Signature)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var simulated *SignedTransaction
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Aptos-Chain-Id", "4")
+				switch {
+				case r.URL.Path == "/-/healthy":
+				case r.URL.Path == "/estimate_gas_price":
+					w.Write([]byte(`{"gas_estimate":100}`))
+				case r.URL.Path == "/accounts/"+sender.String():
+					w.Write([]byte(`{"sequence_number":"9","authentication_key":"0x` + strings.Repeat("0", 64) + `"}`))
+				case r.Method == http.MethodPost && r.URL.Path == "/transactions/simulate":
+					if r.URL.Query().Get("estimate_max_gas_amount") != "true" {
+						t.Errorf("query = %q, want estimate_max_gas_amount=true", r.URL.RawQuery)
+					}
+					body, _ := io.ReadAll(r.Body)
+					simulated = &SignedTransaction{}
+					if err := bcs.Deserialize(body, simulated); err != nil {
+						t.Errorf("decode simulated transaction: %v", err)
+					}
+					w.Write([]byte(`[{"type":"user_transaction","hash":"0x1","success":true,"vm_status":"Executed successfully","gas_used":"7"}]`))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			client, err := NewClient(ClientConfig{NodeURL: server.URL})
+			if err != nil {
+				t.Fatalf("NewClient error: %v", err)
+			}
+			result, err := client.SimulatePayloadWithPublicKey(context.Background(), sender, tt.publicKey, tt.scheme, payload)
+			if err != nil {
+				t.Fatalf("SimulatePayloadWithPublicKey error: %v", err)
+			}
+			if len(result.Data) != 1 || !result.Data[0].Success {
+				t.Errorf("result = %+v, want one successful transaction", result.Data)
+			}
+			if simulated == nil {
+				t.Fatal("no transaction was simulated")
+			}
+			if simulated.RawTxn.Sender != sender || simulated.RawTxn.SequenceNumber != 9 {
+				t.Errorf("sender, sequence number = %s, %d; want %s, 9", simulated.RawTxn.Sender, simulated.RawTxn.SequenceNumber, sender)
+			}
+			tt.check(t, simulated.Authenticator)
+		})
+	}
+}
+
+func TestSimulateTransactions(t *testing.T) {
+	const n = 3 * maxConcurrentRequests
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if current <= peak || maxInFlight.CompareAndSwap(peak, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if body[0] == 5 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"invalid transaction","error_code":"invalid_input"}`))
+			return
+		}
+		fmt.Fprintf(w, `[{"type":"user_transaction","hash":"0x%d","success":true}]`, body[0])
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	txns := make([][]byte, n)
+	for i := range txns {
+		txns[i] = []byte{byte(i)}
+	}
+
+	results, err := client.SimulateTransactions(context.Background(), txns)
+	if err == nil || !strings.Contains(err.Error(), "simulate transaction 5:") {
+		t.Errorf("SimulateTransactions error = %v, want the failure of transaction 5", err)
+	}
+	if len(results) != n {
+		t.Fatalf("got %d results, want %d", len(results), n)
+	}
+	for i, result := range results {
+		if i == 5 {
+			if ErrorKind(result.Err) != ErrKindInvalidInput {
+				t.Errorf("result 5 error = %v, want invalid input", result.Err)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Errorf("result %d error: %v", i, result.Err)
+			continue
+		}
+		if want := fmt.Sprintf("0x%d", i); len(result.Response.Data) != 1 || result.Response.Data[0].Hash != want {
+			t.Errorf("result %d = %+v, want hash %s", i, result.Response.Data, want)
+		}
+	}
+	if peak := maxInFlight.Load(); peak > maxConcurrentRequests {
+		t.Errorf("%d simulations in flight, want at most %d", peak, maxConcurrentRequests)
+	}
+
+	// Nothing is sent once ctx is canceled
+	var requests atomic.Int32
+	canceling := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`[]`))
+	}))
+	defer canceling.Close()
+	client, err = NewClient(ClientConfig{NodeURL: canceling.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err = client.SimulateTransactions(ctx, txns)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("SimulateTransactions error = %v, want context.Canceled", err)
+	}
+	for i, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("result %d error = %v, want context.Canceled", i, result.Err)
+		}
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("made %d requests after cancel, want 0", got)
+	}
+}
+
+func TestCachedGasEstimate(t *testing.T) {
+	var fail atomic.Bool
+	var estimate atomic.Int32
+	estimate.Store(100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"message":"unavailable","error_code":"internal_error"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// Fields unknown to the SDK are ignored
+		fmt.Fprintf(w, `{"gas_estimate":%d,"prioritized_gas_estimate":150,"future_estimate":{"tier":"new"}}`, estimate.Load())
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client, err := NewClient(ClientConfig{NodeURL: server.URL, Transport: transport})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx := context.Background()
+	const maxAge = time.Hour
+	// age backdates the cached estimate past maxAge and returns its new
+	// fetch time, so the test does not wait on the clock.
+	age := func() time.Time {
+		client.gasPriceMu.Lock()
+		defer client.gasPriceMu.Unlock()
+		client.gasPriceCachedAt = client.gasPriceCachedAt.Add(-2 * maxAge)
+		return client.gasPriceCachedAt
+	}
+
+	first, err := client.CachedGasEstimate(ctx, maxAge)
+	if err != nil {
+		t.Fatalf("CachedGasEstimate error: %v", err)
+	}
+	want := GasEstimation{GasEstimate: 100, PrioritizedGasEstimate: 150}
+	if first.GasEstimation != want || first.Stale || first.FetchedAt.IsZero() {
+		t.Errorf("first estimate = %+v, want %+v, fresh", first, want)
+	}
+
+	// Within maxAge the cache is hit
+	estimate.Store(200)
+	cached, err := client.CachedGasEstimate(ctx, maxAge)
+	if err != nil {
+		t.Fatalf("CachedGasEstimate error: %v", err)
+	}
+	if transport.requests != 1 || cached.GasEstimate != 100 || !cached.FetchedAt.Equal(first.FetchedAt) {
+		t.Errorf("cached estimate = %+v after %d requests, want the first estimate from 1 request", cached, transport.requests)
+	}
+
+	// After maxAge it is refreshed
+	aged := age()
+	refreshed, err := client.CachedGasEstimate(ctx, maxAge)
+	if err != nil {
+		t.Fatalf("CachedGasEstimate error: %v", err)
+	}
+	if transport.requests != 2 || refreshed.GasEstimate != 200 || refreshed.Stale || !refreshed.FetchedAt.After(aged) {
+		t.Errorf("refreshed estimate = %+v after %d requests, want 200 from a second request", refreshed, transport.requests)
+	}
+
+	// A failed refresh falls back to the old estimate, marked stale
+	fail.Store(true)
+	aged = age()
+	stale, err := client.CachedGasEstimate(ctx, maxAge)
+	if err != nil {
+		t.Fatalf("CachedGasEstimate error: %v", err)
+	}
+	if transport.requests != 3 || stale.GasEstimate != 200 || !stale.Stale || !stale.FetchedAt.Equal(aged) {
+		t.Errorf("stale estimate = %+v after %d requests, want the refreshed estimate marked stale", stale, transport.requests)
+	}
+
+	// Without any cached estimate, the error is returned
+	client, err = NewClient(ClientConfig{NodeURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	if _, err := client.CachedGasEstimate(ctx, maxAge); err == nil {
+		t.Error("CachedGasEstimate with nothing cached and a failing node should fail")
+	}
+}
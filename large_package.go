@@ -0,0 +1,132 @@
+package aptos
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// LargePackagesModuleAddress is the address of the large_packages module on
+// mainnet and testnet. Other networks may deploy it elsewhere; use
+// LargePackagePayloads with their address.
+var LargePackagesModuleAddress = mustParseAddress("0x0e1ca3011bdd07246d4d16d909dbb2d6953a86c4735d5acf5865d962c630cce7")
+
+// LargePackageChunkSize is the maximum number of metadata and code bytes
+// staged per transaction, leaving room for the rest of the transaction
+// within the 64 KB transaction size limit.
+const LargePackageChunkSize = 55_000
+
+// LargePackagePayloads splits a package into payloads for the large_packages
+// module at moduleAddress, to be submitted in order by the publishing account.
+// All but the last payload call stage_code_chunk; the last stages the final
+// chunk and publishes the package with
+// stage_code_chunk_and_publish_to_account. metadata is the BCS-encoded
+// PackageMetadata and modules the compiled module bytecode, in dependency
+// order, as produced by `aptos move build-publish-payload`.
+//
+// If a staging transaction fails, call the module's cleanup_staging_area
+// before retrying.
+func LargePackagePayloads(moduleAddress AccountAddress, metadata []byte, modules [][]byte) ([]TransactionPayload, error) {
+	if len(metadata) == 0 {
+		return nil, fmt.Errorf("package metadata is empty")
+	}
+	if len(modules) > math.MaxUint16+1 {
+		return nil, fmt.Errorf("too many modules: %d", len(modules))
+	}
+
+	stage := func(function string, metadataChunk []byte, codeIndices []uint16, codeChunks [][]byte) TransactionPayload {
+		return TransactionPayload{
+			Payload: &EntryFunction{
+				Module:   ModuleId{Address: moduleAddress, Name: "large_packages"},
+				Function: function,
+				Args: EntryFunctionArgs(
+					BytesArg(metadataChunk),
+					VectorU16Arg(codeIndices),
+					VectorBytesArg(codeChunks),
+				),
+			},
+		}
+	}
+
+	// Metadata goes first; its last chunk shares a transaction with the first code chunks
+	metadataChunks := chunkBytes(metadata, LargePackageChunkSize)
+	var payloads []TransactionPayload
+	for _, chunk := range metadataChunks[:len(metadataChunks)-1] {
+		payloads = append(payloads, stage("stage_code_chunk", chunk, nil, nil))
+	}
+
+	metadataChunk := metadataChunks[len(metadataChunks)-1]
+	takenSize := len(metadataChunk)
+	var codeIndices []uint16
+	var codeChunks [][]byte
+	for i, code := range modules {
+		for _, chunk := range chunkBytes(code, LargePackageChunkSize) {
+			if takenSize+len(chunk) > LargePackageChunkSize {
+				payloads = append(payloads, stage("stage_code_chunk", metadataChunk, codeIndices, codeChunks))
+				metadataChunk = nil
+				codeIndices = nil
+				codeChunks = nil
+				takenSize = 0
+			}
+			codeIndices = append(codeIndices, uint16(i))
+			codeChunks = append(codeChunks, chunk)
+			takenSize += len(chunk)
+		}
+	}
+	payloads = append(payloads, stage("stage_code_chunk_and_publish_to_account", metadataChunk, codeIndices, codeChunks))
+	return payloads, nil
+}
+
+// chunkBytes splits data into chunks of at most size bytes. Empty data yields
+// a single empty chunk.
+func chunkBytes(data []byte, size int) [][]byte {
+	if len(data) == 0 {
+		return [][]byte{{}}
+	}
+	chunks := make([][]byte, 0, (len(data)+size-1)/size)
+	for len(data) > size {
+		chunks = append(chunks, data[:size])
+		data = data[size:]
+	}
+	return append(chunks, data)
+}
+
+// PublishLargePackage publishes a package that is too large for a single
+// transaction to the account, using the large_packages module at
+// LargePackagesModuleAddress. The chunks are staged in consecutive
+// transactions, each waiting for the previous one to commit, and the last
+// transaction publishes the package. The committed transactions are returned
+// in order, including a failed one if staging stops early.
+//
+// opts apply to every transaction, so they must not set a sequence number or
+// replay protection nonce. See LargePackagePayloads for the expected inputs.
+func (c *Client) PublishLargePackage(
+	ctx context.Context,
+	account *Account,
+	metadata []byte,
+	modules [][]byte,
+	opts ...BuildOption,
+) ([]Response[Transaction], error) {
+	options := ApplyBuildOptions(opts...)
+	if options.SequenceNumber != nil || options.ReplayProtectionNonce != nil {
+		return nil, fmt.Errorf("cannot set a sequence number or nonce for a multi-transaction publish")
+	}
+
+	payloads, err := LargePackagePayloads(LargePackagesModuleAddress, metadata, modules)
+	if err != nil {
+		return nil, err
+	}
+
+	txns := make([]Response[Transaction], 0, len(payloads))
+	for i, payload := range payloads {
+		txn, err := c.BuildSignAndSubmitTransaction(ctx, account, payload, opts...)
+		if err != nil {
+			return txns, fmt.Errorf("transaction %d of %d: %w", i+1, len(payloads), err)
+		}
+		txns = append(txns, txn)
+		if !txn.Data.Success {
+			return txns, fmt.Errorf("transaction %d of %d failed: %s", i+1, len(payloads), txn.Data.VMStatus)
+		}
+	}
+	return txns, nil
+}
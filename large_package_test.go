@@ -0,0 +1,70 @@
+package aptos
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLargePackagePayloads(t *testing.T) {
+	metadata := bytes.Repeat([]byte{0xaa}, 100)
+	modules := [][]byte{
+		bytes.Repeat([]byte{0x01}, 60_000),
+		bytes.Repeat([]byte{0x02}, 10),
+	}
+
+	payloads, err := LargePackagePayloads(LargePackagesModuleAddress, metadata, modules)
+	if err != nil {
+		t.Fatalf("LargePackagePayloads error: %v", err)
+	}
+	if len(payloads) != 3 {
+		t.Fatalf("got %d payloads, want 3", len(payloads))
+	}
+
+	// Reassemble the package from the staged chunks
+	paramTypes := mustParseTypeTags(t, "vector<u8>", "vector<u16>", "vector<vector<u8>>")
+	var gotMetadata []byte
+	gotModules := make([][]byte, len(modules))
+	for i, payload := range payloads {
+		entryFunction := payload.Payload.(*EntryFunction)
+		wantFunction := "stage_code_chunk"
+		if i == len(payloads)-1 {
+			wantFunction = "stage_code_chunk_and_publish_to_account"
+		}
+		if entryFunction.Module.Address != LargePackagesModuleAddress || entryFunction.Module.Name != "large_packages" || entryFunction.Function != wantFunction {
+			t.Errorf("payload %d calls %s::%s, want large_packages::%s", i, entryFunction.Module.Name, entryFunction.Function, wantFunction)
+		}
+
+		args, err := DecodeEntryFunctionArgs(entryFunction.Args, paramTypes)
+		if err != nil {
+			t.Fatalf("payload %d: DecodeEntryFunctionArgs error: %v", i, err)
+		}
+		gotMetadata = append(gotMetadata, args[0].([]byte)...)
+		indices := args[1].([]interface{})
+		chunks := args[2].([]interface{})
+		if len(indices) != len(chunks) {
+			t.Fatalf("payload %d: %d indices for %d chunks", i, len(indices), len(chunks))
+		}
+		size := len(args[0].([]byte))
+		for j := range indices {
+			index := indices[j].(uint16)
+			gotModules[index] = append(gotModules[index], chunks[j].([]byte)...)
+			size += len(chunks[j].([]byte))
+		}
+		if size > LargePackageChunkSize {
+			t.Errorf("payload %d stages %d bytes, more than %d", i, size, LargePackageChunkSize)
+		}
+	}
+
+	if !bytes.Equal(gotMetadata, metadata) {
+		t.Errorf("staged metadata does not match")
+	}
+	for i := range modules {
+		if !bytes.Equal(gotModules[i], modules[i]) {
+			t.Errorf("staged module %d does not match", i)
+		}
+	}
+
+	if _, err := LargePackagePayloads(LargePackagesModuleAddress, nil, modules); err == nil {
+		t.Error("expected error for empty metadata")
+	}
+}
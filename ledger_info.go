@@ -1,16 +1,21 @@
 package aptos
 
+import (
+	"fmt"
+	"time"
+)
+
 // LedgerInfo contains information about the current state of the ledger.
 type LedgerInfo struct {
-	ChainID             uint8  `json:"chain_id"`
-	Epoch               string `json:"epoch"`
-	LedgerVersion       string `json:"ledger_version"`
-	OldestLedgerVersion string `json:"oldest_ledger_version"`
-	LedgerTimestamp     string `json:"ledger_timestamp"`
-	NodeRole            string `json:"node_role"`
-	OldestBlockHeight   string `json:"oldest_block_height"`
-	BlockHeight         string `json:"block_height"`
-	GitHash             string `json:"git_hash"`
+	ChainID             uint8      `json:"chain_id"`
+	Epoch               JSONUint64 `json:"epoch"`
+	LedgerVersion       JSONUint64 `json:"ledger_version"`
+	OldestLedgerVersion JSONUint64 `json:"oldest_ledger_version"`
+	LedgerTimestamp     JSONUint64 `json:"ledger_timestamp"`
+	NodeRole            string     `json:"node_role"`
+	OldestBlockHeight   JSONUint64 `json:"oldest_block_height"`
+	BlockHeight         JSONUint64 `json:"block_height"`
+	GitHash             string     `json:"git_hash"`
 }
 
 // ResponseMetadata contains metadata from Aptos API response headers.
@@ -23,6 +28,30 @@ type ResponseMetadata struct {
 	BlockHeight         uint64
 	OldestBlockHeight   uint64
 	Cursor              string
+
+	// RateLimit is the request quota of the current rate-limit window, from
+	// the X-RateLimit-Limit header. It is zero if the node or gateway did not
+	// report rate limits, in which case the other rate-limit fields are unset.
+	RateLimit uint64
+
+	// RateLimitRemaining is the number of requests left in the current window.
+	RateLimitRemaining uint64
+
+	// RateLimitReset is when the current window ends, or the zero time if unknown.
+	RateLimitReset time.Time
+
+	// RetryAfter is how long the node asked clients to wait before retrying,
+	// from the Retry-After header of a 429 or 503 response, or zero if unset.
+	RetryAfter time.Duration
+}
+
+// Time returns the ledger timestamp from the LedgerTimestampUsec header.
+// Returns the zero time if the header was not present.
+func (m ResponseMetadata) Time() time.Time {
+	if m.LedgerTimestampUsec == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(int64(m.LedgerTimestampUsec))
 }
 
 // Response wraps an API response with metadata from headers.
@@ -39,20 +68,79 @@ type BCSResponse struct {
 
 // NodeInfo contains basic information about a node.
 type NodeInfo struct {
-	ChainID             uint8  `json:"chain_id"`
-	Epoch               string `json:"epoch"`
-	LedgerVersion       string `json:"ledger_version"`
-	OldestLedgerVersion string `json:"oldest_ledger_version"`
-	LedgerTimestamp     string `json:"ledger_timestamp"`
-	NodeRole            string `json:"node_role"`
-	OldestBlockHeight   string `json:"oldest_block_height"`
-	BlockHeight         string `json:"block_height"`
-	GitHash             string `json:"git_hash"`
+	ChainID             uint8      `json:"chain_id"`
+	Epoch               JSONUint64 `json:"epoch"`
+	LedgerVersion       JSONUint64 `json:"ledger_version"`
+	OldestLedgerVersion JSONUint64 `json:"oldest_ledger_version"`
+	LedgerTimestamp     JSONUint64 `json:"ledger_timestamp"`
+	NodeRole            string     `json:"node_role"`
+	OldestBlockHeight   JSONUint64 `json:"oldest_block_height"`
+	BlockHeight         JSONUint64 `json:"block_height"`
+	GitHash             string     `json:"git_hash"`
 }
 
 // GasEstimation contains gas price estimation from the node.
+// Only gas_estimate is guaranteed to be present; the other estimates are zero
+// when a node omits them, and fields unknown to this SDK are ignored.
 type GasEstimation struct {
 	DeprioritizedGasEstimate uint64 `json:"deprioritized_gas_estimate"`
 	GasEstimate              uint64 `json:"gas_estimate"`
 	PrioritizedGasEstimate   uint64 `json:"prioritized_gas_estimate"`
 }
+
+// GasPriority expresses how urgently a transaction should be included, and
+// selects one of the tiers of a GasEstimation.
+type GasPriority uint8
+
+const (
+	// GasPriorityNormal uses the regular gas estimate. It is the default.
+	GasPriorityNormal GasPriority = iota
+	// GasPriorityLow uses the deprioritized estimate, for transactions that
+	// can wait when the network is busy.
+	GasPriorityLow
+	// GasPriorityHigh uses the prioritized estimate, for transactions that
+	// should be included ahead of most others.
+	GasPriorityHigh
+)
+
+// String returns the name of the priority.
+func (p GasPriority) String() string {
+	switch p {
+	case GasPriorityNormal:
+		return "normal"
+	case GasPriorityLow:
+		return "low"
+	case GasPriorityHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("GasPriority(%d)", uint8(p))
+	}
+}
+
+// UnitPrice returns the gas unit price estimate for a priority, falling back
+// to GasEstimate when the node omitted the requested tier.
+func (e GasEstimation) UnitPrice(priority GasPriority) uint64 {
+	var price uint64
+	switch priority {
+	case GasPriorityLow:
+		price = e.DeprioritizedGasEstimate
+	case GasPriorityHigh:
+		price = e.PrioritizedGasEstimate
+	}
+	if price == 0 {
+		price = e.GasEstimate
+	}
+	return price
+}
+
+// CachedGasEstimation is a gas price estimation together with its age.
+type CachedGasEstimation struct {
+	GasEstimation
+
+	// FetchedAt is when the estimation was fetched from the node.
+	FetchedAt time.Time
+
+	// Stale is true if a fresh estimation could not be fetched and an
+	// estimation older than the requested maximum age was returned instead.
+	Stale bool
+}
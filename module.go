@@ -1,6 +1,9 @@
 package aptos
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // MoveModuleBytecode represents a Move module with its bytecode and ABI.
 type MoveModuleBytecode struct {
@@ -17,6 +20,16 @@ type MoveModule struct {
 	Structs          []MoveStruct     `json:"structs"`
 }
 
+// Function returns the exposed function with the given name.
+func (m *MoveModule) Function(name string) (*MoveFunction, bool) {
+	for i := range m.ExposedFunctions {
+		if m.ExposedFunctions[i].Name == name {
+			return &m.ExposedFunctions[i], true
+		}
+	}
+	return nil, false
+}
+
 // MoveFunction represents a Move function.
 type MoveFunction struct {
 	Name              string   `json:"name"`
@@ -53,3 +66,19 @@ type MoveStructField struct {
 	Name string          `json:"name"`
 	Type json.RawMessage `json:"type"`
 }
+
+// TypeTag parses the field's type into a TypeTag with ParseMoveType, so a
+// struct's fields can be inspected to decode resources generically. Fields
+// of a generic struct may contain GenericTag placeholders, which refer to
+// the struct's GenericTypeParams by index.
+func (f MoveStructField) TypeTag() (TypeTag, error) {
+	var s string
+	if err := json.Unmarshal(f.Type, &s); err != nil {
+		return TypeTag{}, fmt.Errorf("field %s: invalid type %s: %w", f.Name, f.Type, err)
+	}
+	tag, err := ParseMoveType(s)
+	if err != nil {
+		return TypeTag{}, fmt.Errorf("field %s: %w", f.Name, err)
+	}
+	return tag, nil
+}
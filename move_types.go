@@ -2,13 +2,51 @@ package aptos
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
 	"strings"
 
 	"github.com/0xbe1/aptopher/bcs"
 )
 
+// JSONUint64 is a u64 that the node API represents in JSON as a decimal
+// string, such as a version, sequence number, or timestamp. It is parsed once
+// when decoding, and fails to decode if the string is not a valid u64.
+type JSONUint64 uint64
+
+// Uint64 returns the value as uint64.
+func (u JSONUint64) Uint64() uint64 {
+	return uint64(u)
+}
+
+// String returns the decimal string representation.
+func (u JSONUint64) String() string {
+	return strconv.FormatUint(uint64(u), 10)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u JSONUint64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It also accepts a JSON number.
+func (u *JSONUint64) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid u64 %s: %w", data, err)
+	}
+	*u = JSONUint64(v)
+	return nil
+}
+
 // U128 represents a 128-bit unsigned integer.
 // In JSON, it's represented as a decimal string.
 type U128 struct {
@@ -20,17 +58,22 @@ func NewU128(v uint64) U128 {
 	return U128{value: new(big.Int).SetUint64(v)}
 }
 
-// NewU128FromBigInt creates a U128 from a big.Int.
+// NewU128FromBigInt creates a U128 from a big.Int. The value is not
+// range-checked; MarshalBCS fails if it is negative or does not fit.
 func NewU128FromBigInt(v *big.Int) U128 {
 	return U128{value: new(big.Int).Set(v)}
 }
 
-// U128FromString parses a decimal string into a U128.
+// U128FromString parses a decimal string into a U128. It rejects negative
+// values and values larger than the maximum u128.
 func U128FromString(s string) (U128, error) {
 	v, ok := new(big.Int).SetString(s, 10)
 	if !ok {
 		return U128{}, fmt.Errorf("invalid U128 string: %s", s)
 	}
+	if err := checkUintRange(v, 128); err != nil {
+		return U128{}, fmt.Errorf("invalid U128 string %s: %w", s, err)
+	}
 	return U128{value: v}, nil
 }
 
@@ -77,16 +120,29 @@ func (u *U128) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// MarshalBCS implements bcs.Marshaler.
+// MarshalBCS implements bcs.Marshaler. It sets an error on the serializer
+// if the value is negative or larger than the maximum u128.
 func (u U128) MarshalBCS(ser *bcs.Serializer) {
 	ser.U128(u.BigInt())
 }
 
-// UnmarshalBCS implements bcs.Unmarshaler.
+// UnmarshalBCS implements bcs.Unmarshaler. The 16 bytes read always
+// fit the type.
 func (u *U128) UnmarshalBCS(des *bcs.Deserializer) {
 	u.value = des.U128()
 }
 
+// checkUintRange returns an error if v is negative or needs more than bits bits.
+func checkUintRange(v *big.Int, bits int) error {
+	if v.Sign() < 0 {
+		return errors.New("negative value")
+	}
+	if v.BitLen() > bits {
+		return fmt.Errorf("value exceeds %d bits", bits)
+	}
+	return nil
+}
+
 // U256 represents a 256-bit unsigned integer.
 type U256 struct {
 	value *big.Int
@@ -97,17 +153,22 @@ func NewU256(v uint64) U256 {
 	return U256{value: new(big.Int).SetUint64(v)}
 }
 
-// NewU256FromBigInt creates a U256 from a big.Int.
+// NewU256FromBigInt creates a U256 from a big.Int. The value is not
+// range-checked; MarshalBCS fails if it is negative or does not fit.
 func NewU256FromBigInt(v *big.Int) U256 {
 	return U256{value: new(big.Int).Set(v)}
 }
 
-// U256FromString parses a decimal string into a U256.
+// U256FromString parses a decimal string into a U256. It rejects negative
+// values and values larger than the maximum u256.
 func U256FromString(s string) (U256, error) {
 	v, ok := new(big.Int).SetString(s, 10)
 	if !ok {
 		return U256{}, fmt.Errorf("invalid U256 string: %s", s)
 	}
+	if err := checkUintRange(v, 256); err != nil {
+		return U256{}, fmt.Errorf("invalid U256 string %s: %w", s, err)
+	}
 	return U256{value: v}, nil
 }
 
@@ -146,12 +207,14 @@ func (u *U256) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// MarshalBCS implements bcs.Marshaler.
+// MarshalBCS implements bcs.Marshaler. It sets an error on the serializer
+// if the value is negative or larger than the maximum u256.
 func (u U256) MarshalBCS(ser *bcs.Serializer) {
 	ser.U256(u.BigInt())
 }
 
-// UnmarshalBCS implements bcs.Unmarshaler.
+// UnmarshalBCS implements bcs.Unmarshaler. The 32 bytes read always
+// fit the type.
 func (u *U256) UnmarshalBCS(des *bcs.Deserializer) {
 	u.value = des.U256()
 }
@@ -171,6 +234,11 @@ const (
 	TypeTagU16     TypeTagVariant = 8
 	TypeTagU32     TypeTagVariant = 9
 	TypeTagU256    TypeTagVariant = 10
+
+	// TypeTagReference and TypeTagGeneric only occur in Move ABIs (see
+	// ParseMoveType); they are not valid in transactions or on-chain data.
+	TypeTagReference TypeTagVariant = 254
+	TypeTagGeneric   TypeTagVariant = 255
 )
 
 // TypeTag represents a Move type.
@@ -343,6 +411,52 @@ func (v *VectorTag) UnmarshalBCS(des *bcs.Deserializer) {
 	v.ElementType.UnmarshalBCS(des)
 }
 
+// ReferenceTag represents a reference type, such as "&signer" or
+// "&mut 0x1::coin::Coin<T0>", as found in function parameters of Move ABIs.
+// It cannot be BCS-serialized.
+type ReferenceTag struct {
+	Mutable bool
+	To      TypeTag
+}
+
+func (ReferenceTag) typeTagVariant() TypeTagVariant { return TypeTagReference }
+
+func (r ReferenceTag) String() string {
+	if r.Mutable {
+		return "&mut " + r.To.String()
+	}
+	return "&" + r.To.String()
+}
+
+func (r ReferenceTag) MarshalBCS(ser *bcs.Serializer) {
+	ser.SetError(fmt.Errorf("reference type %s cannot be serialized", r.String()))
+}
+
+func (r *ReferenceTag) UnmarshalBCS(des *bcs.Deserializer) {
+	des.SetError(fmt.Errorf("reference types cannot be deserialized"))
+}
+
+// GenericTag represents a generic type parameter placeholder, such as "T0",
+// which refers to the type parameter at Index of the enclosing struct or
+// function in a Move ABI. It cannot be BCS-serialized.
+type GenericTag struct {
+	Index uint16
+}
+
+func (GenericTag) typeTagVariant() TypeTagVariant { return TypeTagGeneric }
+
+func (g GenericTag) String() string {
+	return fmt.Sprintf("T%d", g.Index)
+}
+
+func (g GenericTag) MarshalBCS(ser *bcs.Serializer) {
+	ser.SetError(fmt.Errorf("generic type parameter %s cannot be serialized", g.String()))
+}
+
+func (g *GenericTag) UnmarshalBCS(des *bcs.Deserializer) {
+	des.SetError(fmt.Errorf("generic type parameters cannot be deserialized"))
+}
+
 // StructTag represents a struct type.
 type StructTag struct {
 	Address    AccountAddress
@@ -365,6 +479,36 @@ func (s StructTag) String() string {
 	return result
 }
 
+// ResourceString returns the struct type in the form every node accepts in
+// resource and view paths: addresses in full 64-hex-digit form and type
+// parameters separated by "," without spaces, e.g.
+// "0x0000000000000000000000000000000000000000000000000000000000000001::coin::CoinStore<...>".
+// String uses short addresses, which some nodes reject for non-special
+// addresses.
+func (s StructTag) ResourceString() string {
+	result := fmt.Sprintf("%s::%s::%s", s.Address.String(), s.Module, s.Name)
+	if len(s.TypeParams) > 0 {
+		params := make([]string, len(s.TypeParams))
+		for i, p := range s.TypeParams {
+			params[i] = typeTagResourceString(p)
+		}
+		result += "<" + strings.Join(params, ",") + ">"
+	}
+	return result
+}
+
+// typeTagResourceString formats a type tag for StructTag.ResourceString.
+func typeTagResourceString(t TypeTag) string {
+	switch v := t.Value.(type) {
+	case *StructTag:
+		return v.ResourceString()
+	case *VectorTag:
+		return "vector<" + typeTagResourceString(v.ElementType) + ">"
+	default:
+		return t.String()
+	}
+}
+
 func (s StructTag) MarshalBCS(ser *bcs.Serializer) {
 	s.Address.MarshalBCS(ser)
 	ser.String(s.Module)
@@ -389,8 +533,38 @@ func (s *StructTag) UnmarshalBCS(des *bcs.Deserializer) {
 // ParseTypeTag parses a type tag string into a TypeTag.
 // Examples: "u64", "address", "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>"
 func ParseTypeTag(s string) (TypeTag, error) {
+	return parseTypeTag(s, false)
+}
+
+// ParseMoveType parses a type as written in a Move ABI returned by the node,
+// such as MoveStructField.Type or MoveFunction.Params. In addition to what
+// ParseTypeTag accepts, it parses generic type parameters ("T0") into a
+// GenericTag and references ("&signer", "&mut T0") into a ReferenceTag.
+func ParseMoveType(s string) (TypeTag, error) {
+	return parseTypeTag(s, true)
+}
+
+// parseTypeTag parses a type tag, accepting the ABI-only generic and
+// reference forms if abi is set.
+func parseTypeTag(s string, abi bool) (TypeTag, error) {
 	s = strings.TrimSpace(s)
 
+	if abi {
+		if rest, ok := strings.CutPrefix(s, "&"); ok {
+			rest, mutable := strings.CutPrefix(rest, "mut ")
+			to, err := parseTypeTag(rest, abi)
+			if err != nil {
+				return TypeTag{}, fmt.Errorf("invalid reference type: %w", err)
+			}
+			return TypeTag{Value: &ReferenceTag{Mutable: mutable, To: to}}, nil
+		}
+		if digits, ok := strings.CutPrefix(s, "T"); ok && digits != "" {
+			if index, err := strconv.ParseUint(digits, 10, 16); err == nil {
+				return TypeTag{Value: &GenericTag{Index: uint16(index)}}, nil
+			}
+		}
+	}
+
 	// Primitive types
 	switch s {
 	case "bool":
@@ -416,7 +590,7 @@ func ParseTypeTag(s string) (TypeTag, error) {
 	// Vector type
 	if strings.HasPrefix(s, "vector<") && strings.HasSuffix(s, ">") {
 		inner := s[7 : len(s)-1]
-		elemType, err := ParseTypeTag(inner)
+		elemType, err := parseTypeTag(inner, abi)
 		if err != nil {
 			return TypeTag{}, fmt.Errorf("invalid vector element type: %w", err)
 		}
@@ -424,10 +598,10 @@ func ParseTypeTag(s string) (TypeTag, error) {
 	}
 
 	// Struct type: address::module::name<type_params>
-	return parseStructTag(s)
+	return parseStructTag(s, abi)
 }
 
-func parseStructTag(s string) (TypeTag, error) {
+func parseStructTag(s string, abi bool) (TypeTag, error) {
 	// Find type parameters
 	var typeParamsStr string
 	angleStart := strings.Index(s, "<")
@@ -458,7 +632,7 @@ func parseStructTag(s string) (TypeTag, error) {
 
 	// Parse type parameters
 	if typeParamsStr != "" {
-		params, err := parseTypeParams(typeParamsStr)
+		params, err := parseTypeParams(typeParamsStr, abi)
 		if err != nil {
 			return TypeTag{}, err
 		}
@@ -469,7 +643,7 @@ func parseStructTag(s string) (TypeTag, error) {
 }
 
 // parseTypeParams parses comma-separated type parameters, handling nested generics.
-func parseTypeParams(s string) ([]TypeTag, error) {
+func parseTypeParams(s string, abi bool) ([]TypeTag, error) {
 	var params []TypeTag
 	var depth int
 	var start int
@@ -482,7 +656,7 @@ func parseTypeParams(s string) ([]TypeTag, error) {
 			depth--
 		case ',':
 			if depth == 0 {
-				param, err := ParseTypeTag(strings.TrimSpace(s[start:i]))
+				param, err := parseTypeTag(s[start:i], abi)
 				if err != nil {
 					return nil, err
 				}
@@ -494,7 +668,7 @@ func parseTypeParams(s string) ([]TypeTag, error) {
 
 	// Last parameter
 	if start < len(s) {
-		param, err := ParseTypeTag(strings.TrimSpace(s[start:]))
+		param, err := parseTypeTag(s[start:], abi)
 		if err != nil {
 			return nil, err
 		}
@@ -2,7 +2,10 @@ package aptos
 
 import (
 	"encoding/json"
+	"math/big"
 	"testing"
+
+	"github.com/0xbe1/aptopher/bcs"
 )
 
 func TestU128(t *testing.T) {
@@ -41,6 +44,49 @@ func TestU128(t *testing.T) {
 	}
 }
 
+func TestUintFromStringRange(t *testing.T) {
+	maxU256 := "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+	tests := []struct {
+		name    string
+		parse   func(string) error
+		input   string
+		wantErr bool
+	}{
+		{"u128 max", parseU128, "340282366920938463463374607431768211455", false},
+		{"u128 overflow", parseU128, "340282366920938463463374607431768211456", true},
+		{"u128 negative", parseU128, "-1", true},
+		{"u256 max", parseU256, maxU256, false},
+		{"u256 overflow", parseU256, "115792089237316195423570985008687907853269984665640564039457584007913129639936", true},
+		{"u256 negative", parseU256, "-1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.parse(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	var u U128
+	if err := json.Unmarshal([]byte(`"340282366920938463463374607431768211456"`), &u); err == nil {
+		t.Error("json.Unmarshal should reject an out-of-range U128")
+	}
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), 128)
+	if _, err := bcs.Serialize(NewU128FromBigInt(tooLarge)); err == nil {
+		t.Error("MarshalBCS should fail for an out-of-range U128")
+	}
+}
+
+func parseU128(s string) error {
+	_, err := U128FromString(s)
+	return err
+}
+
+func parseU256(s string) error {
+	_, err := U256FromString(s)
+	return err
+}
+
 func TestParseTypeTag(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -110,3 +156,139 @@ func TestModuleId(t *testing.T) {
 	}
 }
 
+
+func TestStructTagResourceString(t *testing.T) {
+	one := "0x0000000000000000000000000000000000000000000000000000000000000001"
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"0x1::account::Account", one + "::account::Account"},
+		{"0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>", one + "::coin::CoinStore<" + one + "::aptos_coin::AptosCoin>"},
+		{"0xabc::pool::Pool<u64, vector<0x1::string::String>>", "0x0000000000000000000000000000000000000000000000000000000000000abc::pool::Pool<u64,vector<" + one + "::string::String>>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tag := mustParseTypeTags(t, tt.input)[0]
+			got := tag.Value.(*StructTag).ResourceString()
+			if got != tt.want {
+				t.Errorf("ResourceString() = %v, want %v", got, tt.want)
+			}
+			reparsed, err := ParseTypeTag(got)
+			if err != nil {
+				t.Fatalf("ParseTypeTag(%q) error: %v", got, err)
+			}
+			if reparsed.String() != tag.String() {
+				t.Errorf("reparsed = %v, want %v", reparsed.String(), tag.String())
+			}
+		})
+	}
+}
+
+func TestParseMoveType(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"u64", "u64"},
+		{"T0", "T0"},
+		{"vector<T1>", "vector<T1>"},
+		{"&signer", "&signer"},
+		{"&mut 0x1::coin::Coin<T0>", "&mut 0x1::coin::Coin<T0>"},
+		{"0x1::table::Table<address, vector<T0>>", "0x1::table::Table<address, vector<T0>>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMoveType(tt.input)
+			if err != nil {
+				t.Fatalf("ParseMoveType(%q) error: %v", tt.input, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseMoveType(%q) = %v, want %v", tt.input, got.String(), tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"T0", "&signer", "vector<T0>"} {
+		if _, err := ParseTypeTag(input); err == nil {
+			t.Errorf("ParseTypeTag(%q) should fail outside ABIs", input)
+		}
+	}
+	if _, err := bcs.Serialize(mustParseMoveType(t, "vector<T0>")); err == nil {
+		t.Error("serializing a generic type parameter should fail")
+	}
+}
+
+func TestMoveStructFieldTypeTag(t *testing.T) {
+	var s MoveStruct
+	data := `{"name":"CoinStore","generic_type_params":[{"constraints":[]}],"fields":[
+		{"name":"coin","type":"0x1::coin::Coin<T0>"},
+		{"name":"frozen","type":"bool"}]}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	coin, err := s.Fields[0].TypeTag()
+	if err != nil {
+		t.Fatalf("TypeTag error: %v", err)
+	}
+	structTag, ok := coin.Value.(*StructTag)
+	if !ok || len(structTag.TypeParams) != 1 {
+		t.Fatalf("coin type = %v, want struct with one type parameter", coin)
+	}
+	if g, ok := structTag.TypeParams[0].Value.(*GenericTag); !ok || g.Index != 0 {
+		t.Errorf("coin type parameter = %v, want T0", structTag.TypeParams[0])
+	}
+
+	if _, err := (MoveStructField{Name: "bad", Type: json.RawMessage(`{"vector":"u8"}`)}).TypeTag(); err == nil {
+		t.Error("TypeTag should fail for a non-string type")
+	}
+}
+
+func mustParseMoveType(t *testing.T, s string) TypeTag {
+	t.Helper()
+	tag, err := ParseMoveType(s)
+	if err != nil {
+		t.Fatalf("ParseMoveType(%q) error: %v", s, err)
+	}
+	return tag
+}
+
+func TestJSONUint64(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    uint64
+		wantErr bool
+	}{
+		{`"0"`, 0, false},
+		{`"18446744073709551615"`, 18446744073709551615, false},
+		{`42`, 42, false},
+		{`""`, 0, true},
+		{`"-1"`, 0, true},
+		{`"18446744073709551616"`, 0, true},
+		{`"12abc"`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var got JSONUint64
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got.Uint64() != tt.want {
+				t.Errorf("Unmarshal(%s) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	data, err := json.Marshal(JSONUint64(123))
+	if err != nil || string(data) != `"123"` {
+		t.Errorf("Marshal = %s, %v, want \"123\"", data, err)
+	}
+
+	var txn Transaction
+	if err := json.Unmarshal([]byte(`{"version":"12","gas_used":"bad"}`), &txn); err == nil {
+		t.Error("decoding a malformed u64 field should fail")
+	}
+}
@@ -0,0 +1,136 @@
+package aptos
+
+import (
+	"fmt"
+
+	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/crypto"
+)
+
+// multisigAccountDomainSeparator prefixes the seed of multisig account
+// addresses in 0x1::multisig_account.
+const multisigAccountDomainSeparator = "aptos_framework::multisig_account"
+
+// CreateMultisigAccountPayload returns a payload that creates a multisig
+// (v2) account with 0x1::multisig_account::create_with_owners. The sender
+// becomes an owner as well, so owners lists only the additional owners, and
+// threshold is the number of owner approvals required to execute a
+// transaction. Use MultisigAccountAddress to predict the new account's
+// address.
+func CreateMultisigAccountPayload(owners []AccountAddress, threshold uint64) (TransactionPayload, error) {
+	if threshold == 0 || threshold > uint64(len(owners))+1 {
+		return TransactionPayload{}, fmt.Errorf("invalid threshold %d for %d owners", threshold, len(owners)+1)
+	}
+	return multisigAccountPayload("create_with_owners",
+		VectorAddressArg(owners),
+		U64Arg(threshold),
+		VectorStringArg(nil), // metadata_keys
+		VectorBytesArg(nil),  // metadata_values
+	), nil
+}
+
+// MultisigAccountAddress predicts the address of the multisig account created
+// by creator in the transaction with the given sequence number, matching
+// 0x1::multisig_account::get_next_multisig_account_address evaluated before
+// that transaction. Pass the sequence number of the creating transaction,
+// such as the SequenceNumber returned by GetAccount before building it.
+func MultisigAccountAddress(creator AccountAddress, sequenceNumber uint64) AccountAddress {
+	seed := append([]byte(multisigAccountDomainSeparator), bcs.SerializeU64(sequenceNumber)...)
+	return CreateResourceAddress(creator, seed)
+}
+
+// multisigAccountModule is the module holding the multisig (v2) account
+// entry functions.
+var multisigAccountModule = ModuleId{Address: AccountOne, Name: "multisig_account"}
+
+// MultisigTransactionPayloadBytes returns the BCS encoding of the
+// MultisigTransactionPayload that wraps entryFunction: the form in which a
+// proposal's payload is stored on chain and passed to
+// 0x1::multisig_account::create_transaction.
+func MultisigTransactionPayloadBytes(entryFunction *EntryFunction) ([]byte, error) {
+	ser := bcs.NewSerializer()
+	marshalMultisigTransactionPayload(ser, entryFunction)
+	if err := ser.Error(); err != nil {
+		return nil, err
+	}
+	return ser.ToBytes(), nil
+}
+
+// MultisigTransactionPayloadHash returns the hash of a proposal that stores
+// only the hash of its payload, as created with
+// CreateMultisigTransactionWithHashPayload: the SHA3-256 of
+// MultisigTransactionPayloadBytes. The chain checks the payload submitted at
+// execution against it.
+func MultisigTransactionPayloadHash(entryFunction *EntryFunction) ([]byte, error) {
+	payloadBytes, err := MultisigTransactionPayloadBytes(entryFunction)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sha3256Hash(payloadBytes), nil
+}
+
+// CreateMultisigTransactionPayload returns a payload with which an owner of
+// the multisig account proposes entryFunction, storing it in full on chain
+// with 0x1::multisig_account::create_transaction. The proposal is assigned
+// the account's next multisig sequence number, and the proposer's approval
+// is recorded.
+func CreateMultisigTransactionPayload(multisigAddress AccountAddress, entryFunction *EntryFunction) (TransactionPayload, error) {
+	payloadBytes, err := MultisigTransactionPayloadBytes(entryFunction)
+	if err != nil {
+		return TransactionPayload{}, err
+	}
+	return multisigAccountPayload("create_transaction", AddressArg(multisigAddress), BytesArg(payloadBytes)), nil
+}
+
+// CreateMultisigTransactionWithHashPayload is like
+// CreateMultisigTransactionPayload, but stores only the payload's hash, with
+// 0x1::multisig_account::create_transaction_with_hash. This suits large
+// payloads; the full entry function must then be supplied to
+// ExecuteMultisigTransactionPayload.
+func CreateMultisigTransactionWithHashPayload(multisigAddress AccountAddress, entryFunction *EntryFunction) (TransactionPayload, error) {
+	hash, err := MultisigTransactionPayloadHash(entryFunction)
+	if err != nil {
+		return TransactionPayload{}, err
+	}
+	return multisigAccountPayload("create_transaction_with_hash", AddressArg(multisigAddress), BytesArg(hash)), nil
+}
+
+// ApproveMultisigTransactionPayload returns a payload with which an owner
+// approves the proposal with the given multisig sequence number, with
+// 0x1::multisig_account::approve_transaction.
+func ApproveMultisigTransactionPayload(multisigAddress AccountAddress, sequenceNumber uint64) TransactionPayload {
+	return multisigAccountPayload("approve_transaction", AddressArg(multisigAddress), U64Arg(sequenceNumber))
+}
+
+// RejectMultisigTransactionPayload returns a payload with which an owner
+// rejects the proposal with the given multisig sequence number, with
+// 0x1::multisig_account::reject_transaction.
+func RejectMultisigTransactionPayload(multisigAddress AccountAddress, sequenceNumber uint64) TransactionPayload {
+	return multisigAccountPayload("reject_transaction", AddressArg(multisigAddress), U64Arg(sequenceNumber))
+}
+
+// ExecuteMultisigTransactionPayload returns the payload with which an owner
+// executes the multisig account's next proposal once it has enough
+// approvals. The transaction runs as the multisig account. Pass the proposed
+// entry function if the proposal stores only its hash, or nil to execute the
+// payload stored on chain.
+func ExecuteMultisigTransactionPayload(multisigAddress AccountAddress, entryFunction *EntryFunction) TransactionPayload {
+	return TransactionPayload{
+		Payload: &MultisigPayload{
+			MultisigAddress:    multisigAddress,
+			TransactionPayload: entryFunction,
+		},
+	}
+}
+
+// multisigAccountPayload returns a payload calling a
+// 0x1::multisig_account entry function.
+func multisigAccountPayload(function string, args ...EntryFunctionArg) TransactionPayload {
+	return TransactionPayload{
+		Payload: &EntryFunction{
+			Module:   multisigAccountModule,
+			Function: function,
+			Args:     EntryFunctionArgs(args...),
+		},
+	}
+}
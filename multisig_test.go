@@ -0,0 +1,162 @@
+package aptos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/crypto"
+)
+
+func TestCreateMultisigAccountPayload(t *testing.T) {
+	owners := []AccountAddress{MustParseAccountAddress("0xa"), MustParseAccountAddress("0xb")}
+
+	payload, err := CreateMultisigAccountPayload(owners, 2)
+	if err != nil {
+		t.Fatalf("CreateMultisigAccountPayload error: %v", err)
+	}
+	entryFunction := payload.Payload.(*EntryFunction)
+	if entryFunction.Module.Name != "multisig_account" || entryFunction.Function != "create_with_owners" {
+		t.Errorf("unexpected function %s::%s", entryFunction.Module.Name, entryFunction.Function)
+	}
+	values, err := DecodeEntryFunctionArgs(entryFunction.Args, mustParseTypeTags(t,
+		"vector<address>", "u64", "vector<0x1::string::String>", "vector<vector<u8>>"))
+	if err != nil {
+		t.Fatalf("DecodeEntryFunctionArgs error: %v", err)
+	}
+	if got := values[0].([]interface{}); len(got) != 2 || got[1].(AccountAddress) != owners[1] {
+		t.Errorf("additional_owners = %v", got)
+	}
+	if values[1].(uint64) != 2 {
+		t.Errorf("num_signatures_required = %v, want 2", values[1])
+	}
+
+	for _, threshold := range []uint64{0, 4} {
+		if _, err := CreateMultisigAccountPayload(owners, threshold); err == nil {
+			t.Errorf("threshold %d should be rejected for 3 owners", threshold)
+		}
+	}
+}
+
+func TestMultisigAccountAddress(t *testing.T) {
+	// Addresses computed outside this package from multisig_account.move:
+	// create_resource_address(creator, b"aptos_framework::multisig_account" ||
+	// bcs(sequence_number)), i.e. SHA3-256(creator || seed || 0xFF)
+	tests := []struct {
+		creator        string
+		sequenceNumber uint64
+		want           string
+	}{
+		{"0xcafe", 0, "0x10f19a40542ad1b005c0e8ee81bf0e2c16d1c6f590b2837169bdbc2827dcc691"},
+		{"0xcafe", 7, "0xda967fc050c9333eca549d28e2b6e5a2bc70f8a98f5711f06c0f0f7226517535"},
+	}
+	for _, tt := range tests {
+		got := MultisigAccountAddress(MustParseAccountAddress(tt.creator), tt.sequenceNumber)
+		if got.String() != tt.want {
+			t.Errorf("MultisigAccountAddress(%s, %d) = %s, want %s", tt.creator, tt.sequenceNumber, got, tt.want)
+		}
+	}
+}
+
+func TestMultisigTransactionPayloadHash(t *testing.T) {
+	bob := MustParseAccountAddress("0xb0b")
+	entryFunction := &EntryFunction{
+		Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+		Function: "transfer",
+		Args:     EntryFunctionArgs(AddressArg(bob), U64Arg(100)),
+	}
+
+	// MultisigTransactionPayload::EntryFunction, then the entry function:
+	// module address and name, function name, type args, and arguments
+	var want bytes.Buffer
+	want.WriteByte(0)
+	want.Write(AccountOne[:])
+	want.WriteString("\x0daptos_account\x08transfer")
+	want.WriteByte(0)
+	want.WriteByte(2)
+	want.WriteByte(32)
+	want.Write(bob[:])
+	want.Write([]byte{8, 100, 0, 0, 0, 0, 0, 0, 0})
+
+	payloadBytes, err := MultisigTransactionPayloadBytes(entryFunction)
+	if err != nil {
+		t.Fatalf("MultisigTransactionPayloadBytes error: %v", err)
+	}
+	if !bytes.Equal(payloadBytes, want.Bytes()) {
+		t.Errorf("MultisigTransactionPayloadBytes = %x, want %x", payloadBytes, want.Bytes())
+	}
+	hash, err := MultisigTransactionPayloadHash(entryFunction)
+	if err != nil {
+		t.Fatalf("MultisigTransactionPayloadHash error: %v", err)
+	}
+	if wantHash := crypto.Sha3256(want.Bytes()); !bytes.Equal(hash, wantHash[:]) {
+		t.Errorf("MultisigTransactionPayloadHash = %x, want %x", hash, wantHash)
+	}
+
+	// At execution the chain hashes the payload carried by the multisig
+	// payload, which follows the address and the Some tag
+	multisig := MustParseAccountAddress("0x5a1e")
+	execute := ExecuteMultisigTransactionPayload(multisig, entryFunction)
+	executeBytes, err := bcs.Serialize(execute.Payload.(*MultisigPayload))
+	if err != nil {
+		t.Fatalf("Serialize error: %v", err)
+	}
+	if carried := executeBytes[AccountAddressLength+1:]; !bytes.Equal(crypto.Sha3256Hash(carried), hash) {
+		t.Error("hash does not match the payload submitted at execution")
+	}
+
+	tests := []struct {
+		name     string
+		payload  TransactionPayload
+		function string
+		types    []string
+		want     []interface{}
+	}{
+		{"create", mustPayload(t)(CreateMultisigTransactionPayload(multisig, entryFunction)), "create_transaction",
+			[]string{"address", "vector<u8>"}, []interface{}{multisig, payloadBytes}},
+		{"create with hash", mustPayload(t)(CreateMultisigTransactionWithHashPayload(multisig, entryFunction)), "create_transaction_with_hash",
+			[]string{"address", "vector<u8>"}, []interface{}{multisig, hash}},
+		{"approve", ApproveMultisigTransactionPayload(multisig, 7), "approve_transaction",
+			[]string{"address", "u64"}, []interface{}{multisig, uint64(7)}},
+		{"reject", RejectMultisigTransactionPayload(multisig, 7), "reject_transaction",
+			[]string{"address", "u64"}, []interface{}{multisig, uint64(7)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ef := tt.payload.Payload.(*EntryFunction)
+			if ef.Module.String() != "0x1::multisig_account" || ef.Function != tt.function {
+				t.Errorf("function = %s::%s, want 0x1::multisig_account::%s", ef.Module, ef.Function, tt.function)
+			}
+			values, err := DecodeEntryFunctionArgs(ef.Args, mustParseTypeTags(t, tt.types...))
+			if err != nil {
+				t.Fatalf("DecodeEntryFunctionArgs error: %v", err)
+			}
+			for i, value := range values {
+				if b, ok := value.([]byte); ok {
+					if !bytes.Equal(b, tt.want[i].([]byte)) {
+						t.Errorf("arg %d = %x, want %x", i, b, tt.want[i])
+					}
+				} else if value != tt.want[i] {
+					t.Errorf("arg %d = %v, want %v", i, value, tt.want[i])
+				}
+			}
+		})
+	}
+
+	stored := ExecuteMultisigTransactionPayload(multisig, nil)
+	if m := stored.Payload.(*MultisigPayload); m.MultisigAddress != multisig || m.TransactionPayload != nil {
+		t.Errorf("ExecuteMultisigTransactionPayload(nil) = %+v, want no inline payload", m)
+	}
+}
+
+// mustPayload returns a function that fails the test if a payload builder
+// returned an error.
+func mustPayload(t *testing.T) func(TransactionPayload, error) TransactionPayload {
+	return func(payload TransactionPayload, err error) TransactionPayload {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("payload error: %v", err)
+		}
+		return payload
+	}
+}
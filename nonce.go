@@ -0,0 +1,82 @@
+package aptos
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// nonceReuseWindow is how long a nonce is remembered: the longest an
+// orderless transaction can stay valid.
+const nonceReuseWindow = time.Duration(OrderlessMaxExpirationSeconds) * time.Second
+
+// RandomNonce returns a random replay protection nonce for an orderless
+// transaction (see WithReplayProtectionNonce), read from crypto/rand.
+func RandomNonce() uint64 {
+	var buf [8]byte
+	rand.Read(buf[:]) // Never fails; crashes the program if the OS source does
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// NonceSource hands out replay protection nonces for one account's orderless
+// transactions. Each nonce is remembered until transactions using it have
+// expired (OrderlessMaxExpirationSeconds), so the source never returns a nonce
+// the node could still reject as a replay. Tracking is in-process only, so use
+// a single NonceSource per sending account.
+//
+// A NonceSource is safe for concurrent use.
+type NonceSource struct {
+	mu   sync.Mutex
+	used map[uint64]time.Time // Nonce to the time it may be reused
+	now  func() time.Time
+}
+
+// NewNonceSource creates an empty nonce source.
+func NewNonceSource() *NonceSource {
+	return &NonceSource{
+		used: make(map[uint64]time.Time),
+		now:  time.Now,
+	}
+}
+
+// Next returns a random nonce that has not been used within the expiration
+// window, and records it as used.
+func (s *NonceSource) Next() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.prune()
+	for {
+		nonce := RandomNonce()
+		if _, ok := s.used[nonce]; !ok {
+			s.used[nonce] = now.Add(nonceReuseWindow)
+			return nonce
+		}
+	}
+}
+
+// Reserve records a caller-chosen nonce as used, returning ErrNonceReused if
+// it was already used within the expiration window.
+func (s *NonceSource) Reserve(nonce uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.prune()
+	if _, ok := s.used[nonce]; ok {
+		return fmt.Errorf("%w: %d", ErrNonceReused, nonce)
+	}
+	s.used[nonce] = now.Add(nonceReuseWindow)
+	return nil
+}
+
+// prune forgets nonces whose transactions have expired and returns the
+// current time. s.mu must be held.
+func (s *NonceSource) prune() time.Time {
+	now := s.now()
+	for nonce, reusableAt := range s.used {
+		if !now.Before(reusableAt) {
+			delete(s.used, nonce)
+		}
+	}
+	return now
+}
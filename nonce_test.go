@@ -0,0 +1,38 @@
+package aptos
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNonceSource(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	source := NewNonceSource()
+	source.now = func() time.Time { return now }
+
+	if err := source.Reserve(42); err != nil {
+		t.Fatalf("Reserve error: %v", err)
+	}
+	if err := source.Reserve(42); !errors.Is(err, ErrNonceReused) {
+		t.Errorf("Reserve reused nonce error = %v, want ErrNonceReused", err)
+	}
+
+	seen := map[uint64]bool{42: true}
+	for i := 0; i < 100; i++ {
+		nonce := source.Next()
+		if seen[nonce] {
+			t.Fatalf("Next returned reused nonce %d", nonce)
+		}
+		seen[nonce] = true
+	}
+
+	// Once transactions using the nonce have expired, it may be reused
+	now = now.Add(nonceReuseWindow)
+	if err := source.Reserve(42); err != nil {
+		t.Errorf("Reserve after expiration error: %v", err)
+	}
+	if len(source.used) != 1 {
+		t.Errorf("tracked %d nonces after expiration, want 1", len(source.used))
+	}
+}
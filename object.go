@@ -0,0 +1,137 @@
+package aptos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/crypto"
+)
+
+// Object is the address of an object, for use where a Move function takes an
+// Object<T>. It is encoded exactly like an address, but keeps object
+// addresses apart from account addresses in Go signatures, e.g. in structs
+// passed to ArgsFromStruct.
+type Object AccountAddress
+
+// Address returns the object's address.
+func (o Object) Address() AccountAddress {
+	return AccountAddress(o)
+}
+
+// String returns the object's address as a 0x-prefixed hex string.
+func (o Object) String() string {
+	return AccountAddress(o).String()
+}
+
+// MarshalBCS implements bcs.Marshaler.
+func (o Object) MarshalBCS(ser *bcs.Serializer) {
+	AccountAddress(o).MarshalBCS(ser)
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (o *Object) UnmarshalBCS(des *bcs.Deserializer) {
+	(*AccountAddress)(o).UnmarshalBCS(des)
+}
+
+// Domain separators appended when deriving object addresses.
+const (
+	objectDerivedScheme   = 0xFC // create_user_derived_object_address
+	objectFromSeedScheme  = 0xFE // create_object_address
+	resourceAddressScheme = 0xFF // create_resource_address
+)
+
+// CreateObjectAddress derives the address of a named object created by
+// creator with the given seed, matching 0x1::object::create_object_address.
+// This is SHA3-256(creator || seed || 0xFE).
+func CreateObjectAddress(creator AccountAddress, seed []byte) AccountAddress {
+	buf := make([]byte, 0, AccountAddressLength+len(seed)+1)
+	buf = append(buf, creator[:]...)
+	buf = append(buf, seed...)
+	buf = append(buf, objectFromSeedScheme)
+	return AccountAddress(crypto.Sha3256(buf))
+}
+
+// CreateUserDerivedObjectAddress derives an object address from a source
+// address and another address, matching
+// 0x1::object::create_user_derived_object_address.
+// This is SHA3-256(source || deriveFrom || 0xFC).
+func CreateUserDerivedObjectAddress(source, deriveFrom AccountAddress) AccountAddress {
+	var buf [2*AccountAddressLength + 1]byte
+	copy(buf[:], source[:])
+	copy(buf[AccountAddressLength:], deriveFrom[:])
+	buf[2*AccountAddressLength] = objectDerivedScheme
+	return AccountAddress(crypto.Sha3256(buf[:]))
+}
+
+// PrimaryFungibleStoreAddress derives the address of the primary fungible
+// store of owner for the fungible asset with the given metadata object,
+// matching 0x1::primary_fungible_store::primary_store_address.
+func PrimaryFungibleStoreAddress(owner, metadata AccountAddress) AccountAddress {
+	return CreateUserDerivedObjectAddress(owner, metadata)
+}
+
+// CreateResourceAddress derives the address of a resource account created by
+// source with the given seed, matching 0x1::account::create_resource_address.
+// This is SHA3-256(source || seed || 0xFF).
+func CreateResourceAddress(source AccountAddress, seed []byte) AccountAddress {
+	buf := make([]byte, 0, AccountAddressLength+len(seed)+1)
+	buf = append(buf, source[:]...)
+	buf = append(buf, seed...)
+	buf = append(buf, resourceAddressScheme)
+	return AccountAddress(crypto.Sha3256(buf))
+}
+
+// ObjectCoreResource is the data of the 0x1::object::ObjectCore resource,
+// which every object stores at its address.
+type ObjectCoreResource struct {
+	// Owner is the account or object that owns the object. Burnt objects are
+	// owned by 0xffff...ffff.
+	Owner AccountAddress `json:"owner"`
+
+	// AllowUngatedTransfer reports whether the owner can transfer the object
+	// with 0x1::object::transfer. When false, only a holder of the object's
+	// TransferRef can move it.
+	AllowUngatedTransfer bool `json:"allow_ungated_transfer"`
+
+	// GUIDCreationNum is the creation number of the next GUID created for
+	// the object.
+	GUIDCreationNum JSONUint64 `json:"guid_creation_num"`
+
+	// TransferEvents is the handle of the object's legacy TransferEvent
+	// stream.
+	TransferEvents EventHandle `json:"transfer_events"`
+}
+
+// GetObjectCore retrieves the ObjectCore resource of the object at
+// objectAddress, which holds its owner and whether it can be transferred.
+func (c *Client) GetObjectCore(ctx context.Context, objectAddress AccountAddress, opts ...RequestOption) (ObjectCoreResource, error) {
+	resource, err := c.GetAccountResource(ctx, objectAddress, "0x1::object::ObjectCore", opts...)
+	if err != nil {
+		return ObjectCoreResource{}, err
+	}
+	var core ObjectCoreResource
+	if err := resource.Data.DecodeData(&core); err != nil {
+		return ObjectCoreResource{}, fmt.Errorf("decode object core: %w", err)
+	}
+	return core, nil
+}
+
+// TransferObjectPayload returns a payload that transfers the object at object
+// to recipient with 0x1::object::transfer<0x1::object::ObjectCore>. The
+// function is generic over the object's resource type, and ObjectCore is
+// present in every object, so it works for tokens and any other object whose
+// owner is allowed ungated transfers.
+func TransferObjectPayload(object, recipient AccountAddress) TransactionPayload {
+	return TransactionPayload{
+		Payload: &EntryFunction{
+			Module:   ModuleId{Address: AccountOne, Name: "object"},
+			Function: "transfer",
+			TypeArgs: []TypeTag{{Value: &StructTag{Address: AccountOne, Module: "object", Name: "ObjectCore"}}},
+			Args: EntryFunctionArgs(
+				ObjectArg(object),
+				AddressArg(recipient),
+			),
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package aptos
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/0xbe1/aptopher/bcs"
+)
+
+func TestPrimaryFungibleStoreAddress(t *testing.T) {
+	tests := []struct {
+		owner    string
+		metadata string
+		want     string
+	}{
+		{"0x1", "0xa", "0xc6d3d69a9810647845a5ca5ebe905256dc37327c1c39c1d673de00caaac0e3a8"},
+		{"0xb0b", "0xa", "0xd448ff899c14915207a07424af4c846543b471b4cd83cc9cb54b6b79b89639f1"},
+		{"0xcafe", "0x1234", "0x6f9d39b56c639cda43f6e0b4e61cd2fa19ee45970579cb72f2969a984dd95f09"},
+	}
+	for _, tt := range tests {
+		got := PrimaryFungibleStoreAddress(MustParseAccountAddress(tt.owner), MustParseAccountAddress(tt.metadata))
+		if got.String() != tt.want {
+			t.Errorf("PrimaryFungibleStoreAddress(%s, %s) = %s, want %s", tt.owner, tt.metadata, got, tt.want)
+		}
+	}
+}
+
+func TestCreateObjectAddress(t *testing.T) {
+	got := CreateObjectAddress(AccountOne, []byte("collection"))
+	want := "0x576f5756b1b00af4e460d6c652061638e4176e9f2986893985aad3a87d938a8f"
+	if got.String() != want {
+		t.Errorf("CreateObjectAddress(0x1, collection) = %s, want %s", got, want)
+	}
+}
+
+func TestObjectArgs(t *testing.T) {
+	objs := []AccountAddress{MustParseAccountAddress("0xa"), MustParseAccountAddress("0xb")}
+	if got, want := VectorObjectArg(objs), VectorAddressArg(objs); !bytes.Equal(got, want) {
+		t.Errorf("VectorObjectArg = %x, want %x", got, want)
+	}
+
+	// Object fields encode like addresses in ArgsFromStruct
+	args, err := ArgsFromStruct(struct {
+		Token  Object
+		Tokens []Object
+	}{Object(objs[0]), []Object{Object(objs[0]), Object(objs[1])}})
+	if err != nil {
+		t.Fatalf("ArgsFromStruct error: %v", err)
+	}
+	if !bytes.Equal(args[0], ObjectArg(objs[0])) {
+		t.Errorf("Object field = %x, want %x", args[0], ObjectArg(objs[0]))
+	}
+	if !bytes.Equal(args[1], VectorObjectArg(objs)) {
+		t.Errorf("[]Object field = %x, want %x", args[1], VectorObjectArg(objs))
+	}
+
+	var decoded Object
+	if err := bcs.Deserialize(args[0], &decoded); err != nil {
+		t.Fatalf("Deserialize error: %v", err)
+	}
+	if decoded.Address() != objs[0] {
+		t.Errorf("decoded object = %s, want %s", decoded, objs[0])
+	}
+}
+
+func TestTransferObjectPayload(t *testing.T) {
+	object := MustParseAccountAddress("0xabc")
+	to := MustParseAccountAddress("0xcafe")
+	payload := TransferObjectPayload(object, to)
+	entryFunction, ok := payload.Payload.(*EntryFunction)
+	if !ok {
+		t.Fatalf("payload is %T, want *EntryFunction", payload.Payload)
+	}
+	if entryFunction.Module.Address != AccountOne || entryFunction.Module.Name != "object" || entryFunction.Function != "transfer" {
+		t.Errorf("unexpected function %s::%s", entryFunction.Module.Name, entryFunction.Function)
+	}
+	if len(entryFunction.TypeArgs) != 1 || entryFunction.TypeArgs[0].String() != "0x1::object::ObjectCore" {
+		t.Errorf("TypeArgs = %v, want [0x1::object::ObjectCore]", entryFunction.TypeArgs)
+	}
+	wantArgs := [][]byte{object[:], to[:]}
+	if len(entryFunction.Args) != len(wantArgs) {
+		t.Fatalf("got %d args, want %d", len(entryFunction.Args), len(wantArgs))
+	}
+	for i := range wantArgs {
+		if !bytes.Equal(entryFunction.Args[i], wantArgs[i]) {
+			t.Errorf("Args[%d] = %x, want %x", i, entryFunction.Args[i], wantArgs[i])
+		}
+	}
+
+	// The payload round-trips through BCS.
+	data, err := bcs.Serialize(payload)
+	if err != nil {
+		t.Fatalf("Serialize error: %v", err)
+	}
+	var decoded TransactionPayload
+	if err := bcs.Deserialize(data, &decoded); err != nil {
+		t.Fatalf("Deserialize error: %v", err)
+	}
+	if got := decoded.Payload.(*EntryFunction).TypeArgs[0].String(); got != "0x1::object::ObjectCore" {
+		t.Errorf("decoded type argument = %s", got)
+	}
+}
+
+func TestGetObjectCore(t *testing.T) {
+	client, closeServer := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/accounts/0x0000000000000000000000000000000000000000000000000000000000000abc/resource/0x1::object::ObjectCore" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"type":"0x1::object::ObjectCore","data":{
+			"allow_ungated_transfer":true,
+			"guid_creation_num":"1125899906842625",
+			"owner":"0xb0b",
+			"transfer_events":{"counter":"2","guid":{"id":{"addr":"0xabc","creation_num":"1125899906842624"}}}
+		}}`))
+	})
+	defer closeServer()
+
+	core, err := client.GetObjectCore(context.Background(), MustParseAccountAddress("0xabc"))
+	if err != nil {
+		t.Fatalf("GetObjectCore error: %v", err)
+	}
+	if core.Owner != MustParseAccountAddress("0xb0b") || !core.AllowUngatedTransfer || core.GUIDCreationNum.Uint64() != 1125899906842625 {
+		t.Errorf("unexpected object core: %+v", core)
+	}
+	id := core.TransferEvents.GUID.ID
+	if core.TransferEvents.Counter.Uint64() != 2 || id.Address != MustParseAccountAddress("0xabc") || id.CreationNumber.Uint64() != 1125899906842624 {
+		t.Errorf("unexpected transfer events: %+v", core.TransferEvents)
+	}
+}
@@ -0,0 +1,89 @@
+package aptos
+
+import (
+	"context"
+	"iter"
+	"strconv"
+)
+
+// Transactions returns an iterator over committed transactions in version
+// order, from the version set with WithStart, or genesis if none, up to the
+// ledger version when the last page was read. Pages are fetched as the
+// iteration proceeds, WithLimit transactions at a time (the node's default
+// page size if unset). Non-archive nodes prune old transactions, so start
+// from a recent version on those.
+//
+// Iteration stops at the first error, which is yielded with a zero
+// Transaction:
+//
+//	for txn, err := range client.Transactions(ctx, aptos.WithStart(version)) {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+func (c *Client) Transactions(ctx context.Context, opts ...RequestOption) iter.Seq2[Transaction, error] {
+	return paginateTransactions(ctx, opts, c.GetTransactions, func(txn *Transaction, metadata ResponseMetadata) (uint64, bool) {
+		next := txn.VersionUint64() + 1
+		return next, metadata.LedgerVersion == 0 || next <= metadata.LedgerVersion
+	})
+}
+
+// AccountTransactions returns an iterator over the transactions sent by
+// address, in sequence number order, from the sequence number set with
+// WithStart, or the first if none. Pages are fetched as the iteration
+// proceeds, WithLimit transactions at a time (the node's default page size if
+// unset), until a page comes back empty. Errors are yielded as by
+// Transactions.
+func (c *Client) AccountTransactions(ctx context.Context, address AccountAddress, opts ...RequestOption) iter.Seq2[Transaction, error] {
+	fetch := func(ctx context.Context, opts ...RequestOption) (Response[[]Transaction], error) {
+		return c.GetAccountTransactions(ctx, address, opts...)
+	}
+	return paginateTransactions(ctx, opts, fetch, func(txn *Transaction, _ ResponseMetadata) (uint64, bool) {
+		return txn.SequenceNumber.Uint64() + 1, true
+	})
+}
+
+// paginateTransactions iterates over the pages returned by fetch. After each
+// page, the next start is the page's cursor, if the node returned one, or
+// else the position after the page's last transaction, as computed by next,
+// which also reports whether more transactions may follow.
+func paginateTransactions(ctx context.Context, opts []RequestOption,
+	fetch func(context.Context, ...RequestOption) (Response[[]Transaction], error),
+	next func(*Transaction, ResponseMetadata) (uint64, bool),
+) iter.Seq2[Transaction, error] {
+	return func(yield func(Transaction, error) bool) {
+		var start uint64
+		if options := ApplyOptions(opts...); options.Start != nil {
+			start = *options.Start
+		}
+		pageOpts := append(opts[:len(opts):len(opts)], nil)
+
+		for {
+			pageOpts[len(pageOpts)-1] = WithStart(start)
+			page, err := fetch(ctx, pageOpts...)
+			if err != nil {
+				yield(Transaction{}, err)
+				return
+			}
+			if len(page.Data) == 0 {
+				return
+			}
+			for _, txn := range page.Data {
+				if !yield(txn, nil) {
+					return
+				}
+			}
+
+			more := true
+			if cursor, err := strconv.ParseUint(page.Metadata.Cursor, 10, 64); err == nil {
+				start = cursor
+			} else {
+				start, more = next(&page.Data[len(page.Data)-1], page.Metadata)
+			}
+			if !more {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package aptos
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter holds requests back while the node's rate-limit quota is
+// exhausted, based on the rate-limit headers of previous responses.
+// A nil *rateLimiter never delays.
+type rateLimiter struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// observe records the rate-limit state of a response. Once no requests remain
+// in the window, requests are delayed until the window resets.
+func (l *rateLimiter) observe(metadata ResponseMetadata) {
+	if l == nil || metadata.RateLimit == 0 || metadata.RateLimitRemaining > 0 || metadata.RateLimitReset.IsZero() {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if metadata.RateLimitReset.After(l.until) {
+		l.until = metadata.RateLimitReset
+	}
+}
+
+// wait blocks until the current rate-limit window resets, or returns the
+// context's error if it is done first.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	delay := time.Until(l.until)
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
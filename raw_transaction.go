@@ -1,6 +1,10 @@
 package aptos
 
 import (
+	"bytes"
+	"fmt"
+	"time"
+
 	"github.com/0xbe1/aptopher/bcs"
 	"github.com/0xbe1/aptopher/crypto"
 )
@@ -16,6 +20,111 @@ type RawTransaction struct {
 	ChainID                 uint8
 }
 
+// NewRawTransaction builds a raw transaction without any network access, for
+// offline (air-gapped) signing. Unlike Client.BuildTransaction, nothing is
+// fetched from a node, so the caller must supply the chain ID and the options
+// that would otherwise be fetched:
+//   - WithSequenceNumber or WithReplayProtectionNonce (required)
+//   - WithGasUnitPrice (required)
+//   - WithMaxGasAmount (defaults to DefaultMaxGasAmount)
+//   - WithExpirationTimestampSecs or WithExpirationDuration (defaults to
+//     DefaultExpirationSeconds from now, or OrderlessMaxExpirationSeconds for
+//     orderless transactions)
+//
+// The signed transaction can then be serialized with SignedTransaction.Bytes
+// and transported to an online machine for Client.SubmitTransaction.
+func NewRawTransaction(sender AccountAddress, payload TransactionPayload, chainID uint8, opts ...BuildOption) (*RawTransaction, error) {
+	options := ApplyBuildOptions(opts...)
+
+	switch {
+	case options.SequenceNumber != nil && options.ReplayProtectionNonce != nil:
+		return nil, fmt.Errorf("cannot specify both SequenceNumber and ReplayProtectionNonce")
+	case options.SequenceNumber == nil && options.ReplayProtectionNonce == nil:
+		return nil, fmt.Errorf("offline transactions require SequenceNumber or ReplayProtectionNonce")
+	case options.GasUnitPrice == nil:
+		return nil, fmt.Errorf("offline transactions require GasUnitPrice")
+	case chainID == 0:
+		return nil, fmt.Errorf("offline transactions require a chain ID")
+	}
+	if err := validateExpirationOptions(options); err != nil {
+		return nil, err
+	}
+
+	sequenceNumber := OrderlessPlaceholderSequenceNum
+	if options.SequenceNumber != nil {
+		sequenceNumber = *options.SequenceNumber
+	}
+	return assembleRawTransaction(sender, payload, sequenceNumber, *options.GasUnitPrice, chainID, options), nil
+}
+
+// validateExpirationOptions checks the expiration build options for conflicts
+// and, for orderless transactions, against OrderlessMaxExpirationSeconds.
+func validateExpirationOptions(options BuildOptions) error {
+	if options.ExpirationDuration == nil {
+		return nil
+	}
+	d := *options.ExpirationDuration
+	switch {
+	case options.ExpirationTimestampSecs != nil:
+		return fmt.Errorf("cannot specify both ExpirationTimestampSecs and ExpirationDuration")
+	case d <= 0:
+		return fmt.Errorf("expiration duration must be positive, got %s", d)
+	case options.ReplayProtectionNonce != nil && d > time.Duration(OrderlessMaxExpirationSeconds)*time.Second:
+		return fmt.Errorf("orderless transactions expire within %ds, got %s", OrderlessMaxExpirationSeconds, d)
+	}
+	return nil
+}
+
+// assembleRawTransaction builds a raw transaction from resolved network values,
+// applying defaults for the max gas amount and expiration.
+func assembleRawTransaction(
+	sender AccountAddress,
+	payload TransactionPayload,
+	sequenceNumber uint64,
+	gasUnitPrice uint64,
+	chainID uint8,
+	options BuildOptions,
+) *RawTransaction {
+	isOrderless := options.ReplayProtectionNonce != nil
+
+	// Get max gas amount
+	maxGasAmount := DefaultMaxGasAmount
+	if options.MaxGasAmount != nil {
+		maxGasAmount = *options.MaxGasAmount
+	}
+
+	// Get expiration timestamp
+	var expirationTimestampSecs uint64
+	if options.ExpirationTimestampSecs != nil {
+		expirationTimestampSecs = *options.ExpirationTimestampSecs
+	} else if options.ExpirationDuration != nil {
+		// Round up to whole seconds so the transaction is valid for at least d
+		secs := (*options.ExpirationDuration + time.Second - 1) / time.Second
+		expirationTimestampSecs = uint64(time.Now().Unix()) + uint64(secs)
+	} else if isOrderless {
+		// Orderless transactions have a max expiration of 60 seconds
+		expirationTimestampSecs = uint64(time.Now().Unix()) + OrderlessMaxExpirationSeconds
+	} else {
+		expirationTimestampSecs = uint64(time.Now().Unix()) + DefaultExpirationSeconds
+	}
+
+	// For orderless transactions, wrap the payload in TransactionInnerPayloadV1
+	finalPayload := payload
+	if isOrderless {
+		finalPayload = wrapPayloadForOrderless(payload, options.ReplayProtectionNonce)
+	}
+
+	return &RawTransaction{
+		Sender:                  sender,
+		SequenceNumber:          sequenceNumber,
+		Payload:                 finalPayload,
+		MaxGasAmount:            maxGasAmount,
+		GasUnitPrice:            gasUnitPrice,
+		ExpirationTimestampSecs: expirationTimestampSecs,
+		ChainID:                 chainID,
+	}
+}
+
 // MarshalBCS implements bcs.Marshaler.
 func (t RawTransaction) MarshalBCS(ser *bcs.Serializer) {
 	t.Sender.MarshalBCS(ser)
@@ -27,10 +136,27 @@ func (t RawTransaction) MarshalBCS(ser *bcs.Serializer) {
 	ser.U8(t.ChainID)
 }
 
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (t *RawTransaction) UnmarshalBCS(des *bcs.Deserializer) {
+	t.Sender.UnmarshalBCS(des)
+	t.SequenceNumber = des.U64()
+	t.Payload.UnmarshalBCS(des)
+	t.MaxGasAmount = des.U64()
+	t.GasUnitPrice = des.U64()
+	t.ExpirationTimestampSecs = des.U64()
+	t.ChainID = des.U8()
+}
+
+// Bytes returns the BCS-encoded raw transaction.
+// Unlike SigningMessage, this is neither prefixed nor hashed.
+func (t *RawTransaction) Bytes() ([]byte, error) {
+	return bcs.Serialize(t)
+}
+
 // SigningMessage returns the message to be signed for this transaction.
 // This is SHA3-256(prefix || bcs(RawTransaction))
 func (t *RawTransaction) SigningMessage() ([]byte, error) {
-	txnBytes, err := bcs.Serialize(t)
+	txnBytes, err := t.Bytes()
 	if err != nil {
 		return nil, err
 	}
@@ -49,24 +175,140 @@ func (t *RawTransaction) Sign(signer crypto.Signer) (*SignedTransaction, error)
 		return nil, err
 	}
 
+	return &SignedTransaction{
+		RawTxn:        t,
+		Authenticator: newTransactionAuthenticator(signer.PublicKey(), signer.Scheme(), signature),
+	}, nil
+}
+
+// SignMultiEd25519 signs the transaction for an account controlled by a
+// MultiEd25519 key, with signers keyed by the index of their key in
+// publicKey. At least the threshold of signers is required.
+func (t *RawTransaction) SignMultiEd25519(publicKey *crypto.MultiEd25519PublicKey, signers map[int]crypto.Signer) (*SignedTransaction, error) {
+	if len(signers) < int(publicKey.Threshold) {
+		return nil, fmt.Errorf("%d signers, below the threshold of %d", len(signers), publicKey.Threshold)
+	}
+	signingMessage, err := t.SigningMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	signatures := make(map[int][]byte, len(signers))
+	for i, signer := range signers {
+		if i < 0 || i >= len(publicKey.PublicKeys) {
+			return nil, fmt.Errorf("signer index %d out of range for %d keys", i, len(publicKey.PublicKeys))
+		}
+		if !bytes.Equal(signer.PublicKey(), publicKey.PublicKeys[i]) {
+			return nil, fmt.Errorf("signer %d does not match public key %d", i, i)
+		}
+		if signatures[i], err = signer.Sign(signingMessage); err != nil {
+			return nil, err
+		}
+	}
+	signature, err := crypto.NewMultiEd25519Signature(signatures)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedTransaction{
+		RawTxn: t,
+		Authenticator: TransactionAuthenticator{
+			Variant: TransactionAuthenticatorMultiEd25519,
+			Auth:    &AccountAuthenticatorMultiEd25519{PublicKey: *publicKey, Signature: *signature},
+		},
+	}, nil
+}
+
+// SignMultiKey signs the transaction for an account controlled by a
+// multi-key (AIP-55), with signers keyed by the index of their key in
+// publicKey. At least the threshold of signers is required.
+func (t *RawTransaction) SignMultiKey(publicKey *MultiKey, signers map[int]crypto.Signer) (*SignedTransaction, error) {
+	if len(signers) < int(publicKey.SignaturesRequired) {
+		return nil, fmt.Errorf("%d signers, below the threshold of %d", len(signers), publicKey.SignaturesRequired)
+	}
+	signingMessage, err := t.SigningMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	signatures := make(map[int]AnySignature, len(signers))
+	for i, signer := range signers {
+		if i < 0 || i >= len(publicKey.PublicKeys) {
+			return nil, fmt.Errorf("signer index %d out of range for %d keys", i, len(publicKey.PublicKeys))
+		}
+		key := publicKey.PublicKeys[i]
+		signerKey := signer.PublicKey()
+		if signer.Scheme() == crypto.Secp256k1Scheme {
+			if uncompressed, err := crypto.UncompressSecp256k1PubKey(signerKey); err == nil {
+				signerKey = uncompressed
+			}
+		}
+		if signer.Scheme() != key.Variant || !bytes.Equal(signerKey, key.PublicKey) {
+			return nil, fmt.Errorf("signer %d does not match public key %d", i, i)
+		}
+		signature, err := signer.Sign(signingMessage)
+		if err != nil {
+			return nil, err
+		}
+		signatures[i] = AnySignature{Variant: key.Variant, Signature: signature}
+	}
+	signature, err := NewMultiKeySignature(signatures)
+	if err != nil {
+		return nil, err
+	}
+
 	return &SignedTransaction{
 		RawTxn: t,
 		Authenticator: TransactionAuthenticator{
 			Variant: TransactionAuthenticatorSingleSender,
-			Auth: &AccountAuthenticatorSingleKey{
-				PublicKey: AnyPublicKey{
-					Variant:   signer.Scheme(),
-					PublicKey: signer.PublicKey(),
-				},
-				Signature: AnySignature{
-					Variant:   signer.Scheme(),
-					Signature: signature,
-				},
-			},
+			Auth:    &AccountAuthenticatorMultiKey{PublicKey: *publicKey, Signature: *signature},
 		},
 	}, nil
 }
 
+// newTransactionAuthenticator returns the authenticator for a single signer
+// that matches how Account derives its address from the key: Ed25519 keys use
+// the legacy Ed25519 authenticator, and other keys the single-key
+// authenticator. Secp256k1 keys are sent uncompressed, as the node expects.
+func newTransactionAuthenticator(publicKey []byte, scheme crypto.SignatureScheme, signature []byte) TransactionAuthenticator {
+	if scheme == crypto.Ed25519Scheme && len(publicKey) == crypto.Ed25519PublicKeyLength && len(signature) == crypto.Ed25519SignatureLength {
+		auth := &AccountAuthenticatorEd25519{}
+		copy(auth.PublicKey[:], publicKey)
+		copy(auth.Signature[:], signature)
+		return TransactionAuthenticator{Variant: TransactionAuthenticatorEd25519, Auth: auth}
+	}
+	if scheme == crypto.Secp256k1Scheme {
+		if uncompressed, err := crypto.UncompressSecp256k1PubKey(publicKey); err == nil {
+			publicKey = uncompressed
+		}
+	}
+	return TransactionAuthenticator{
+		Variant: TransactionAuthenticatorSingleSender,
+		Auth: &AccountAuthenticatorSingleKey{
+			PublicKey: AnyPublicKey{
+				Variant:   scheme,
+				PublicKey: publicKey,
+			},
+			Signature: AnySignature{
+				Variant:   scheme,
+				Signature: signature,
+			},
+		},
+	}
+}
+
+// SimulationTransaction returns the transaction with an all-zero signature
+// for the given public key, for use with Client.SimulateTransaction.
+// Only the public key is needed, so transactions can be simulated for
+// accounts whose private key is not available. The node rejects simulations
+// that carry a valid signature.
+func (t *RawTransaction) SimulationTransaction(publicKey []byte, scheme crypto.SignatureScheme) *SignedTransaction {
+	return &SignedTransaction{
+		RawTxn:        t,
+		Authenticator: newTransactionAuthenticator(publicKey, scheme, make([]byte, 64)),
+	}
+}
+
 // RawTransactionWithData wraps a raw transaction with additional data for multi-agent/fee-payer transactions.
 type RawTransactionWithData struct {
 	Variant            RawTransactionWithDataVariant
@@ -113,3 +355,117 @@ func (t *RawTransactionWithData) SigningMessage() ([]byte, error) {
 	}
 	return crypto.HashWithPrefix(crypto.RawTransactionWithDataHashPrefix, txnBytes), nil
 }
+
+// NewMultiAgentTransaction wraps rawTxn for signing by its sender and the
+// secondary signers, which must be listed in the order the payload's signer
+// parameters after the sender's expect them.
+func NewMultiAgentTransaction(rawTxn *RawTransaction, secondarySigners []AccountAddress) *RawTransactionWithData {
+	return &RawTransactionWithData{
+		Variant:          MultiAgent,
+		RawTxn:           rawTxn,
+		SecondarySigners: secondarySigners,
+	}
+}
+
+// NewFeePayerTransaction wraps rawTxn for a sponsored transaction, whose gas
+// is paid by feePayer instead of the sender. The secondary signers, if any,
+// must be in the order the payload's signer parameters after the sender's
+// expect them. feePayer may be the zero address when the sponsor is not yet
+// known; FeePayerSign fills it in.
+func NewFeePayerTransaction(rawTxn *RawTransaction, secondarySigners []AccountAddress, feePayer AccountAddress) *RawTransactionWithData {
+	return &RawTransactionWithData{
+		Variant:          FeePayer,
+		RawTxn:           rawTxn,
+		SecondarySigners: secondarySigners,
+		FeePayerAddress:  feePayer,
+	}
+}
+
+// Sign signs the transaction as one of its signers and returns that signer's
+// account authenticator. The sender and every secondary signer sign the same
+// SigningMessage; collect their authenticators and assemble them with
+// MultiAgentTransaction, or, for a fee-payer transaction, have the fee payer
+// assemble them with FeePayerSign.
+func (t *RawTransactionWithData) Sign(signer crypto.Signer) (AccountAuthenticatorImpl, error) {
+	signingMessage, err := t.SigningMessage()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(signingMessage)
+	if err != nil {
+		return nil, err
+	}
+	return newTransactionAuthenticator(signer.PublicKey(), signer.Scheme(), signature).Auth, nil
+}
+
+// MultiAgentTransaction assembles the signed transaction from the sender's
+// authenticator and one authenticator per secondary signer, in the order of
+// SecondarySigners.
+func (t *RawTransactionWithData) MultiAgentTransaction(sender AccountAuthenticatorImpl, secondarySigners []AccountAuthenticatorImpl) (*SignedTransaction, error) {
+	if t.Variant != MultiAgent {
+		return nil, fmt.Errorf("transaction variant %d is not multi-agent", t.Variant)
+	}
+	authenticator := TransactionAuthenticator{
+		Variant: TransactionAuthenticatorMultiAgent,
+		Auth: &MultiAgentAuthenticator{
+			Sender:                   sender,
+			SecondarySignerAddresses: t.SecondarySigners,
+			SecondarySigners:         secondarySigners,
+		},
+	}
+	if err := authenticator.validate(); err != nil {
+		return nil, err
+	}
+	return &SignedTransaction{RawTxn: t.RawTxn, Authenticator: authenticator}, nil
+}
+
+// SignMultiAgent signs a multi-agent transaction with all of its signers at
+// once, for when every key is available locally. The secondary signers must
+// be in the order of SecondarySigners.
+func (t *RawTransactionWithData) SignMultiAgent(sender crypto.Signer, secondarySigners ...crypto.Signer) (*SignedTransaction, error) {
+	senderAuth, err := t.Sign(sender)
+	if err != nil {
+		return nil, err
+	}
+	secondaryAuths := make([]AccountAuthenticatorImpl, len(secondarySigners))
+	for i, signer := range secondarySigners {
+		if secondaryAuths[i], err = t.Sign(signer); err != nil {
+			return nil, fmt.Errorf("secondary signer %d: %w", i, err)
+		}
+	}
+	return t.MultiAgentTransaction(senderAuth, secondaryAuths)
+}
+
+// FeePayerSign is run by the sponsor of a fee-payer transaction: it sets
+// FeePayerAddress to feePayerAddress, signs as the fee payer, and assembles
+// the signed transaction from the sender's authenticator and one
+// authenticator per secondary signer, in the order of SecondarySigners.
+//
+// The sender and secondary signers may have signed before the fee payer was
+// known, with FeePayerAddress left as the zero address: the node accepts their
+// signatures over either the zero address or the fee payer's. The fee payer's
+// own signature always covers its address.
+func (t *RawTransactionWithData) FeePayerSign(feePayerAddress AccountAddress, feePayer crypto.Signer, sender AccountAuthenticatorImpl, secondarySigners []AccountAuthenticatorImpl) (*SignedTransaction, error) {
+	if t.Variant != FeePayer {
+		return nil, fmt.Errorf("transaction variant %d is not fee-payer", t.Variant)
+	}
+	t.FeePayerAddress = feePayerAddress
+	feePayerAuth, err := t.Sign(feePayer)
+	if err != nil {
+		return nil, fmt.Errorf("fee payer: %w", err)
+	}
+	authenticator := TransactionAuthenticator{
+		Variant: TransactionAuthenticatorFeePayer,
+		Auth: &FeePayerAuthenticator{
+			Sender:                   sender,
+			SecondarySignerAddresses: t.SecondarySigners,
+			SecondarySigners:         secondarySigners,
+			FeePayerAddress:          feePayerAddress,
+			FeePayer:                 feePayerAuth,
+		},
+	}
+	if err := authenticator.validate(); err != nil {
+		return nil, err
+	}
+	return &SignedTransaction{RawTxn: t.RawTxn, Authenticator: authenticator}, nil
+}
@@ -0,0 +1,122 @@
+package aptos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xbe1/aptopher/crypto"
+)
+
+func TestNewRawTransaction(t *testing.T) {
+	account, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+	payload := TransactionPayload{
+		Payload: &EntryFunction{
+			Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+			Function: "transfer",
+			Args:     EntryFunctionArgs(AddressArg(AccountOne), U64Arg(1)),
+		},
+	}
+
+	rawTxn, err := NewRawTransaction(account.Address, payload, 2,
+		WithSequenceNumber(5),
+		WithGasUnitPrice(150),
+		WithExpirationTimestampSecs(1700000000),
+	)
+	if err != nil {
+		t.Fatalf("NewRawTransaction error: %v", err)
+	}
+	if rawTxn.SequenceNumber != 5 || rawTxn.GasUnitPrice != 150 || rawTxn.ChainID != 2 {
+		t.Errorf("unexpected transaction fields: %+v", rawTxn)
+	}
+	if rawTxn.MaxGasAmount != DefaultMaxGasAmount {
+		t.Errorf("MaxGasAmount = %d, want %d", rawTxn.MaxGasAmount, DefaultMaxGasAmount)
+	}
+	if rawTxn.ExpirationTimestampSecs != 1700000000 {
+		t.Errorf("ExpirationTimestampSecs = %d, want 1700000000", rawTxn.ExpirationTimestampSecs)
+	}
+
+	// Sign offline and verify the signature
+	signedTxn, err := account.SignTransaction(rawTxn)
+	if err != nil {
+		t.Fatalf("SignTransaction error: %v", err)
+	}
+	message, err := rawTxn.SigningMessage()
+	if err != nil {
+		t.Fatalf("SigningMessage error: %v", err)
+	}
+	if signedTxn.Authenticator.Variant != TransactionAuthenticatorEd25519 {
+		t.Errorf("authenticator variant = %d, want %d", signedTxn.Authenticator.Variant, TransactionAuthenticatorEd25519)
+	}
+	auth := signedTxn.Authenticator.Auth.(*AccountAuthenticatorEd25519)
+	if !crypto.VerifyEd25519(auth.PublicKey[:], message, auth.Signature[:]) {
+		t.Error("signature verification failed")
+	}
+
+	// Orderless transactions use the placeholder sequence number
+	orderless, err := NewRawTransaction(account.Address, payload, 2,
+		WithReplayProtectionNonce(42),
+		WithGasUnitPrice(100),
+	)
+	if err != nil {
+		t.Fatalf("NewRawTransaction orderless error: %v", err)
+	}
+	if orderless.SequenceNumber != OrderlessPlaceholderSequenceNum {
+		t.Errorf("SequenceNumber = %d, want %d", orderless.SequenceNumber, OrderlessPlaceholderSequenceNum)
+	}
+	if _, ok := orderless.Payload.Payload.(*TransactionInnerPayloadV1); !ok {
+		t.Errorf("orderless payload = %T, want *TransactionInnerPayloadV1", orderless.Payload.Payload)
+	}
+}
+
+func TestNewRawTransactionMissingOptions(t *testing.T) {
+	payload := TransactionPayload{Payload: &EntryFunction{}}
+	tests := []struct {
+		name    string
+		chainID uint8
+		opts    []BuildOption
+	}{
+		{"no sequence number", 1, []BuildOption{WithGasUnitPrice(100)}},
+		{"no gas price", 1, []BuildOption{WithSequenceNumber(0)}},
+		{"no chain id", 0, []BuildOption{WithSequenceNumber(0), WithGasUnitPrice(100)}},
+		{"sequence number and nonce", 1, []BuildOption{WithSequenceNumber(0), WithReplayProtectionNonce(1), WithGasUnitPrice(100)}},
+		{"expiration timestamp and duration", 1, []BuildOption{WithSequenceNumber(0), WithGasUnitPrice(100), WithExpirationTimestampSecs(1), WithExpirationDuration(time.Minute)}},
+		{"non-positive expiration duration", 1, []BuildOption{WithSequenceNumber(0), WithGasUnitPrice(100), WithExpirationDuration(0)}},
+		{"orderless expiration duration over limit", 1, []BuildOption{WithReplayProtectionNonce(1), WithGasUnitPrice(100), WithExpirationDuration(2 * time.Minute)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRawTransaction(AccountOne, payload, tt.chainID, tt.opts...); err == nil {
+				t.Error("NewRawTransaction should have failed")
+			}
+		})
+	}
+}
+
+func TestNewRawTransactionExpirationDuration(t *testing.T) {
+	payload := TransactionPayload{Payload: &EntryFunction{}}
+	tests := []struct {
+		name     string
+		opts     []BuildOption
+		wantSecs uint64
+	}{
+		{"two minutes", []BuildOption{WithSequenceNumber(0), WithExpirationDuration(2 * time.Minute)}, 120},
+		{"rounded up", []BuildOption{WithSequenceNumber(0), WithExpirationDuration(1500 * time.Millisecond)}, 2},
+		{"orderless at limit", []BuildOption{WithReplayProtectionNonce(1), WithExpirationDuration(time.Minute)}, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := uint64(time.Now().Unix())
+			rawTxn, err := NewRawTransaction(AccountOne, payload, 1, append(tt.opts, WithGasUnitPrice(100))...)
+			if err != nil {
+				t.Fatalf("NewRawTransaction error: %v", err)
+			}
+			after := uint64(time.Now().Unix())
+			if got := rawTxn.ExpirationTimestampSecs; got < before+tt.wantSecs || got > after+tt.wantSecs {
+				t.Errorf("ExpirationTimestampSecs = %d, want %d seconds from now (%d)", got, tt.wantSecs, before)
+			}
+		})
+	}
+}
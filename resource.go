@@ -1,6 +1,14 @@
 package aptos
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/0xbe1/aptopher/bcs"
+)
 
 // MoveResource represents a Move resource stored on-chain.
 type MoveResource struct {
@@ -12,3 +20,137 @@ type MoveResource struct {
 func (r *MoveResource) DecodeData(v interface{}) error {
 	return json.Unmarshal(r.Data, v)
 }
+
+// resourceTypes maps resource types, without type arguments, to constructors
+// of the Go values their data decodes into, for MoveResource.Decode.
+var (
+	resourceTypesMu sync.RWMutex
+	resourceTypes   = map[string]func() interface{}{
+		"0x1::account::Account":         func() interface{} { return new(AccountData) },
+		"0x1::coin::CoinInfo":           func() interface{} { return new(CoinInfoResource) },
+		"0x1::coin::CoinStore":          func() interface{} { return new(CoinStoreResource) },
+		"0x1::fungible_asset::Metadata": func() interface{} { return new(FungibleAssetMetadata) },
+		"0x1::object::ObjectCore":       func() interface{} { return new(ObjectCoreResource) },
+		"0x1::stake::ValidatorSet":      func() interface{} { return new(ValidatorSet) },
+	}
+)
+
+// RegisterResourceType registers the Go type that MoveResource.Decode and
+// GetTypedResource decode resources of resourceType into. Type arguments are
+// ignored, so registering "0x1::coin::CoinStore" covers every CoinStore<T>.
+// newData returns a pointer to a new value of that type. Registering a type
+// again replaces the previous registration.
+func RegisterResourceType(resourceType string, newData func() interface{}) {
+	resourceTypesMu.Lock()
+	defer resourceTypesMu.Unlock()
+	resourceTypes[resourceBaseType(resourceType)] = newData
+}
+
+// resourceBaseType returns a resource type without its type arguments, with
+// the address in short form, as used for the registry keys.
+func resourceBaseType(resourceType string) string {
+	tag, err := ParseTypeTag(strings.TrimSpace(resourceType))
+	if err != nil {
+		return resourceType
+	}
+	structTag, ok := tag.Value.(*StructTag)
+	if !ok {
+		return resourceType
+	}
+	return structTag.Address.ShortString() + "::" + structTag.Module + "::" + structTag.Name
+}
+
+// Decode decodes the resource data into the Go type registered for the
+// resource's type with RegisterResourceType, and returns a pointer to it. For
+// example, a 0x1::coin::CoinStore<T> resource decodes to a *CoinStoreResource.
+// It reports false if no type is registered for the resource's type.
+func (r *MoveResource) Decode() (interface{}, bool, error) {
+	resourceTypesMu.RLock()
+	newData, ok := resourceTypes[resourceBaseType(r.Type)]
+	resourceTypesMu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	v := newData()
+	if err := r.DecodeData(v); err != nil {
+		return nil, true, err
+	}
+	return v, true, nil
+}
+
+// GetTypedResource retrieves a resource and decodes it with
+// MoveResource.Decode. The result is a pointer to the registered Go type,
+// such as *CoinStoreResource for "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>",
+// or the MoveResource itself if no type is registered for resourceType.
+func (c *Client) GetTypedResource(ctx context.Context, address AccountAddress, resourceType string, opts ...RequestOption) (Response[interface{}], error) {
+	resource, err := c.GetAccountResource(ctx, address, resourceType, opts...)
+	if err != nil {
+		return Response[interface{}]{}, err
+	}
+	v, ok, err := resource.Data.Decode()
+	if err != nil {
+		return Response[interface{}]{}, fmt.Errorf("decode %s: %w", resource.Data.Type, err)
+	}
+	if !ok {
+		return Response[interface{}]{Data: resource.Data, Metadata: resource.Metadata}, nil
+	}
+	return Response[interface{}]{Data: v, Metadata: resource.Metadata}, nil
+}
+
+// ResourceGroup is the BCS representation of a resource group (AIP-9): a map
+// from member struct tags to their BCS-encoded values.
+type ResourceGroup struct {
+	Members []ResourceGroupMember
+}
+
+// ResourceGroupMember is a single resource stored in a resource group.
+type ResourceGroupMember struct {
+	Type StructTag
+	Data []byte // BCS-encoded resource value
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (g *ResourceGroup) UnmarshalBCS(des *bcs.Deserializer) {
+	length := des.Uleb128()
+	if des.Error() != nil {
+		return
+	}
+	g.Members = make([]ResourceGroupMember, 0, min(int(length), des.Remaining()))
+	for i := uint32(0); i < length; i++ {
+		var member ResourceGroupMember
+		member.Type.UnmarshalBCS(des)
+		member.Data = des.Bytes()
+		if des.Error() != nil {
+			return
+		}
+		g.Members = append(g.Members, member)
+	}
+}
+
+// DecodeResourceGroup decodes a BCS map from struct tags to BCS-encoded
+// resource values. This is the format of a resource group, and also of the
+// response of GetAccountResourcesBCS, in which each resource group an account
+// holds appears as a single member whose data is itself a resource group.
+func DecodeResourceGroup(data []byte) (ResourceGroup, error) {
+	var group ResourceGroup
+	if err := bcs.Deserialize(data, &group); err != nil {
+		return ResourceGroup{}, fmt.Errorf("failed to decode resource group: %w", err)
+	}
+	return group, nil
+}
+
+// Member returns the BCS-encoded value of the member with the given type.
+// Type strings are compared in normalized form.
+func (g *ResourceGroup) Member(memberType string) ([]byte, bool) {
+	tag, err := ParseTypeTag(memberType)
+	if err != nil {
+		return nil, false
+	}
+	want := tag.String()
+	for _, member := range g.Members {
+		if member.Type.String() == want {
+			return member.Data, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,149 @@
+package aptos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/0xbe1/aptopher/bcs"
+)
+
+func TestResourceGroupMember(t *testing.T) {
+	objectCore := StructTag{Address: AccountOne, Module: "object", Name: "ObjectCore"}
+	token := StructTag{Address: AccountFour, Module: "token", Name: "Token"}
+
+	ser := bcs.NewSerializer()
+	ser.Uleb128(2)
+	objectCore.MarshalBCS(ser)
+	ser.Bytes([]byte{1, 2, 3})
+	token.MarshalBCS(ser)
+	ser.Bytes([]byte{4, 5})
+
+	var group ResourceGroup
+	if err := bcs.Deserialize(ser.ToBytes(), &group); err != nil {
+		t.Fatalf("BCS deserialize error: %v", err)
+	}
+	if len(group.Members) != 2 {
+		t.Fatalf("got %d members, want 2", len(group.Members))
+	}
+
+	data, ok := group.Member("0x1::object::ObjectCore")
+	if !ok || !bytes.Equal(data, []byte{1, 2, 3}) {
+		t.Errorf("Member(ObjectCore) = %v, %v; want [1 2 3], true", data, ok)
+	}
+	data, ok = group.Member("0x0000000000000000000000000000000000000000000000000000000000000004::token::Token")
+	if !ok || !bytes.Equal(data, []byte{4, 5}) {
+		t.Errorf("Member(Token) = %v, %v; want [4 5], true", data, ok)
+	}
+	if _, ok := group.Member("0x1::object::Untransferable"); ok {
+		t.Error("Member(Untransferable) should not be found")
+	}
+}
+
+func TestMoveResourceDecode(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		check func(t *testing.T, v interface{})
+	}{
+		{
+			name: "coin store",
+			json: `{"type":"0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>","data":{"coin":{"value":"100"},"frozen":true,
+				"deposit_events":{"counter":"1","guid":{"id":{"addr":"0xcafe","creation_num":"2"}}},
+				"withdraw_events":{"counter":"0","guid":{"id":{"addr":"0xcafe","creation_num":"3"}}}}}`,
+			check: func(t *testing.T, v interface{}) {
+				store, ok := v.(*CoinStoreResource)
+				if !ok || store.Coin.Value != 100 || !store.Frozen || store.WithdrawEvents.GUID.ID.CreationNumber != 3 {
+					t.Errorf("Decode() = %#v", v)
+				}
+			},
+		},
+		{
+			name: "long address form",
+			json: `{"type":"0x0000000000000000000000000000000000000000000000000000000000000001::object::ObjectCore","data":{"owner":"0xcafe","allow_ungated_transfer":true}}`,
+			check: func(t *testing.T, v interface{}) {
+				core, ok := v.(*ObjectCoreResource)
+				if !ok || core.Owner != MustParseAccountAddress("0xcafe") || !core.AllowUngatedTransfer {
+					t.Errorf("Decode() = %#v", v)
+				}
+			},
+		},
+		{
+			name: "fungible asset metadata",
+			json: `{"type":"0x1::fungible_asset::Metadata","data":{"name":"Aptos Coin","symbol":"APT","decimals":8,"icon_uri":"","project_uri":""}}`,
+			check: func(t *testing.T, v interface{}) {
+				metadata, ok := v.(*FungibleAssetMetadata)
+				if !ok || metadata.Symbol != "APT" || metadata.Decimals != 8 {
+					t.Errorf("Decode() = %#v", v)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resource MoveResource
+			if err := json.Unmarshal([]byte(tt.json), &resource); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			v, ok, err := resource.Decode()
+			if err != nil || !ok {
+				t.Fatalf("Decode() = %v, %v", ok, err)
+			}
+			tt.check(t, v)
+		})
+	}
+
+	unknown := MoveResource{Type: "0xcafe::game::Score", Data: json.RawMessage(`{"points":"3"}`)}
+	if v, ok, err := unknown.Decode(); ok || err != nil || v != nil {
+		t.Errorf("Decode() of unregistered type = %v, %v, %v; want nil, false, nil", v, ok, err)
+	}
+
+	type score struct {
+		Points JSONUint64 `json:"points"`
+	}
+	RegisterResourceType("0xcafe::game::Score", func() interface{} { return new(score) })
+	v, ok, err := unknown.Decode()
+	if got, isScore := v.(*score); err != nil || !ok || !isScore || got.Points != 3 {
+		t.Errorf("Decode() of registered type = %#v, %v, %v", v, ok, err)
+	}
+}
+
+func TestGetTypedResource(t *testing.T) {
+	client, closeServer := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Aptos-Ledger-Version", "5")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/resource/0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>"):
+			w.Write([]byte(`{"type":"0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>","data":{"coin":{"value":"250"},"frozen":false}}`))
+		case strings.HasSuffix(r.URL.Path, "/resource/0xcafe::game::Unknown"):
+			w.Write([]byte(`{"type":"0xcafe::game::Unknown","data":{"x":"1"}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer closeServer()
+
+	ctx := context.Background()
+
+	resp, err := client.GetTypedResource(ctx, AccountOne, "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>")
+	if err != nil {
+		t.Fatalf("GetTypedResource error: %v", err)
+	}
+	store, ok := resp.Data.(*CoinStoreResource)
+	if !ok || store.Coin.Value != 250 {
+		t.Errorf("GetTypedResource = %#v, want *CoinStoreResource with 250", resp.Data)
+	}
+	if resp.Metadata.LedgerVersion != 5 {
+		t.Errorf("LedgerVersion = %d, want 5", resp.Metadata.LedgerVersion)
+	}
+
+	resp, err = client.GetTypedResource(ctx, AccountOne, "0xcafe::game::Unknown")
+	if err != nil {
+		t.Fatalf("GetTypedResource error: %v", err)
+	}
+	if resource, ok := resp.Data.(MoveResource); !ok || resource.Type != "0xcafe::game::Unknown" {
+		t.Errorf("GetTypedResource = %#v, want the raw MoveResource", resp.Data)
+	}
+}
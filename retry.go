@@ -0,0 +1,120 @@
+package aptos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultRetryBackoff is the delay before the first retry when
+	// ClientConfig.RetryBackoff is unset.
+	defaultRetryBackoff = 200 * time.Millisecond
+
+	// maxRetryBackoff caps the delay between retries, whether from the
+	// exponential backoff or a Retry-After header.
+	maxRetryBackoff = 30 * time.Second
+)
+
+// retryPolicy decides whether and when failed requests are retried.
+// A nil *retryPolicy never retries.
+type retryPolicy struct {
+	maxRetries int
+	backoff    time.Duration
+	jitter     float64
+}
+
+// newRetryPolicy returns the policy set by a ClientConfig, or nil if retries
+// are disabled.
+func newRetryPolicy(config ClientConfig) *retryPolicy {
+	if config.MaxRetries <= 0 {
+		return nil
+	}
+	backoff := config.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	return &retryPolicy{
+		maxRetries: config.MaxRetries,
+		backoff:    backoff,
+		jitter:     min(max(config.RetryJitter, 0), 1),
+	}
+}
+
+// delay returns how long to wait before retry number attempt (from zero):
+// the node's Retry-After if it sent one, or else the backoff doubled for each
+// earlier retry and reduced by a random fraction of up to the jitter. Either
+// is capped at maxRetryBackoff, since a retry of a deduplicated read cannot be
+// canceled by its callers.
+func (p *retryPolicy) delay(attempt int, metadata ResponseMetadata) time.Duration {
+	if metadata.RetryAfter > 0 {
+		return min(metadata.RetryAfter, maxRetryBackoff)
+	}
+	d := p.backoff
+	for i := 0; i < attempt && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, maxRetryBackoff)
+	return d - time.Duration(p.jitter*rand.Float64()*float64(d))
+}
+
+// isRetryableRequest reports whether a request can safely be sent again:
+// it only reads state or simulates a transaction. Submissions are never
+// retried, since a failed response does not prove the node rejected the
+// transaction.
+func isRetryableRequest(method, path string) bool {
+	return isIdempotentRead(method, path) ||
+		(method == http.MethodPost && (strings.HasPrefix(path, "/transactions/simulate") || strings.HasPrefix(path, "/tables/")))
+}
+
+// isRetryableError reports whether a failed request may succeed if retried:
+// it was rate limited, failed on the server, or did not reach the node.
+// Timeouts are not retried, as the request may simply need more time.
+func isRetryableError(err error) bool {
+	switch ErrorKind(err) {
+	case ErrKindRateLimited, ErrKindServerError, ErrKindNetworkError:
+		return true
+	default:
+		return false
+	}
+}
+
+// roundTripWithRetries sends a request like roundTrip, retrying it according
+// to the client's retry policy if it is safe to send again.
+func (c *httpClient) roundTripWithRetries(ctx context.Context, method, path string, body io.Reader, contentType, accept string) ([]byte, ResponseMetadata, error) {
+	if c.retry == nil || !isRetryableRequest(method, path) {
+		return c.roundTrip(ctx, method, path, body, contentType, accept)
+	}
+
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		if bodyBytes, err = io.ReadAll(body); err != nil {
+			return nil, ResponseMetadata{}, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		var body io.Reader
+		if bodyBytes != nil {
+			body = bytes.NewReader(bodyBytes)
+		}
+		respBody, metadata, err := c.roundTrip(ctx, method, path, body, contentType, accept)
+		if err == nil || attempt == c.retry.maxRetries || !isRetryableError(err) || ctx.Err() != nil {
+			return respBody, metadata, err
+		}
+
+		timer := time.NewTimer(c.retry.delay(attempt, metadata))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, metadata, fmt.Errorf("request failed: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package aptos
 
 import (
+	"fmt"
+	"math"
+	"time"
+
 	"golang.org/x/crypto/sha3"
 
 	"github.com/0xbe1/aptopher/bcs"
@@ -19,6 +23,13 @@ func (t SignedTransaction) MarshalBCS(ser *bcs.Serializer) {
 	t.Authenticator.MarshalBCS(ser)
 }
 
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (t *SignedTransaction) UnmarshalBCS(des *bcs.Deserializer) {
+	t.RawTxn = &RawTransaction{}
+	t.RawTxn.UnmarshalBCS(des)
+	t.Authenticator.UnmarshalBCS(des)
+}
+
 // Bytes returns the BCS-encoded signed transaction.
 func (t *SignedTransaction) Bytes() ([]byte, error) {
 	return bcs.Serialize(t)
@@ -31,7 +42,93 @@ func (t *SignedTransaction) Hash() (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return signedTransactionHash(txnBytes), nil
+}
+
+// Limits checked by SignedTransaction.Validate, matching mainnet's on-chain
+// gas schedule.
+const (
+	// MaxTransactionSize is the maximum size of a regular (non-governance)
+	// signed transaction, in bytes.
+	MaxTransactionSize = 64 * 1024
+
+	// MaxGasAmountLimit is the maximum number of gas units a transaction may reserve.
+	MaxGasAmountLimit = uint64(2_000_000)
+)
+
+// Validate checks that the transaction is well-formed and not obviously
+// doomed, so relayers can reject it before submitting it:
+//   - the authenticator variant is supported and matches its contents
+//   - public key and signature lengths match their schemes
+//   - the expiration is in the future, and at most
+//     OrderlessMaxExpirationSeconds away for orderless transactions
+//   - the max gas amount and gas unit price are non-zero, the max gas amount
+//     is at most MaxGasAmountLimit, and the maximum fee does not overflow
+//   - the serialized transaction is at most MaxTransactionSize bytes
+//
+// Signatures are not verified.
+func (t *SignedTransaction) Validate() error {
+	return t.validate(time.Now())
+}
+
+func (t *SignedTransaction) validate(now time.Time) error {
+	if t.RawTxn == nil {
+		return fmt.Errorf("missing raw transaction")
+	}
+	if t.RawTxn.Payload.Payload == nil {
+		return fmt.Errorf("missing payload")
+	}
+	if t.RawTxn.ChainID == 0 {
+		return fmt.Errorf("missing chain ID")
+	}
+	if err := t.Authenticator.validate(); err != nil {
+		return fmt.Errorf("invalid authenticator: %w", err)
+	}
+
+	expiration := t.RawTxn.ExpirationTimestampSecs
+	nowSecs := uint64(now.Unix())
+	if expiration <= nowSecs {
+		return fmt.Errorf("transaction expired at %s", time.Unix(int64(expiration), 0).UTC().Format(time.RFC3339))
+	}
+	if isOrderlessPayload(t.RawTxn.Payload) && expiration > nowSecs+OrderlessMaxExpirationSeconds {
+		return fmt.Errorf("orderless transaction expires in %ds, more than the maximum of %ds", expiration-nowSecs, OrderlessMaxExpirationSeconds)
+	}
+
+	switch maxGas, gasPrice := t.RawTxn.MaxGasAmount, t.RawTxn.GasUnitPrice; {
+	case maxGas == 0:
+		return fmt.Errorf("max gas amount is zero")
+	case maxGas > MaxGasAmountLimit:
+		return fmt.Errorf("max gas amount %d exceeds the limit of %d", maxGas, MaxGasAmountLimit)
+	case gasPrice == 0:
+		return fmt.Errorf("gas unit price is zero")
+	case gasPrice > math.MaxUint64/maxGas:
+		return fmt.Errorf("max gas amount %d at gas unit price %d overflows", maxGas, gasPrice)
+	}
+
+	txnBytes, err := t.Bytes()
+	if err != nil {
+		return fmt.Errorf("serialize transaction: %w", err)
+	}
+	if len(txnBytes) > MaxTransactionSize {
+		return fmt.Errorf("transaction size %d bytes exceeds the limit of %d", len(txnBytes), MaxTransactionSize)
+	}
+	return nil
+}
+
+// isOrderlessPayload reports whether the payload carries a replay protection nonce.
+func isOrderlessPayload(payload TransactionPayload) bool {
+	switch p := payload.Payload.(type) {
+	case *TransactionInnerPayloadV1:
+		return p.ExtraConfig.ReplayProtectionNonce != nil
+	case TransactionInnerPayloadV1:
+		return p.ExtraConfig.ReplayProtectionNonce != nil
+	default:
+		return false
+	}
+}
 
+// signedTransactionHash returns the hash of a BCS-encoded signed transaction.
+func signedTransactionHash(txnBytes []byte) string {
 	// Use incremental hashing to avoid intermediate allocation
 	h := sha3.New256()
 	h.Write(crypto.TransactionHashPrefix)
@@ -40,7 +137,7 @@ func (t *SignedTransaction) Hash() (string, error) {
 
 	var hash [32]byte
 	h.Sum(hash[:0])
-	return bytesToHex(hash[:]), nil
+	return bytesToHex(hash[:])
 }
 
 func bytesToHex(b []byte) string {
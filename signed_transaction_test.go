@@ -0,0 +1,548 @@
+package aptos
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/crypto"
+)
+
+func TestSignedTransactionValidate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	account, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+	entryFunction := &EntryFunction{
+		Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+		Function: "transfer",
+		Args:     EntryFunctionArgs(AddressArg(AccountOne), U64Arg(1)),
+	}
+	nonce := uint64(7)
+
+	// singleKey replaces the transaction's Ed25519 authenticator with the
+	// equivalent single-key authenticator and returns it.
+	singleKey := func(txn *SignedTransaction) *AccountAuthenticatorSingleKey {
+		ed25519 := txn.Authenticator.Auth.(*AccountAuthenticatorEd25519)
+		auth := &AccountAuthenticatorSingleKey{
+			PublicKey: AnyPublicKey{Variant: crypto.Ed25519Scheme, PublicKey: ed25519.PublicKey[:]},
+			Signature: AnySignature{Variant: crypto.Ed25519Scheme, Signature: ed25519.Signature[:]},
+		}
+		txn.Authenticator = TransactionAuthenticator{Variant: TransactionAuthenticatorSingleSender, Auth: auth}
+		return auth
+	}
+
+	sign := func(t *testing.T, payload TransactionPayload, opts ...BuildOption) *SignedTransaction {
+		t.Helper()
+		opts = append([]BuildOption{WithGasUnitPrice(100), WithExpirationTimestampSecs(uint64(now.Unix()) + 30)}, opts...)
+		rawTxn, err := NewRawTransaction(account.Address, payload, 2, opts...)
+		if err != nil {
+			t.Fatalf("NewRawTransaction error: %v", err)
+		}
+		signedTxn, err := account.SignTransaction(rawTxn)
+		if err != nil {
+			t.Fatalf("SignTransaction error: %v", err)
+		}
+		return signedTxn
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(txn *SignedTransaction)
+		wantErr string
+	}{
+		{"valid", func(txn *SignedTransaction) {}, ""},
+		{"valid single key", func(txn *SignedTransaction) { singleKey(txn) }, ""},
+		{"expired", func(txn *SignedTransaction) { txn.RawTxn.ExpirationTimestampSecs = uint64(now.Unix()) }, "expired"},
+		{"zero max gas", func(txn *SignedTransaction) { txn.RawTxn.MaxGasAmount = 0 }, "max gas amount is zero"},
+		{"max gas above limit", func(txn *SignedTransaction) { txn.RawTxn.MaxGasAmount = MaxGasAmountLimit + 1 }, "exceeds the limit"},
+		{"zero gas price", func(txn *SignedTransaction) { txn.RawTxn.GasUnitPrice = 0 }, "gas unit price is zero"},
+		{"fee overflow", func(txn *SignedTransaction) { txn.RawTxn.GasUnitPrice = 1 << 60 }, "overflows"},
+		{"missing chain ID", func(txn *SignedTransaction) { txn.RawTxn.ChainID = 0 }, "chain ID"},
+		{"too large", func(txn *SignedTransaction) {
+			txn.RawTxn.Payload = TransactionPayload{Payload: &Script{Code: make([]byte, MaxTransactionSize)}}
+		}, "transaction size"},
+		{"unsupported variant", func(txn *SignedTransaction) {
+			txn.Authenticator.Variant = TransactionAuthenticatorVariant(5)
+		}, "unsupported transaction authenticator variant"},
+		{"single sender with wrong type", func(txn *SignedTransaction) {
+			txn.Authenticator.Variant = TransactionAuthenticatorSingleSender
+			txn.Authenticator.Auth = &MultiAgentAuthenticator{}
+		}, "unsupported account authenticator type"},
+		{"mismatched variant", func(txn *SignedTransaction) {
+			txn.Authenticator.Variant = TransactionAuthenticatorFeePayer
+		}, "does not match variant"},
+		{"short public key", func(txn *SignedTransaction) {
+			singleKey(txn).PublicKey.PublicKey = make([]byte, 31)
+		}, "public key length"},
+		{"short signature", func(txn *SignedTransaction) {
+			singleKey(txn).Signature.Signature = make([]byte, 63)
+		}, "signature length"},
+		{"mismatched schemes", func(txn *SignedTransaction) {
+			singleKey(txn).Signature.Variant = 1
+		}, "does not match signature scheme"},
+		{"multi ed25519 with ed25519 auth", func(txn *SignedTransaction) {
+			txn.Authenticator.Variant = TransactionAuthenticatorMultiEd25519
+		}, "does not match variant"},
+		{"multi agent without secondary signer", func(txn *SignedTransaction) {
+			txn.Authenticator = TransactionAuthenticator{Variant: TransactionAuthenticatorMultiAgent, Auth: &MultiAgentAuthenticator{
+				Sender:                   txn.Authenticator.Auth,
+				SecondarySignerAddresses: []AccountAddress{AccountOne},
+			}}
+		}, "1 secondary signer addresses for 0 secondary signers"},
+		{"fee payer", func(txn *SignedTransaction) {
+			txn.Authenticator = TransactionAuthenticator{Variant: TransactionAuthenticatorFeePayer, Auth: &FeePayerAuthenticator{
+				Sender:          txn.Authenticator.Auth,
+				FeePayerAddress: AccountOne,
+				FeePayer:        &AccountAuthenticatorEd25519{},
+			}}
+		}, ""},
+		{"fee payer missing", func(txn *SignedTransaction) {
+			txn.Authenticator = TransactionAuthenticator{Variant: TransactionAuthenticatorFeePayer, Auth: &FeePayerAuthenticator{
+				Sender:          txn.Authenticator.Auth,
+				FeePayerAddress: AccountOne,
+			}}
+		}, "fee payer: missing account authenticator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := sign(t, TransactionPayload{Payload: entryFunction}, WithSequenceNumber(0))
+			tt.modify(txn)
+			err := txn.validate(now)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("orderless expiration", func(t *testing.T) {
+		txn := sign(t, TransactionPayload{Payload: entryFunction}, WithReplayProtectionNonce(nonce))
+		if err := txn.validate(now); err != nil {
+			t.Errorf("validate() error = %v", err)
+		}
+		txn = sign(t, TransactionPayload{Payload: entryFunction}, WithReplayProtectionNonce(nonce),
+			WithExpirationTimestampSecs(uint64(now.Unix())+OrderlessMaxExpirationSeconds+1))
+		if err := txn.validate(now); err == nil || !strings.Contains(err.Error(), "orderless") {
+			t.Errorf("validate() error = %v, want orderless expiration error", err)
+		}
+	})
+}
+
+func TestSignMultiEd25519(t *testing.T) {
+	var publicKeys [][]byte
+	signers := make(map[int]crypto.Signer)
+	for i := 0; i < 3; i++ {
+		priv, err := crypto.GenerateEd25519PrivateKey()
+		if err != nil {
+			t.Fatalf("GenerateEd25519PrivateKey error: %v", err)
+		}
+		publicKeys = append(publicKeys, priv.PublicKey())
+		if i != 1 {
+			signers[i] = priv.Signer()
+		}
+	}
+	publicKey, err := crypto.NewMultiEd25519PublicKey(publicKeys, 2)
+	if err != nil {
+		t.Fatalf("NewMultiEd25519PublicKey error: %v", err)
+	}
+	sender := AccountAddressFromMultiEd25519(publicKey)
+
+	rawTxn, err := NewRawTransaction(sender, TransactionPayload{Payload: &EntryFunction{
+		Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+		Function: "transfer",
+		Args:     EntryFunctionArgs(AddressArg(AccountOne), U64Arg(1)),
+	}}, 2, WithSequenceNumber(0), WithGasUnitPrice(100), WithExpirationTimestampSecs(uint64(time.Now().Unix())+30))
+	if err != nil {
+		t.Fatalf("NewRawTransaction error: %v", err)
+	}
+	signedTxn, err := rawTxn.SignMultiEd25519(publicKey, signers)
+	if err != nil {
+		t.Fatalf("SignMultiEd25519 error: %v", err)
+	}
+	if err := signedTxn.Validate(); err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+
+	// Variant 1, then the key and the signature as length-prefixed bytes
+	authBytes, err := bcs.Serialize(signedTxn.Authenticator)
+	if err != nil {
+		t.Fatalf("Serialize error: %v", err)
+	}
+	auth := signedTxn.Authenticator.Auth.(*AccountAuthenticatorMultiEd25519)
+	want := []byte{1, 97}
+	want = append(want, publicKey.Bytes()...)
+	want = append(want, 0x84, 0x01) // ULEB128 of 132
+	want = append(want, auth.Signature.Signatures[0]...)
+	want = append(want, auth.Signature.Signatures[1]...)
+	want = append(want, 0b10100000, 0, 0, 0)
+	if !bytes.Equal(authBytes, want) {
+		t.Errorf("authenticator = %x, want %x", authBytes, want)
+	}
+
+	signingMessage, _ := rawTxn.SigningMessage()
+	if !crypto.VerifyMultiEd25519(&auth.PublicKey, signingMessage, &auth.Signature) {
+		t.Error("signature verification failed")
+	}
+
+	var decoded SignedTransaction
+	txnBytes, _ := signedTxn.Bytes()
+	if err := bcs.Deserialize(txnBytes, &decoded); err != nil {
+		t.Fatalf("Deserialize error: %v", err)
+	}
+	if err := decoded.Validate(); err != nil {
+		t.Errorf("decoded Validate error: %v", err)
+	}
+
+	delete(signers, 2)
+	if _, err := rawTxn.SignMultiEd25519(publicKey, signers); err == nil || !strings.Contains(err.Error(), "threshold") {
+		t.Errorf("SignMultiEd25519 with one signer error = %v, want threshold error", err)
+	}
+	signers[1] = signers[0]
+	if _, err := rawTxn.SignMultiEd25519(publicKey, signers); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("SignMultiEd25519 with the wrong key error = %v, want mismatch error", err)
+	}
+}
+
+func TestSignMultiKey(t *testing.T) {
+	// A 2-of-3 multi-key mixing Ed25519 and secp256k1 keys
+	ed0, _ := crypto.NewEd25519PrivateKey(bytes.Repeat([]byte{1}, 32))
+	secp1, _ := crypto.NewSecp256k1PrivateKey(bytes.Repeat([]byte{2}, 32))
+	ed2, _ := crypto.NewEd25519PrivateKey(bytes.Repeat([]byte{3}, 32))
+	publicKey, err := NewMultiKey([]AnyPublicKey{
+		{Variant: crypto.Ed25519Scheme, PublicKey: ed0.PublicKey()},
+		{Variant: crypto.Secp256k1Scheme, PublicKey: secp1.PublicKey()}, // compressed
+		{Variant: crypto.Ed25519Scheme, PublicKey: ed2.PublicKey()},
+	}, 2)
+	if err != nil {
+		t.Fatalf("NewMultiKey error: %v", err)
+	}
+	secpKey := publicKey.PublicKeys[1].PublicKey
+	if len(secpKey) != crypto.Secp256k1UncompressedPublicKeyLength {
+		t.Fatalf("secp256k1 key length = %d, want uncompressed", len(secpKey))
+	}
+
+	sender, err := AccountAddressFromMultiKey(publicKey)
+	if err != nil {
+		t.Fatalf("AccountAddressFromMultiKey error: %v", err)
+	}
+	keyBytes, _ := bcs.Serialize(publicKey)
+	if sender != AccountAddress(crypto.Sha3256(append(keyBytes, 0x03))) {
+		t.Error("address is not SHA3-256(bcs(MultiKey) || 0x03)")
+	}
+
+	rawTxn, err := NewRawTransaction(sender, TransactionPayload{Payload: &EntryFunction{
+		Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+		Function: "transfer",
+		Args:     EntryFunctionArgs(AddressArg(AccountOne), U64Arg(1)),
+	}}, 2, WithSequenceNumber(0), WithGasUnitPrice(100), WithExpirationTimestampSecs(uint64(time.Now().Unix())+30))
+	if err != nil {
+		t.Fatalf("NewRawTransaction error: %v", err)
+	}
+	signedTxn, err := rawTxn.SignMultiKey(publicKey, map[int]crypto.Signer{2: ed2.Signer(), 1: secp1.Signer()})
+	if err != nil {
+		t.Fatalf("SignMultiKey error: %v", err)
+	}
+	if err := signedTxn.Validate(); err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+
+	auth := signedTxn.Authenticator.Auth.(*AccountAuthenticatorMultiKey)
+	signingMessage, _ := rawTxn.SigningMessage()
+	secpSig, edSig := auth.Signature.Signatures[0], auth.Signature.Signatures[1]
+	if !crypto.VerifySecp256k1(secpKey, signingMessage, secpSig.Signature) || !crypto.VerifyEd25519(ed2.PublicKey(), signingMessage, edSig.Signature) {
+		t.Error("signatures are not in key order or do not verify")
+	}
+
+	// SingleSender, MultiKey variant, the keys and threshold, then the
+	// signatures and the length-prefixed bitmap
+	want := []byte{4, 3, 3}
+	want = append(append(want, 0, 32), ed0.PublicKey()...)
+	want = append(append(want, 1, 65), secpKey...)
+	want = append(append(want, 0, 32), ed2.PublicKey()...)
+	want = append(want, 2, 2)
+	want = append(append(want, 1, 64), secpSig.Signature...)
+	want = append(append(want, 0, 64), edSig.Signature...)
+	want = append(want, 4, 0b01100000, 0, 0, 0)
+	authBytes, err := bcs.Serialize(signedTxn.Authenticator)
+	if err != nil {
+		t.Fatalf("Serialize error: %v", err)
+	}
+	if !bytes.Equal(authBytes, want) {
+		t.Errorf("authenticator = %x, want %x", authBytes, want)
+	}
+
+	var decoded SignedTransaction
+	txnBytes, _ := signedTxn.Bytes()
+	if err := bcs.Deserialize(txnBytes, &decoded); err != nil {
+		t.Fatalf("Deserialize error: %v", err)
+	}
+	if err := decoded.Validate(); err != nil {
+		t.Errorf("decoded Validate error: %v", err)
+	}
+
+	if _, err := rawTxn.SignMultiKey(publicKey, map[int]crypto.Signer{0: ed0.Signer()}); err == nil || !strings.Contains(err.Error(), "threshold") {
+		t.Errorf("SignMultiKey with one signer error = %v, want threshold error", err)
+	}
+	if _, err := rawTxn.SignMultiKey(publicKey, map[int]crypto.Signer{0: ed0.Signer(), 1: ed2.Signer()}); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("SignMultiKey with the wrong key error = %v, want mismatch error", err)
+	}
+
+	// One signature is below the threshold
+	auth.Signature.Signatures = auth.Signature.Signatures[:1]
+	auth.Signature.Bitmap = [4]byte{0b01000000}
+	if err := signedTxn.Validate(); err == nil || !strings.Contains(err.Error(), "threshold") {
+		t.Errorf("Validate with one signature error = %v, want threshold error", err)
+	}
+}
+
+// fakeSigner is an Ed25519 signer that returns a fixed signature and records
+// the messages it signed, so tests can check exact authenticator bytes.
+type fakeSigner struct {
+	publicKey [crypto.Ed25519PublicKeyLength]byte
+	signature [crypto.Ed25519SignatureLength]byte
+	messages  [][]byte
+}
+
+func newFakeSigner(b byte) *fakeSigner {
+	s := &fakeSigner{}
+	copy(s.publicKey[:], bytes.Repeat([]byte{b}, len(s.publicKey)))
+	copy(s.signature[:], bytes.Repeat([]byte{b + 1}, len(s.signature)))
+	return s
+}
+
+func (s *fakeSigner) Sign(message []byte) ([]byte, error) {
+	s.messages = append(s.messages, message)
+	return s.signature[:], nil
+}
+
+func (s *fakeSigner) PublicKey() []byte { return s.publicKey[:] }
+
+func (s *fakeSigner) AuthKey() [32]byte {
+	return crypto.AuthenticationKey(s.publicKey[:], crypto.Ed25519Scheme)
+}
+
+func (s *fakeSigner) Scheme() crypto.SignatureScheme { return crypto.Ed25519Scheme }
+
+// ed25519AuthenticatorBytes returns the BCS AccountAuthenticator of an
+// Ed25519 signer: the variant, then the length-prefixed key and signature.
+func ed25519AuthenticatorBytes(s *fakeSigner) []byte {
+	b := []byte{0, crypto.Ed25519PublicKeyLength}
+	b = append(b, s.publicKey[:]...)
+	b = append(b, crypto.Ed25519SignatureLength)
+	return append(b, s.signature[:]...)
+}
+
+func TestSignMultiAgent(t *testing.T) {
+	transport := &countingTransport{}
+	client, err := NewClient(ClientConfig{NodeURL: "http://localhost", Transport: transport})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+
+	sender, second, third := newFakeSigner(0x10), newFakeSigner(0x20), newFakeSigner(0x30)
+	secondAddr, thirdAddr := MustParseAccountAddress("0xb0b"), MustParseAccountAddress("0xca7")
+	payload := TransactionPayload{Payload: &EntryFunction{
+		Module:   ModuleId{Address: MustParseAccountAddress("0xcafe"), Name: "swap"},
+		Function: "swap",
+	}}
+	txn, err := client.BuildMultiAgentTransaction(context.Background(), AccountOne, []AccountAddress{thirdAddr, secondAddr}, payload,
+		WithSequenceNumber(3), WithGasUnitPrice(100), WithChainID(4), WithExpirationTimestampSecs(uint64(time.Now().Unix())+60))
+	if err != nil {
+		t.Fatalf("BuildMultiAgentTransaction error: %v", err)
+	}
+	if transport.requests != 0 {
+		t.Errorf("transport saw %d requests, want 0", transport.requests)
+	}
+
+	// Secondary signers sign in any order; the authenticators follow the
+	// order of the addresses
+	secondAuth, err := txn.Sign(second)
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	thirdAuth, err := txn.Sign(third)
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	senderAuth, err := txn.Sign(sender)
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	signedTxn, err := txn.MultiAgentTransaction(senderAuth, []AccountAuthenticatorImpl{thirdAuth, secondAuth})
+	if err != nil {
+		t.Fatalf("MultiAgentTransaction error: %v", err)
+	}
+	if err := signedTxn.Validate(); err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+
+	// Every signer signs SHA3-256(prefix || bcs(RawTransactionWithData))
+	rawBytes, _ := txn.RawTxn.Bytes()
+	withData := append([]byte{0}, rawBytes...)
+	withData = append(append(withData, 2), thirdAddr[:]...)
+	withData = append(withData, secondAddr[:]...)
+	signingMessage := crypto.HashWithPrefix(crypto.RawTransactionWithDataHashPrefix, withData)
+	for i, signer := range []*fakeSigner{sender, second, third} {
+		if len(signer.messages) != 1 || !bytes.Equal(signer.messages[0], signingMessage) {
+			t.Errorf("signer %d did not sign the RawTransactionWithData signing message", i)
+		}
+	}
+
+	// The raw transaction, the MultiAgent variant, the sender, the secondary
+	// addresses, and their authenticators in the same order
+	want := append([]byte(nil), rawBytes...)
+	want = append(want, byte(TransactionAuthenticatorMultiAgent))
+	want = append(want, ed25519AuthenticatorBytes(sender)...)
+	want = append(append(want, 2), thirdAddr[:]...)
+	want = append(want, secondAddr[:]...)
+	want = append(want, 2)
+	want = append(want, ed25519AuthenticatorBytes(third)...)
+	want = append(want, ed25519AuthenticatorBytes(second)...)
+	txnBytes, err := signedTxn.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes error: %v", err)
+	}
+	if !bytes.Equal(txnBytes, want) {
+		t.Errorf("signed transaction = %x, want %x", txnBytes, want)
+	}
+
+	// SignMultiAgent produces the same transaction in one call
+	oneCall, err := txn.SignMultiAgent(sender, third, second)
+	if err != nil {
+		t.Fatalf("SignMultiAgent error: %v", err)
+	}
+	if oneCallBytes, _ := oneCall.Bytes(); !bytes.Equal(oneCallBytes, want) {
+		t.Errorf("SignMultiAgent = %x, want %x", oneCallBytes, want)
+	}
+
+	if _, err := txn.MultiAgentTransaction(senderAuth, []AccountAuthenticatorImpl{thirdAuth}); err == nil {
+		t.Error("MultiAgentTransaction with a missing secondary signer should fail")
+	}
+}
+
+func TestFeePayerSign(t *testing.T) {
+	sender, second, sponsor := newFakeSigner(0x10), newFakeSigner(0x20), newFakeSigner(0x40)
+	secondAddr, sponsorAddr := MustParseAccountAddress("0xb0b"), MustParseAccountAddress("0x5b0")
+	rawTxn, err := NewRawTransaction(AccountOne, TransactionPayload{Payload: &EntryFunction{
+		Module:   ModuleId{Address: MustParseAccountAddress("0xcafe"), Name: "swap"},
+		Function: "swap",
+	}}, 4, WithSequenceNumber(3), WithGasUnitPrice(100), WithExpirationTimestampSecs(uint64(time.Now().Unix())+60))
+	if err != nil {
+		t.Fatalf("NewRawTransaction error: %v", err)
+	}
+	rawBytes, _ := rawTxn.Bytes()
+
+	// signingMessage returns SHA3-256(prefix || bcs(RawTransactionWithData))
+	// for the FeePayer variant with the given fee payer
+	signingMessage := func(secondaries []AccountAddress, feePayer AccountAddress) []byte {
+		b := append([]byte{1}, rawBytes...)
+		b = append(b, byte(len(secondaries)))
+		for _, addr := range secondaries {
+			b = append(b, addr[:]...)
+		}
+		b = append(b, feePayer[:]...)
+		return crypto.HashWithPrefix(crypto.RawTransactionWithDataHashPrefix, b)
+	}
+
+	t.Run("fee payer known when signing", func(t *testing.T) {
+		sender.messages, second.messages, sponsor.messages = nil, nil, nil
+		txn := NewFeePayerTransaction(rawTxn, []AccountAddress{secondAddr}, sponsorAddr)
+		senderAuth, err := txn.Sign(sender)
+		if err != nil {
+			t.Fatalf("Sign error: %v", err)
+		}
+		secondAuth, err := txn.Sign(second)
+		if err != nil {
+			t.Fatalf("Sign error: %v", err)
+		}
+		signedTxn, err := txn.FeePayerSign(sponsorAddr, sponsor, senderAuth, []AccountAuthenticatorImpl{secondAuth})
+		if err != nil {
+			t.Fatalf("FeePayerSign error: %v", err)
+		}
+		if err := signedTxn.Validate(); err != nil {
+			t.Fatalf("Validate error: %v", err)
+		}
+
+		want := signingMessage([]AccountAddress{secondAddr}, sponsorAddr)
+		for i, signer := range []*fakeSigner{sender, second, sponsor} {
+			if len(signer.messages) != 1 || !bytes.Equal(signer.messages[0], want) {
+				t.Errorf("signer %d did not sign the shared signing message", i)
+			}
+		}
+
+		// The FeePayer variant, the sender, the secondary addresses and
+		// authenticators, then the fee payer's address and authenticator
+		wantAuth := []byte{byte(TransactionAuthenticatorFeePayer)}
+		wantAuth = append(wantAuth, ed25519AuthenticatorBytes(sender)...)
+		wantAuth = append(append(wantAuth, 1), secondAddr[:]...)
+		wantAuth = append(wantAuth, 1)
+		wantAuth = append(wantAuth, ed25519AuthenticatorBytes(second)...)
+		wantAuth = append(wantAuth, sponsorAddr[:]...)
+		wantAuth = append(wantAuth, ed25519AuthenticatorBytes(sponsor)...)
+		authBytes, err := bcs.Serialize(signedTxn.Authenticator)
+		if err != nil {
+			t.Fatalf("Serialize error: %v", err)
+		}
+		if !bytes.Equal(authBytes, wantAuth) {
+			t.Errorf("authenticator = %x, want %x", authBytes, wantAuth)
+		}
+	})
+
+	t.Run("fee payer filled in later", func(t *testing.T) {
+		sender.messages, sponsor.messages = nil, nil
+		transport := &countingTransport{}
+		client, err := NewClient(ClientConfig{NodeURL: "http://localhost", Transport: transport})
+		if err != nil {
+			t.Fatalf("NewClient error: %v", err)
+		}
+		txn, err := client.BuildFeePayerTransaction(context.Background(), rawTxn.Sender, rawTxn.Payload,
+			WithSequenceNumber(3), WithGasUnitPrice(100), WithChainID(4), WithExpirationTimestampSecs(rawTxn.ExpirationTimestampSecs))
+		if err != nil {
+			t.Fatalf("BuildFeePayerTransaction error: %v", err)
+		}
+		if txn.Variant != FeePayer || !txn.FeePayerAddress.IsZero() || transport.requests != 0 {
+			t.Fatalf("BuildFeePayerTransaction = %+v after %d requests, want fee payer 0x0 and no requests", txn, transport.requests)
+		}
+
+		senderAuth, err := txn.Sign(sender)
+		if err != nil {
+			t.Fatalf("Sign error: %v", err)
+		}
+		signedTxn, err := txn.FeePayerSign(sponsorAddr, sponsor, senderAuth, nil)
+		if err != nil {
+			t.Fatalf("FeePayerSign error: %v", err)
+		}
+		if txn.FeePayerAddress != sponsorAddr {
+			t.Errorf("FeePayerAddress = %s, want %s", txn.FeePayerAddress, sponsorAddr)
+		}
+		auth := signedTxn.Authenticator.Auth.(*FeePayerAuthenticator)
+		if auth.FeePayerAddress != sponsorAddr {
+			t.Errorf("authenticator fee payer = %s, want %s", auth.FeePayerAddress, sponsorAddr)
+		}
+
+		// The sender signed over 0x0, and the sponsor over its own address
+		if !bytes.Equal(sender.messages[0], signingMessage(nil, AccountAddress{})) {
+			t.Error("sender did not sign over the zero fee payer address")
+		}
+		if !bytes.Equal(sponsor.messages[0], signingMessage(nil, sponsorAddr)) {
+			t.Error("fee payer did not sign over its own address")
+		}
+	})
+
+	multiAgent := NewMultiAgentTransaction(rawTxn, nil)
+	senderAuth, _ := multiAgent.Sign(sender)
+	if _, err := multiAgent.FeePayerSign(sponsorAddr, sponsor, senderAuth, nil); err == nil {
+		t.Error("FeePayerSign of a multi-agent transaction should fail")
+	}
+}
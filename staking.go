@@ -0,0 +1,114 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// DelegatorStake is a delegator's stake in a delegation pool, in octas,
+// as returned by 0x1::delegation_pool::get_stake.
+type DelegatorStake struct {
+	Active          uint64
+	Inactive        uint64
+	PendingInactive uint64
+}
+
+// Total returns the sum of active, inactive, and pending inactive stake.
+func (s DelegatorStake) Total() uint64 {
+	return s.Active + s.Inactive + s.PendingInactive
+}
+
+// GetDelegatorStake retrieves the stake of delegator in the delegation pool at
+// poolAddress by calling the 0x1::delegation_pool::get_stake view function.
+func (c *Client) GetDelegatorStake(ctx context.Context, poolAddress, delegator AccountAddress, opts ...RequestOption) (Response[DelegatorStake], error) {
+	result, err := c.View(ctx, ViewRequest{
+		Function:  "0x1::delegation_pool::get_stake",
+		Arguments: []interface{}{poolAddress, delegator},
+	}, opts...)
+	if err != nil {
+		return Response[DelegatorStake]{}, err
+	}
+
+	values, err := decodeViewU64s(result.Data, 3)
+	if err != nil {
+		return Response[DelegatorStake]{}, fmt.Errorf("failed to decode get_stake result: %w", err)
+	}
+	return Response[DelegatorStake]{
+		Data: DelegatorStake{
+			Active:          values[0],
+			Inactive:        values[1],
+			PendingInactive: values[2],
+		},
+		Metadata: result.Metadata,
+	}, nil
+}
+
+// ValidatorSet is the data of the 0x1::stake::ValidatorSet resource: the
+// validators of the current epoch and those joining or leaving at the next one.
+type ValidatorSet struct {
+	ConsensusScheme  uint8           `json:"consensus_scheme"`
+	ActiveValidators []ValidatorInfo `json:"active_validators"`
+
+	// PendingActive validators join the set at the start of the next epoch.
+	PendingActive []ValidatorInfo `json:"pending_active"`
+
+	// PendingInactive validators are still active and leave the set at the
+	// start of the next epoch.
+	PendingInactive []ValidatorInfo `json:"pending_inactive"`
+
+	TotalVotingPower  U128 `json:"total_voting_power"`
+	TotalJoiningPower U128 `json:"total_joining_power"`
+}
+
+// ValidatorInfo is a validator in a ValidatorSet. Address is the stake pool
+// address, which is also the delegation pool address for delegated pools.
+type ValidatorInfo struct {
+	Address     AccountAddress  `json:"addr"`
+	VotingPower JSONUint64      `json:"voting_power"`
+	Config      ValidatorConfig `json:"config"`
+}
+
+// ValidatorConfig is the on-chain configuration of a validator. Keys and
+// addresses are hex-encoded; the network addresses are BCS-encoded lists.
+type ValidatorConfig struct {
+	ConsensusPubkey   string     `json:"consensus_pubkey"`
+	NetworkAddresses  string     `json:"network_addresses"`
+	FullnodeAddresses string     `json:"fullnode_addresses"`
+	ValidatorIndex    JSONUint64 `json:"validator_index"`
+}
+
+// GetValidatorSet retrieves the 0x1::stake::ValidatorSet resource.
+func (c *Client) GetValidatorSet(ctx context.Context, opts ...RequestOption) (Response[ValidatorSet], error) {
+	resource, err := c.GetAccountResource(ctx, AccountOne, "0x1::stake::ValidatorSet", opts...)
+	if err != nil {
+		return Response[ValidatorSet]{}, err
+	}
+
+	var set ValidatorSet
+	if err := resource.Data.DecodeData(&set); err != nil {
+		return Response[ValidatorSet]{}, fmt.Errorf("failed to decode validator set: %w", err)
+	}
+	return Response[ValidatorSet]{Data: set, Metadata: resource.Metadata}, nil
+}
+
+// decodeViewU64s decodes a view function result of exactly n u64 values.
+func decodeViewU64s(result []json.RawMessage, n int) ([]uint64, error) {
+	if len(result) != n {
+		return nil, fmt.Errorf("expected %d return values, got %d", n, len(result))
+	}
+	values := make([]uint64, n)
+	for i, raw := range result {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, fmt.Errorf("return value %d: %w", i, err)
+		}
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("return value %d: %w", i, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
@@ -0,0 +1,61 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetDelegatorStake(t *testing.T) {
+	pool := MustParseAccountAddress("0x9001")
+	delegator := MustParseAccountAddress("0xa11ce")
+	var result atomic.Value
+	client, closeServer := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/view" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("ledger_version"); got != "55" {
+			t.Errorf("ledger_version = %q, want 55", got)
+		}
+		var req struct {
+			Function      string   `json:"function"`
+			TypeArguments []string `json:"type_arguments"`
+			Arguments     []string `json:"arguments"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode view request: %v", err)
+		}
+		if req.Function != "0x1::delegation_pool::get_stake" || len(req.TypeArguments) != 0 {
+			t.Errorf("view request = %+v, want 0x1::delegation_pool::get_stake without type arguments", req)
+		}
+		// get_stake(pool_address, delegator_address)
+		if len(req.Arguments) != 2 || req.Arguments[0] != pool.String() || req.Arguments[1] != delegator.String() {
+			t.Errorf("arguments = %v, want [%s %s]", req.Arguments, pool, delegator)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(result.Load().(string)))
+	})
+	defer closeServer()
+
+	ctx := context.Background()
+
+	// The result is (active, inactive, pending_inactive)
+	result.Store(`["1000","20","3"]`)
+	stake, err := client.GetDelegatorStake(ctx, pool, delegator, WithLedgerVersion(55))
+	if err != nil {
+		t.Fatalf("GetDelegatorStake error: %v", err)
+	}
+	want := DelegatorStake{Active: 1000, Inactive: 20, PendingInactive: 3}
+	if stake.Data != want || stake.Data.Total() != 1023 {
+		t.Errorf("GetDelegatorStake = %+v (total %d), want %+v (total 1023)", stake.Data, stake.Data.Total(), want)
+	}
+
+	for _, bad := range []string{`["1000","20"]`, `["1000","20","x"]`} {
+		result.Store(bad)
+		if _, err := client.GetDelegatorStake(ctx, pool, delegator, WithLedgerVersion(55)); err == nil {
+			t.Errorf("GetDelegatorStake with result %s should fail", bad)
+		}
+	}
+}
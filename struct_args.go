@@ -0,0 +1,139 @@
+package aptos
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/0xbe1/aptopher/bcs"
+)
+
+var (
+	bigIntType    = reflect.TypeOf((*big.Int)(nil))
+	marshalerType = reflect.TypeOf((*bcs.Marshaler)(nil)).Elem()
+)
+
+// ArgsFromStruct encodes the exported fields of a struct, in declaration
+// order, as separate entry function arguments. v may be a struct or a pointer
+// to one. Fields are encoded by type:
+//   - bool, uint8, uint16, uint32, uint64 (and uint): the matching Move type
+//   - U128, U256, and other bcs.Marshaler values: their MarshalBCS encoding
+//   - *big.Int: u128 or u256, selected with a `bcs:"u128"` or `bcs:"u256"` tag
+//   - AccountAddress: address
+//   - Object: Object<T>
+//   - string: String
+//   - []byte: vector<u8>
+//   - other slices and arrays: vector<T> of the element type
+//   - other pointers: Option<T>, with nil encoding None
+//
+// Fields tagged `bcs:"-"` and unexported fields are skipped. Signed integers,
+// floats, maps, and structs that do not implement bcs.Marshaler are rejected.
+//
+// Example:
+//
+//	type transferArgs struct {
+//		To     aptos.AccountAddress
+//		Amount uint64
+//		Memo   *string // Option<String>
+//	}
+//	args, err := aptos.ArgsFromStruct(transferArgs{To: recipient, Amount: 100})
+func ArgsFromStruct(v interface{}) ([][]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("nil struct pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct, got %T", v)
+	}
+
+	rt := rv.Type()
+	args := make([][]byte, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("bcs")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+
+		ser := bcs.NewSerializer()
+		encodeStructArg(ser, rv.Field(i), tag)
+		if err := ser.Error(); err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		args = append(args, ser.ToBytes())
+	}
+	return args, nil
+}
+
+// encodeStructArg serializes a single field value. The tag only applies to
+// *big.Int values, including those nested in vectors and options.
+// Errors are recorded on the serializer.
+func encodeStructArg(ser *bcs.Serializer, rv reflect.Value, tag string) {
+	if rv.Type() == bigIntType {
+		if rv.IsNil() {
+			ser.SetError(fmt.Errorf("nil *big.Int"))
+			return
+		}
+		switch tag {
+		case "u128":
+			ser.U128(rv.Interface().(*big.Int))
+		case "u256":
+			ser.U256(rv.Interface().(*big.Int))
+		default:
+			ser.SetError(fmt.Errorf(`*big.Int requires a bcs:"u128" or bcs:"u256" tag`))
+		}
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			ser.U8(0) // None
+			return
+		}
+		ser.U8(1) // Some
+		encodeStructArg(ser, rv.Elem(), tag)
+		return
+	case reflect.Interface:
+		if rv.IsNil() {
+			ser.SetError(fmt.Errorf("nil interface value"))
+			return
+		}
+		encodeStructArg(ser, rv.Elem(), tag)
+		return
+	}
+
+	if rv.Type().Implements(marshalerType) {
+		rv.Interface().(bcs.Marshaler).MarshalBCS(ser)
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		ser.Bool(rv.Bool())
+	case reflect.Uint8:
+		ser.U8(uint8(rv.Uint()))
+	case reflect.Uint16:
+		ser.U16(uint16(rv.Uint()))
+	case reflect.Uint32:
+		ser.U32(uint32(rv.Uint()))
+	case reflect.Uint, reflect.Uint64:
+		ser.U64(rv.Uint())
+	case reflect.String:
+		ser.String(rv.String())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			ser.Bytes(rv.Bytes())
+			return
+		}
+		ser.Uleb128(uint32(rv.Len()))
+		for i := 0; i < rv.Len(); i++ {
+			encodeStructArg(ser, rv.Index(i), tag)
+		}
+	default:
+		ser.SetError(fmt.Errorf("unsupported type %s", rv.Type()))
+	}
+}
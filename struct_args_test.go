@@ -0,0 +1,90 @@
+package aptos
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestArgsFromStruct(t *testing.T) {
+	memo := "hello"
+	type args struct {
+		To       AccountAddress
+		Amount   uint64
+		Flag     bool
+		Small    uint8
+		Big      *big.Int `bcs:"u128"`
+		Huge     U256
+		Name     string
+		Data     []byte
+		Amounts  []uint64
+		Memo     *string
+		Missing  *AccountAddress
+		Skipped  uint64 `bcs:"-"`
+		internal uint64
+	}
+
+	got, err := ArgsFromStruct(&args{
+		To:       AccountOne,
+		Amount:   100,
+		Flag:     true,
+		Small:    7,
+		Big:      big.NewInt(5),
+		Huge:     NewU256(6),
+		Name:     "coin",
+		Data:     []byte{1, 2},
+		Amounts:  []uint64{1, 2},
+		Memo:     &memo,
+		Skipped:  9,
+		internal: 9,
+	})
+	if err != nil {
+		t.Fatalf("ArgsFromStruct error: %v", err)
+	}
+
+	want := EntryFunctionArgs(
+		AddressArg(AccountOne),
+		U64Arg(100),
+		BoolArg(true),
+		U8Arg(7),
+		U128Arg(big.NewInt(5)),
+		U256Arg(big.NewInt(6)),
+		StringArg("coin"),
+		BytesArg([]byte{1, 2}),
+		VectorU64Arg([]uint64{1, 2}),
+		OptionStringArg(&memo),
+		OptionAddressArg(nil),
+	)
+	if len(got) != len(want) {
+		t.Fatalf("got %d args, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("arg %d = %x, want %x", i, got[i], want[i])
+		}
+	}
+}
+
+func TestArgsFromStructErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{"not a struct", uint64(1)},
+		{"nil pointer", (*struct{ A uint64 })(nil)},
+		{"signed integer", struct{ A int64 }{1}},
+		{"untagged big.Int", struct{ A *big.Int }{big.NewInt(1)}},
+		{"nil big.Int", struct {
+			A *big.Int `bcs:"u128"`
+		}{}},
+		{"map", struct{ A map[string]uint64 }{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ArgsFromStruct(tt.v); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
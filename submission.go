@@ -0,0 +1,108 @@
+package aptos
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xbe1/aptopher/bcs"
+)
+
+// submissionTracker remembers the transactions submitted through a client
+// until they expire, so that resubmitting one is caught before it reaches the
+// node. A nil *submissionTracker tracks nothing.
+type submissionTracker struct {
+	mu     sync.Mutex
+	byHash map[string]*trackedSubmission
+	bySeq  map[senderSequence]*trackedSubmission
+}
+
+// senderSequence identifies the slot a sequence-numbered transaction takes.
+type senderSequence struct {
+	sender         AccountAddress
+	sequenceNumber uint64
+}
+
+// trackedSubmission is a submitted transaction that may still be pending.
+type trackedSubmission struct {
+	hash         string
+	seq          *senderSequence // nil for orderless transactions
+	gasUnitPrice uint64
+	expiresAt    time.Time
+}
+
+func newSubmissionTracker() *submissionTracker {
+	return &submissionTracker{
+		byHash: make(map[string]*trackedSubmission),
+		bySeq:  make(map[senderSequence]*trackedSubmission),
+	}
+}
+
+// begin records a submission of signedTxnBytes, or returns an error wrapping
+// ErrDuplicateSubmission if the same transaction, or another one with the
+// same sender and sequence number and no higher gas unit price, was
+// submitted and has not expired. A higher gas unit price is allowed, since the
+// mempool accepts it as a replacement. Bytes that do not decode as a signed
+// transaction are not tracked; the node rejects them.
+func (t *submissionTracker) begin(signedTxnBytes []byte) (*trackedSubmission, error) {
+	if t == nil {
+		return nil, nil
+	}
+	var txn SignedTransaction
+	if bcs.Deserialize(signedTxnBytes, &txn) != nil {
+		return nil, nil
+	}
+	raw := txn.RawTxn
+	sub := &trackedSubmission{
+		hash:         signedTransactionHash(signedTxnBytes),
+		gasUnitPrice: raw.GasUnitPrice,
+		expiresAt:    time.Unix(int64(raw.ExpirationTimestampSecs), 0),
+	}
+	if raw.SequenceNumber != OrderlessPlaceholderSequenceNum {
+		sub.seq = &senderSequence{sender: raw.Sender, sequenceNumber: raw.SequenceNumber}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prune(time.Now())
+	if _, ok := t.byHash[sub.hash]; ok {
+		return nil, fmt.Errorf("%w: transaction %s was already submitted", ErrDuplicateSubmission, sub.hash)
+	}
+	if sub.seq != nil {
+		if prev, ok := t.bySeq[*sub.seq]; ok && sub.gasUnitPrice <= prev.gasUnitPrice {
+			return nil, fmt.Errorf("%w: sequence number %d of %s was already used by transaction %s",
+				ErrDuplicateSubmission, raw.SequenceNumber, raw.Sender, prev.hash)
+		}
+		t.bySeq[*sub.seq] = sub
+	}
+	t.byHash[sub.hash] = sub
+	return sub, nil
+}
+
+// fail forgets a submission that the node did not accept.
+func (t *submissionTracker) fail(sub *trackedSubmission) {
+	if t == nil || sub == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.remove(sub)
+}
+
+// prune forgets submissions that expired before now, which can no longer be
+// committed. It must be called with t.mu held.
+func (t *submissionTracker) prune(now time.Time) {
+	for _, sub := range t.byHash {
+		if sub.expiresAt.Before(now) {
+			t.remove(sub)
+		}
+	}
+}
+
+// remove forgets a submission. It must be called with t.mu held.
+func (t *submissionTracker) remove(sub *trackedSubmission) {
+	delete(t.byHash, sub.hash)
+	if sub.seq != nil && t.bySeq[*sub.seq] == sub {
+		delete(t.bySeq, *sub.seq)
+	}
+}
@@ -1,30 +1,33 @@
 package aptos
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Transaction represents an Aptos transaction.
 // Use the Type field to determine which specific transaction type this is.
 type Transaction struct {
 	Type                    string          `json:"type"`
 	Hash                    string          `json:"hash"`
-	Version                 string          `json:"version,omitempty"`
+	Version                 JSONUint64      `json:"version,omitempty"`
 	StateChangeHash         string          `json:"state_change_hash,omitempty"`
 	EventRootHash           string          `json:"event_root_hash,omitempty"`
 	StateCheckpointHash     *string         `json:"state_checkpoint_hash,omitempty"`
-	GasUsed                 string          `json:"gas_used,omitempty"`
+	GasUsed                 JSONUint64      `json:"gas_used,omitempty"`
 	Success                 bool            `json:"success,omitempty"`
 	VMStatus                string          `json:"vm_status,omitempty"`
 	AccumulatorRootHash     string          `json:"accumulator_root_hash,omitempty"`
 	Changes                 json.RawMessage `json:"changes,omitempty"`
 	Sender                  string          `json:"sender,omitempty"`
-	SequenceNumber          string          `json:"sequence_number,omitempty"`
-	MaxGasAmount            string          `json:"max_gas_amount,omitempty"`
-	GasUnitPrice            string          `json:"gas_unit_price,omitempty"`
-	ExpirationTimestampSecs string          `json:"expiration_timestamp_secs,omitempty"`
+	SequenceNumber          JSONUint64      `json:"sequence_number,omitempty"`
+	MaxGasAmount            JSONUint64      `json:"max_gas_amount,omitempty"`
+	GasUnitPrice            JSONUint64      `json:"gas_unit_price,omitempty"`
+	ExpirationTimestampSecs JSONUint64      `json:"expiration_timestamp_secs,omitempty"`
 	Payload                 json.RawMessage `json:"payload,omitempty"`
 	Signature               json.RawMessage `json:"signature,omitempty"`
 	Events                  []Event         `json:"events,omitempty"`
-	Timestamp               string          `json:"timestamp,omitempty"`
+	Timestamp               JSONUint64      `json:"timestamp,omitempty"`
 }
 
 // Transaction types
@@ -50,47 +53,80 @@ func (t *Transaction) IsUserTransaction() bool {
 
 // VersionUint64 returns the version as uint64.
 func (t *Transaction) VersionUint64() uint64 {
-	return parseStringToUint64(t.Version)
+	return t.Version.Uint64()
 }
 
 // GasUsedUint64 returns the gas used as uint64.
 func (t *Transaction) GasUsedUint64() uint64 {
-	return parseStringToUint64(t.GasUsed)
+	return t.GasUsed.Uint64()
+}
+
+// Time returns the commit timestamp of the transaction.
+// Returns the zero time for pending transactions, which have no timestamp.
+func (t *Transaction) Time() time.Time {
+	return microsTime(t.Timestamp)
+}
+
+// IsInsufficientBalance returns true if the transaction failed because the
+// sender could not pay for its gas or its transfers.
+func (t *Transaction) IsInsufficientBalance() bool {
+	return !t.Success && isInsufficientBalanceStatus(t.VMStatus)
 }
 
 // PendingTransaction represents a transaction that has been submitted but not yet committed.
 type PendingTransaction struct {
 	Hash                    string          `json:"hash"`
 	Sender                  string          `json:"sender"`
-	SequenceNumber          string          `json:"sequence_number"`
-	MaxGasAmount            string          `json:"max_gas_amount"`
-	GasUnitPrice            string          `json:"gas_unit_price"`
-	ExpirationTimestampSecs string          `json:"expiration_timestamp_secs"`
+	SequenceNumber          JSONUint64      `json:"sequence_number"`
+	MaxGasAmount            JSONUint64      `json:"max_gas_amount"`
+	GasUnitPrice            JSONUint64      `json:"gas_unit_price"`
+	ExpirationTimestampSecs JSONUint64      `json:"expiration_timestamp_secs"`
 	Payload                 json.RawMessage `json:"payload"`
 	Signature               json.RawMessage `json:"signature"`
 }
 
 // UserTransaction represents a committed user transaction.
 type UserTransaction struct {
-	Version                 string          `json:"version"`
+	Version                 JSONUint64      `json:"version"`
 	Hash                    string          `json:"hash"`
 	StateChangeHash         string          `json:"state_change_hash"`
 	EventRootHash           string          `json:"event_root_hash"`
 	StateCheckpointHash     *string         `json:"state_checkpoint_hash"`
-	GasUsed                 string          `json:"gas_used"`
+	GasUsed                 JSONUint64      `json:"gas_used"`
 	Success                 bool            `json:"success"`
 	VMStatus                string          `json:"vm_status"`
 	AccumulatorRootHash     string          `json:"accumulator_root_hash"`
 	Changes                 json.RawMessage `json:"changes"`
 	Sender                  string          `json:"sender"`
-	SequenceNumber          string          `json:"sequence_number"`
-	MaxGasAmount            string          `json:"max_gas_amount"`
-	GasUnitPrice            string          `json:"gas_unit_price"`
-	ExpirationTimestampSecs string          `json:"expiration_timestamp_secs"`
+	SequenceNumber          JSONUint64      `json:"sequence_number"`
+	MaxGasAmount            JSONUint64      `json:"max_gas_amount"`
+	GasUnitPrice            JSONUint64      `json:"gas_unit_price"`
+	ExpirationTimestampSecs JSONUint64      `json:"expiration_timestamp_secs"`
 	Payload                 json.RawMessage `json:"payload"`
 	Signature               json.RawMessage `json:"signature"`
 	Events                  []Event         `json:"events"`
-	Timestamp               string          `json:"timestamp"`
+	Timestamp               JSONUint64      `json:"timestamp"`
+}
+
+// Time returns the commit timestamp of the transaction.
+func (t *UserTransaction) Time() time.Time {
+	return microsTime(t.Timestamp)
+}
+
+// IsInsufficientBalance returns true if the transaction failed because the
+// sender could not pay for its gas or its transfers, so callers can show
+// "not enough funds" instead of the raw VM status.
+func (t *UserTransaction) IsInsufficientBalance() bool {
+	return !t.Success && isInsufficientBalanceStatus(t.VMStatus)
+}
+
+// microsTime converts a microseconds-since-epoch timestamp into a time.Time.
+// Returns the zero time if us is zero (e.g. absent for pending transactions).
+func microsTime(us JSONUint64) time.Time {
+	if us == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(int64(us))
 }
 
 // ViewRequest represents a request to execute a view function.
@@ -100,6 +136,39 @@ type ViewRequest struct {
 	Arguments     []interface{} `json:"arguments"`
 }
 
+// EntryFunctionPayloadType is the type of an entry function payload in the
+// REST API's JSON transaction format.
+const EntryFunctionPayloadType = "entry_function_payload"
+
+// EntryFunctionPayloadJSON is an entry function payload in the REST API's
+// JSON format, as used by JSON transaction submission and simulation and by
+// /transactions/encode_submission. Arguments are in the JSON forms listed for
+// EntryFunctionArgsToJSON.
+type EntryFunctionPayloadJSON struct {
+	Type          string        `json:"type"`
+	Function      string        `json:"function"`
+	TypeArguments []string      `json:"type_arguments"`
+	Arguments     []interface{} `json:"arguments"`
+}
+
+// NewEntryFunctionPayloadJSON returns the JSON payload that calls function in
+// module with the given type arguments and JSON-form arguments.
+func NewEntryFunctionPayloadJSON(module ModuleId, function string, typeArgs []TypeTag, args []interface{}) EntryFunctionPayloadJSON {
+	typeArguments := make([]string, len(typeArgs))
+	for i, tag := range typeArgs {
+		typeArguments[i] = tag.String()
+	}
+	if args == nil {
+		args = []interface{}{}
+	}
+	return EntryFunctionPayloadJSON{
+		Type:          EntryFunctionPayloadType,
+		Function:      module.String() + "::" + function,
+		TypeArguments: typeArguments,
+		Arguments:     args,
+	}
+}
+
 // TableItemRequest represents a request to get a table item.
 type TableItemRequest struct {
 	KeyType   string      `json:"key_type"`
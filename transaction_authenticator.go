@@ -1,6 +1,9 @@
 package aptos
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/0xbe1/aptopher/bcs"
 	"github.com/0xbe1/aptopher/crypto"
 )
@@ -32,16 +35,281 @@ type TransactionAuthenticator struct {
 }
 
 // MarshalBCS implements bcs.Marshaler.
+// For SingleSender, Auth is encoded as an AccountAuthenticator, prefixed with
+// its variant.
 func (a TransactionAuthenticator) MarshalBCS(ser *bcs.Serializer) {
 	ser.Uleb128(uint32(a.Variant))
+	if a.Variant == TransactionAuthenticatorSingleSender {
+		marshalAccountAuthenticator(ser, a.Auth)
+		return
+	}
 	a.Auth.MarshalBCS(ser)
 }
 
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (a *TransactionAuthenticator) UnmarshalBCS(des *bcs.Deserializer) {
+	a.Variant = TransactionAuthenticatorVariant(des.Uleb128())
+	if des.Error() != nil {
+		return
+	}
+	switch a.Variant {
+	case TransactionAuthenticatorEd25519:
+		var auth AccountAuthenticatorEd25519
+		auth.UnmarshalBCS(des)
+		a.Auth = &auth
+	case TransactionAuthenticatorMultiEd25519:
+		var auth AccountAuthenticatorMultiEd25519
+		auth.UnmarshalBCS(des)
+		a.Auth = &auth
+	case TransactionAuthenticatorMultiAgent:
+		var auth MultiAgentAuthenticator
+		auth.UnmarshalBCS(des)
+		a.Auth = &auth
+	case TransactionAuthenticatorFeePayer:
+		var auth FeePayerAuthenticator
+		auth.UnmarshalBCS(des)
+		a.Auth = &auth
+	case TransactionAuthenticatorSingleSender:
+		a.Auth = unmarshalAccountAuthenticator(des)
+	default:
+		des.SetError(fmt.Errorf("unsupported transaction authenticator variant: %d", a.Variant))
+	}
+}
+
+// validate checks that the variant is supported and that Auth has the
+// matching type with well-formed keys and signatures.
+func (a TransactionAuthenticator) validate() error {
+	switch a.Variant {
+	case TransactionAuthenticatorEd25519:
+		switch a.Auth.(type) {
+		case AccountAuthenticatorEd25519, *AccountAuthenticatorEd25519:
+			return nil
+		}
+	case TransactionAuthenticatorMultiEd25519:
+		switch auth := a.Auth.(type) {
+		case AccountAuthenticatorMultiEd25519:
+			return auth.validate()
+		case *AccountAuthenticatorMultiEd25519:
+			return auth.validate()
+		}
+	case TransactionAuthenticatorSingleSender:
+		return validateAccountAuthenticator(a.Auth)
+	case TransactionAuthenticatorMultiAgent:
+		switch auth := a.Auth.(type) {
+		case MultiAgentAuthenticator:
+			return validateSigners(auth.Sender, auth.SecondarySignerAddresses, auth.SecondarySigners)
+		case *MultiAgentAuthenticator:
+			return validateSigners(auth.Sender, auth.SecondarySignerAddresses, auth.SecondarySigners)
+		}
+	case TransactionAuthenticatorFeePayer:
+		var auth *FeePayerAuthenticator
+		switch feePayer := a.Auth.(type) {
+		case FeePayerAuthenticator:
+			auth = &feePayer
+		case *FeePayerAuthenticator:
+			auth = feePayer
+		}
+		if auth != nil {
+			if err := validateSigners(auth.Sender, auth.SecondarySignerAddresses, auth.SecondarySigners); err != nil {
+				return err
+			}
+			if err := validateAccountAuthenticator(auth.FeePayer); err != nil {
+				return fmt.Errorf("fee payer: %w", err)
+			}
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported transaction authenticator variant: %d", a.Variant)
+	}
+	return fmt.Errorf("authenticator type %T does not match variant %d", a.Auth, a.Variant)
+}
+
+// validateSigners validates the sender and secondary signers of a
+// multi-agent or fee-payer authenticator.
+func validateSigners(sender AccountAuthenticatorImpl, addresses []AccountAddress, signers []AccountAuthenticatorImpl) error {
+	if err := validateAccountAuthenticator(sender); err != nil {
+		return fmt.Errorf("sender: %w", err)
+	}
+	if len(addresses) != len(signers) {
+		return fmt.Errorf("%d secondary signer addresses for %d secondary signers", len(addresses), len(signers))
+	}
+	for i, signer := range signers {
+		if err := validateAccountAuthenticator(signer); err != nil {
+			return fmt.Errorf("secondary signer %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateAccountAuthenticator checks that an account authenticator is of a
+// supported type and that its key and signature lengths match their schemes.
+func validateAccountAuthenticator(auth AccountAuthenticatorImpl) error {
+	switch a := auth.(type) {
+	case AccountAuthenticatorEd25519, *AccountAuthenticatorEd25519:
+		return nil // Fixed-size arrays
+	case AccountAuthenticatorSingleKey:
+		return a.validate()
+	case *AccountAuthenticatorSingleKey:
+		return a.validate()
+	case AccountAuthenticatorMultiEd25519:
+		return a.validate()
+	case *AccountAuthenticatorMultiEd25519:
+		return a.validate()
+	case AccountAuthenticatorMultiKey:
+		return a.validate()
+	case *AccountAuthenticatorMultiKey:
+		return a.validate()
+	case nil:
+		return fmt.Errorf("missing account authenticator")
+	default:
+		return fmt.Errorf("unsupported account authenticator type: %T", auth)
+	}
+}
+
+// validate checks the key and signature lengths against their schemes.
+func (a AccountAuthenticatorSingleKey) validate() error {
+	if a.PublicKey.Variant != a.Signature.Variant {
+		return fmt.Errorf("public key scheme %d does not match signature scheme %d", a.PublicKey.Variant, a.Signature.Variant)
+	}
+	if err := validateAnyPublicKey(a.PublicKey); err != nil {
+		return err
+	}
+	switch a.Signature.Variant {
+	case crypto.Ed25519Scheme:
+		if len(a.Signature.Signature) != crypto.Ed25519SignatureLength {
+			return fmt.Errorf("invalid Ed25519 signature length: %d", len(a.Signature.Signature))
+		}
+	case crypto.Secp256k1Scheme:
+		if len(a.Signature.Signature) != crypto.Secp256k1SignatureLength {
+			return fmt.Errorf("invalid secp256k1 signature length: %d", len(a.Signature.Signature))
+		}
+	}
+	return nil
+}
+
+// validateAnyPublicKey checks the key length against its scheme.
+func validateAnyPublicKey(k AnyPublicKey) error {
+	switch k.Variant {
+	case crypto.Ed25519Scheme:
+		if len(k.PublicKey) != crypto.Ed25519PublicKeyLength {
+			return fmt.Errorf("invalid Ed25519 public key length: %d", len(k.PublicKey))
+		}
+	case crypto.Secp256k1Scheme:
+		// The node only accepts uncompressed secp256k1 keys
+		if n := len(k.PublicKey); n != crypto.Secp256k1UncompressedPublicKeyLength {
+			return fmt.Errorf("invalid secp256k1 public key length: %d, want %d (uncompressed)", n, crypto.Secp256k1UncompressedPublicKeyLength)
+		}
+	default:
+		return fmt.Errorf("unsupported public key scheme: %d", k.Variant)
+	}
+	return nil
+}
+
+// AccountAuthenticatorVariant represents the type of account authenticator.
+type AccountAuthenticatorVariant uint8
+
+const (
+	// AccountAuthenticatorVariantEd25519 is a single Ed25519 signature.
+	AccountAuthenticatorVariantEd25519 AccountAuthenticatorVariant = 0
+
+	// AccountAuthenticatorVariantMultiEd25519 is a multi-Ed25519 signature.
+	AccountAuthenticatorVariantMultiEd25519 AccountAuthenticatorVariant = 1
+
+	// AccountAuthenticatorVariantSingleKey is a single AnyPublicKey signature.
+	AccountAuthenticatorVariantSingleKey AccountAuthenticatorVariant = 2
+
+	// AccountAuthenticatorVariantMultiKey is a k-of-n AnyPublicKey signature.
+	AccountAuthenticatorVariantMultiKey AccountAuthenticatorVariant = 3
+)
+
 // AccountAuthenticatorImpl is implemented by all account authenticator types.
 type AccountAuthenticatorImpl interface {
 	bcs.Marshaler
 }
 
+// marshalAccountAuthenticator serializes auth as an AccountAuthenticator,
+// prefixed with its variant.
+func marshalAccountAuthenticator(ser *bcs.Serializer, auth AccountAuthenticatorImpl) {
+	switch auth.(type) {
+	case AccountAuthenticatorEd25519, *AccountAuthenticatorEd25519:
+		ser.Uleb128(uint32(AccountAuthenticatorVariantEd25519))
+	case AccountAuthenticatorMultiEd25519, *AccountAuthenticatorMultiEd25519:
+		ser.Uleb128(uint32(AccountAuthenticatorVariantMultiEd25519))
+	case AccountAuthenticatorSingleKey, *AccountAuthenticatorSingleKey:
+		ser.Uleb128(uint32(AccountAuthenticatorVariantSingleKey))
+	case AccountAuthenticatorMultiKey, *AccountAuthenticatorMultiKey:
+		ser.Uleb128(uint32(AccountAuthenticatorVariantMultiKey))
+	default:
+		ser.SetError(fmt.Errorf("unsupported account authenticator type: %T", auth))
+		return
+	}
+	auth.MarshalBCS(ser)
+}
+
+// unmarshalAccountAuthenticator deserializes an AccountAuthenticator.
+func unmarshalAccountAuthenticator(des *bcs.Deserializer) AccountAuthenticatorImpl {
+	variant := AccountAuthenticatorVariant(des.Uleb128())
+	if des.Error() != nil {
+		return nil
+	}
+	switch variant {
+	case AccountAuthenticatorVariantEd25519:
+		var auth AccountAuthenticatorEd25519
+		auth.UnmarshalBCS(des)
+		return &auth
+	case AccountAuthenticatorVariantMultiEd25519:
+		var auth AccountAuthenticatorMultiEd25519
+		auth.UnmarshalBCS(des)
+		return &auth
+	case AccountAuthenticatorVariantSingleKey:
+		var auth AccountAuthenticatorSingleKey
+		auth.UnmarshalBCS(des)
+		return &auth
+	case AccountAuthenticatorVariantMultiKey:
+		var auth AccountAuthenticatorMultiKey
+		auth.UnmarshalBCS(des)
+		return &auth
+	default:
+		des.SetError(fmt.Errorf("unsupported account authenticator variant: %d", variant))
+		return nil
+	}
+}
+
+// unmarshalAccountAuthenticators deserializes a vector of AccountAuthenticators.
+func unmarshalAccountAuthenticators(des *bcs.Deserializer) []AccountAuthenticatorImpl {
+	length := des.Uleb128()
+	if des.Error() != nil {
+		return nil
+	}
+	auths := make([]AccountAuthenticatorImpl, 0, min(int(length), des.Remaining()))
+	for i := uint32(0); i < length; i++ {
+		auth := unmarshalAccountAuthenticator(des)
+		if des.Error() != nil {
+			return nil
+		}
+		auths = append(auths, auth)
+	}
+	return auths
+}
+
+// unmarshalAddresses deserializes a vector of account addresses.
+func unmarshalAddresses(des *bcs.Deserializer) []AccountAddress {
+	length := des.Uleb128()
+	if des.Error() != nil {
+		return nil
+	}
+	addrs := make([]AccountAddress, 0, min(int(length), des.Remaining()/AccountAddressLength))
+	for i := uint32(0); i < length; i++ {
+		var addr AccountAddress
+		addr.UnmarshalBCS(des)
+		if des.Error() != nil {
+			return nil
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
 // AccountAuthenticatorSingleKey is the modern single-key authenticator.
 type AccountAuthenticatorSingleKey struct {
 	PublicKey AnyPublicKey
@@ -54,6 +322,12 @@ func (a AccountAuthenticatorSingleKey) MarshalBCS(ser *bcs.Serializer) {
 	a.Signature.MarshalBCS(ser)
 }
 
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (a *AccountAuthenticatorSingleKey) UnmarshalBCS(des *bcs.Deserializer) {
+	a.PublicKey.UnmarshalBCS(des)
+	a.Signature.UnmarshalBCS(des)
+}
+
 // AnyPublicKey represents a public key of any supported type.
 type AnyPublicKey struct {
 	Variant   crypto.SignatureScheme
@@ -61,16 +335,25 @@ type AnyPublicKey struct {
 }
 
 // MarshalBCS implements bcs.Marshaler.
+// The key bytes are length-prefixed, matching the node's encoding.
 func (k AnyPublicKey) MarshalBCS(ser *bcs.Serializer) {
 	ser.Uleb128(uint32(k.Variant))
-	switch k.Variant {
-	case crypto.Ed25519Scheme:
-		ser.FixedBytes(k.PublicKey) // 32 bytes
-	case crypto.Secp256k1Scheme:
-		ser.FixedBytes(k.PublicKey) // 33 bytes (compressed)
-	default:
-		ser.Bytes(k.PublicKey) // Unknown, use length-prefixed
+	ser.Bytes(k.PublicKey)
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+// Only keys encoded as plain bytes (variants 0-2) are supported; keyless
+// public keys are rejected.
+func (k *AnyPublicKey) UnmarshalBCS(des *bcs.Deserializer) {
+	k.Variant = crypto.SignatureScheme(des.Uleb128())
+	if des.Error() != nil {
+		return
 	}
+	if k.Variant > 2 {
+		des.SetError(fmt.Errorf("unsupported public key variant: %d", k.Variant))
+		return
+	}
+	k.PublicKey = des.Bytes()
 }
 
 // AnySignature represents a signature of any supported type.
@@ -80,16 +363,226 @@ type AnySignature struct {
 }
 
 // MarshalBCS implements bcs.Marshaler.
+// The signature bytes are length-prefixed, matching the node's encoding.
 func (s AnySignature) MarshalBCS(ser *bcs.Serializer) {
 	ser.Uleb128(uint32(s.Variant))
-	switch s.Variant {
-	case crypto.Ed25519Scheme:
-		ser.FixedBytes(s.Signature) // 64 bytes
-	case crypto.Secp256k1Scheme:
-		ser.FixedBytes(s.Signature) // 64 bytes
-	default:
-		ser.Bytes(s.Signature) // Unknown, use length-prefixed
+	ser.Bytes(s.Signature)
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+// Only signatures encoded as plain bytes (variants 0-1) are supported;
+// WebAuthn and keyless signatures are rejected.
+func (s *AnySignature) UnmarshalBCS(des *bcs.Deserializer) {
+	s.Variant = crypto.SignatureScheme(des.Uleb128())
+	if des.Error() != nil {
+		return
+	}
+	if s.Variant > 1 {
+		des.SetError(fmt.Errorf("unsupported signature variant: %d", s.Variant))
+		return
+	}
+	s.Signature = des.Bytes()
+}
+
+// MultiKeyMaxKeys is the maximum number of keys in a MultiKey.
+const MultiKeyMaxKeys = 32
+
+// MultiKey is a k-of-n multi-signature key (AIP-55) whose keys may be of
+// different schemes, such as Ed25519 and secp256k1. The order of the keys is
+// significant: it determines the auth key and the signer indices.
+type MultiKey struct {
+	PublicKeys         []AnyPublicKey
+	SignaturesRequired uint8
+}
+
+// NewMultiKey creates a MultiKey that requires signaturesRequired signatures
+// from the given keys. Secp256k1 keys may be compressed; they are stored
+// uncompressed, as the node expects.
+func NewMultiKey(publicKeys []AnyPublicKey, signaturesRequired uint8) (*MultiKey, error) {
+	k := &MultiKey{PublicKeys: make([]AnyPublicKey, len(publicKeys)), SignaturesRequired: signaturesRequired}
+	for i, publicKey := range publicKeys {
+		if publicKey.Variant == crypto.Secp256k1Scheme {
+			uncompressed, err := crypto.UncompressSecp256k1PubKey(publicKey.PublicKey)
+			if err != nil {
+				return nil, fmt.Errorf("public key %d: %w", i, err)
+			}
+			publicKey.PublicKey = uncompressed
+		}
+		k.PublicKeys[i] = publicKey
 	}
+	if err := k.validate(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// validate checks the number of keys, their lengths, and the threshold.
+func (k MultiKey) validate() error {
+	n := len(k.PublicKeys)
+	if n == 0 || n > MultiKeyMaxKeys {
+		return fmt.Errorf("invalid number of multi-key public keys: %d, want 1 to %d", n, MultiKeyMaxKeys)
+	}
+	if k.SignaturesRequired == 0 || int(k.SignaturesRequired) > n {
+		return fmt.Errorf("invalid multi-key threshold: %d of %d keys", k.SignaturesRequired, n)
+	}
+	for i, publicKey := range k.PublicKeys {
+		if err := validateAnyPublicKey(publicKey); err != nil {
+			return fmt.Errorf("public key %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// AuthKey returns the authentication key of an account controlled by this
+// key: SHA3-256(bcs(MultiKey) || MultiKeyAuthScheme).
+func (k MultiKey) AuthKey() ([32]byte, error) {
+	keyBytes, err := bcs.Serialize(k)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return crypto.Sha3256(append(keyBytes, crypto.MultiKeyAuthScheme)), nil
+}
+
+// MarshalBCS implements bcs.Marshaler.
+func (k MultiKey) MarshalBCS(ser *bcs.Serializer) {
+	ser.Uleb128(uint32(len(k.PublicKeys)))
+	for _, publicKey := range k.PublicKeys {
+		publicKey.MarshalBCS(ser)
+	}
+	ser.U8(k.SignaturesRequired)
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (k *MultiKey) UnmarshalBCS(des *bcs.Deserializer) {
+	length := des.Uleb128()
+	if des.Error() != nil {
+		return
+	}
+	if length > MultiKeyMaxKeys {
+		des.SetError(fmt.Errorf("too many multi-key public keys: %d", length))
+		return
+	}
+	k.PublicKeys = make([]AnyPublicKey, length)
+	for i := range k.PublicKeys {
+		k.PublicKeys[i].UnmarshalBCS(des)
+	}
+	k.SignaturesRequired = des.U8()
+}
+
+// MultiKeySignature is a signature by some of the keys of a MultiKey. Bitmap
+// marks which keys signed, with the same layout as MultiEd25519 signatures,
+// and Signatures holds their signatures in key order.
+type MultiKeySignature struct {
+	Signatures []AnySignature
+	Bitmap     [crypto.MultiEd25519BitmapLength]byte
+}
+
+// NewMultiKeySignature creates a multi-key signature from the signatures of
+// the keys at the given indices of the MultiKey.
+func NewMultiKeySignature(signatures map[int]AnySignature) (*MultiKeySignature, error) {
+	indices := make([]int, 0, len(signatures))
+	for i := range signatures {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	bitmap, err := crypto.MultiEd25519Bitmap(indices)
+	if err != nil {
+		return nil, err
+	}
+	s := &MultiKeySignature{Bitmap: bitmap}
+	for _, i := range indices {
+		s.Signatures = append(s.Signatures, signatures[i])
+	}
+	return s, nil
+}
+
+// SignerIndices returns the indices of the keys marked in the bitmap, in
+// ascending order.
+func (s MultiKeySignature) SignerIndices() []int {
+	return (&crypto.MultiEd25519Signature{Bitmap: s.Bitmap}).SignerIndices()
+}
+
+// MarshalBCS implements bcs.Marshaler.
+// The bitmap is encoded as length-prefixed bytes.
+func (s MultiKeySignature) MarshalBCS(ser *bcs.Serializer) {
+	ser.Uleb128(uint32(len(s.Signatures)))
+	for _, signature := range s.Signatures {
+		signature.MarshalBCS(ser)
+	}
+	ser.Bytes(s.Bitmap[:])
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+// Bitmaps shorter than four bytes, as the node produces for fewer keys, are
+// padded with zeros.
+func (s *MultiKeySignature) UnmarshalBCS(des *bcs.Deserializer) {
+	length := des.Uleb128()
+	if des.Error() != nil {
+		return
+	}
+	if length > MultiKeyMaxKeys {
+		des.SetError(fmt.Errorf("too many multi-key signatures: %d", length))
+		return
+	}
+	s.Signatures = make([]AnySignature, length)
+	for i := range s.Signatures {
+		s.Signatures[i].UnmarshalBCS(des)
+	}
+	bitmap := des.BytesNoCopy()
+	if des.Error() != nil {
+		return
+	}
+	if len(bitmap) > len(s.Bitmap) {
+		des.SetError(fmt.Errorf("invalid multi-key bitmap length: %d", len(bitmap)))
+		return
+	}
+	s.Bitmap = [crypto.MultiEd25519BitmapLength]byte{}
+	copy(s.Bitmap[:], bitmap)
+}
+
+// AccountAuthenticatorMultiKey is the k-of-n multi-key authenticator
+// (AIP-55), sent in a TransactionAuthenticatorSingleSender.
+type AccountAuthenticatorMultiKey struct {
+	PublicKey MultiKey
+	Signature MultiKeySignature
+}
+
+// MarshalBCS implements bcs.Marshaler.
+func (a AccountAuthenticatorMultiKey) MarshalBCS(ser *bcs.Serializer) {
+	a.PublicKey.MarshalBCS(ser)
+	a.Signature.MarshalBCS(ser)
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (a *AccountAuthenticatorMultiKey) UnmarshalBCS(des *bcs.Deserializer) {
+	a.PublicKey.UnmarshalBCS(des)
+	a.Signature.UnmarshalBCS(des)
+}
+
+// validate checks the key, and that the signature carries at least the
+// threshold of signatures, each well-formed for the scheme of its key.
+func (a AccountAuthenticatorMultiKey) validate() error {
+	if err := a.PublicKey.validate(); err != nil {
+		return err
+	}
+	indices := a.Signature.SignerIndices()
+	if len(indices) != len(a.Signature.Signatures) {
+		return fmt.Errorf("multi-key bitmap marks %d signers for %d signatures", len(indices), len(a.Signature.Signatures))
+	}
+	if len(indices) < int(a.PublicKey.SignaturesRequired) {
+		return fmt.Errorf("%d multi-key signatures, below the threshold of %d", len(indices), a.PublicKey.SignaturesRequired)
+	}
+	for j, i := range indices {
+		if i >= len(a.PublicKey.PublicKeys) {
+			return fmt.Errorf("multi-key signer index %d out of range for %d keys", i, len(a.PublicKey.PublicKeys))
+		}
+		single := AccountAuthenticatorSingleKey{PublicKey: a.PublicKey.PublicKeys[i], Signature: a.Signature.Signatures[j]}
+		if err := single.validate(); err != nil {
+			return fmt.Errorf("signer %d: %w", i, err)
+		}
+	}
+	return nil
 }
 
 // AccountAuthenticatorEd25519 is the legacy Ed25519 authenticator.
@@ -100,8 +593,90 @@ type AccountAuthenticatorEd25519 struct {
 
 // MarshalBCS implements bcs.Marshaler.
 func (a AccountAuthenticatorEd25519) MarshalBCS(ser *bcs.Serializer) {
-	ser.FixedBytes(a.PublicKey[:])
-	ser.FixedBytes(a.Signature[:])
+	ser.Bytes(a.PublicKey[:])
+	ser.Bytes(a.Signature[:])
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (a *AccountAuthenticatorEd25519) UnmarshalBCS(des *bcs.Deserializer) {
+	unmarshalFixedLengthBytes(des, a.PublicKey[:], "Ed25519 public key")
+	unmarshalFixedLengthBytes(des, a.Signature[:], "Ed25519 signature")
+}
+
+// unmarshalFixedLengthBytes reads length-prefixed bytes into dst, failing if
+// the length does not match.
+func unmarshalFixedLengthBytes(des *bcs.Deserializer, dst []byte, name string) {
+	data := des.BytesNoCopy()
+	if des.Error() != nil {
+		return
+	}
+	if len(data) != len(dst) {
+		des.SetError(fmt.Errorf("invalid %s length: got %d, want %d", name, len(data), len(dst)))
+		return
+	}
+	copy(dst, data)
+}
+
+// AccountAuthenticatorMultiEd25519 is the legacy k-of-n Ed25519
+// authenticator. The key and signature are each encoded as length-prefixed
+// bytes, in the forms returned by their Bytes methods.
+type AccountAuthenticatorMultiEd25519 struct {
+	PublicKey crypto.MultiEd25519PublicKey
+	Signature crypto.MultiEd25519Signature
+}
+
+// MarshalBCS implements bcs.Marshaler.
+func (a AccountAuthenticatorMultiEd25519) MarshalBCS(ser *bcs.Serializer) {
+	ser.Bytes(a.PublicKey.Bytes())
+	ser.Bytes(a.Signature.Bytes())
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (a *AccountAuthenticatorMultiEd25519) UnmarshalBCS(des *bcs.Deserializer) {
+	publicKeyBytes := des.BytesNoCopy()
+	if des.Error() != nil {
+		return
+	}
+	publicKey, err := crypto.ParseMultiEd25519PublicKey(publicKeyBytes)
+	if err != nil {
+		des.SetError(err)
+		return
+	}
+	signatureBytes := des.BytesNoCopy()
+	if des.Error() != nil {
+		return
+	}
+	signature, err := crypto.ParseMultiEd25519Signature(signatureBytes)
+	if err != nil {
+		des.SetError(err)
+		return
+	}
+	a.PublicKey = *publicKey
+	a.Signature = *signature
+}
+
+// validate checks the key, and that the signature carries at least the
+// threshold of well-formed signatures by keys that exist.
+func (a AccountAuthenticatorMultiEd25519) validate() error {
+	if _, err := crypto.NewMultiEd25519PublicKey(a.PublicKey.PublicKeys, a.PublicKey.Threshold); err != nil {
+		return err
+	}
+	indices := a.Signature.SignerIndices()
+	if len(indices) != len(a.Signature.Signatures) {
+		return fmt.Errorf("MultiEd25519 bitmap marks %d signers for %d signatures", len(indices), len(a.Signature.Signatures))
+	}
+	if len(indices) < int(a.PublicKey.Threshold) {
+		return fmt.Errorf("%d MultiEd25519 signatures, below the threshold of %d", len(indices), a.PublicKey.Threshold)
+	}
+	if n := len(indices); n > 0 && indices[n-1] >= len(a.PublicKey.PublicKeys) {
+		return fmt.Errorf("MultiEd25519 signer index %d out of range for %d keys", indices[n-1], len(a.PublicKey.PublicKeys))
+	}
+	for i, signature := range a.Signature.Signatures {
+		if len(signature) != crypto.Ed25519SignatureLength {
+			return fmt.Errorf("invalid Ed25519 signature %d length: %d", i, len(signature))
+		}
+	}
+	return nil
 }
 
 // MultiAgentAuthenticator is for multi-agent transactions.
@@ -113,17 +688,24 @@ type MultiAgentAuthenticator struct {
 
 // MarshalBCS implements bcs.Marshaler.
 func (a MultiAgentAuthenticator) MarshalBCS(ser *bcs.Serializer) {
-	a.Sender.MarshalBCS(ser)
+	marshalAccountAuthenticator(ser, a.Sender)
 	ser.Uleb128(uint32(len(a.SecondarySignerAddresses)))
 	for _, addr := range a.SecondarySignerAddresses {
 		addr.MarshalBCS(ser)
 	}
 	ser.Uleb128(uint32(len(a.SecondarySigners)))
 	for _, auth := range a.SecondarySigners {
-		auth.MarshalBCS(ser)
+		marshalAccountAuthenticator(ser, auth)
 	}
 }
 
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (a *MultiAgentAuthenticator) UnmarshalBCS(des *bcs.Deserializer) {
+	a.Sender = unmarshalAccountAuthenticator(des)
+	a.SecondarySignerAddresses = unmarshalAddresses(des)
+	a.SecondarySigners = unmarshalAccountAuthenticators(des)
+}
+
 // FeePayerAuthenticator is for fee-payer transactions.
 type FeePayerAuthenticator struct {
 	Sender                   AccountAuthenticatorImpl
@@ -135,15 +717,24 @@ type FeePayerAuthenticator struct {
 
 // MarshalBCS implements bcs.Marshaler.
 func (a FeePayerAuthenticator) MarshalBCS(ser *bcs.Serializer) {
-	a.Sender.MarshalBCS(ser)
+	marshalAccountAuthenticator(ser, a.Sender)
 	ser.Uleb128(uint32(len(a.SecondarySignerAddresses)))
 	for _, addr := range a.SecondarySignerAddresses {
 		addr.MarshalBCS(ser)
 	}
 	ser.Uleb128(uint32(len(a.SecondarySigners)))
 	for _, auth := range a.SecondarySigners {
-		auth.MarshalBCS(ser)
+		marshalAccountAuthenticator(ser, auth)
 	}
 	a.FeePayerAddress.MarshalBCS(ser)
-	a.FeePayer.MarshalBCS(ser)
+	marshalAccountAuthenticator(ser, a.FeePayer)
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (a *FeePayerAuthenticator) UnmarshalBCS(des *bcs.Deserializer) {
+	a.Sender = unmarshalAccountAuthenticator(des)
+	a.SecondarySignerAddresses = unmarshalAddresses(des)
+	a.SecondarySigners = unmarshalAccountAuthenticators(des)
+	a.FeePayerAddress.UnmarshalBCS(des)
+	a.FeePayer = unmarshalAccountAuthenticator(des)
 }
@@ -0,0 +1,144 @@
+package aptos
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/crypto"
+)
+
+// TestAuthenticatorWireFormat pins the encoding of authenticators and
+// multisig payloads against the node's. Each case also records the bytes the
+// SDK produced before the encoding was fixed, which the node rejected:
+//   - public keys and signatures were written without their length prefix;
+//   - account authenticators inside SingleSender, MultiAgent and FeePayer
+//     were written without their AccountAuthenticator variant;
+//   - multisig payloads omitted the MultisigTransactionPayload variant.
+func TestAuthenticatorWireFormat(t *testing.T) {
+	const (
+		ed25519Key   = "de19e5d1880cac87d57484ce9ed2e84cf0f9599f12e7cc3a52e4e7657a763f2c"
+		ed25519Sig   = "9e653d56a09247570bb174a389e85b9226abd5c403ea6c504b386626a145158cd4efd66fc5e071c0e19538a96a05ddbda24d3c51e1e6a9dacc6bb1ce775cce07"
+		secp256k1Key = "04acdd16651b839c24665b7e2033b55225f384554949fef46c397b5275f37f6ee95554d70fb5d9f93c5831ebf695c7206e7477ce708f03ae9bb2862dc6c9e033ea"
+		secp256k1Sig = "d0d634e843b61339473b028105930ace022980708b2855954b977da09df84a770c0b68c29c8ca1b5409a5085b0ec263be80e433c83fcf6debb82f3447e71edca"
+		address1     = "0000000000000000000000000000000000000000000000000000000000000001"
+		address3     = "0000000000000000000000000000000000000000000000000000000000000003"
+	)
+	mustHex := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatalf("bad hex %q: %v", s, err)
+		}
+		return b
+	}
+	ed25519Auth := func() *AccountAuthenticatorEd25519 {
+		auth := &AccountAuthenticatorEd25519{}
+		copy(auth.PublicKey[:], mustHex(ed25519Key))
+		copy(auth.Signature[:], mustHex(ed25519Sig))
+		return auth
+	}
+	secp256k1Auth := &AccountAuthenticatorSingleKey{
+		PublicKey: AnyPublicKey{Variant: crypto.Secp256k1Scheme, PublicKey: mustHex(secp256k1Key)},
+		Signature: AnySignature{Variant: crypto.Secp256k1Scheme, Signature: mustHex(secp256k1Sig)},
+	}
+	ed25519SingleKey := &AccountAuthenticatorSingleKey{
+		PublicKey: AnyPublicKey{Variant: crypto.Ed25519Scheme, PublicKey: mustHex(ed25519Key)},
+		Signature: AnySignature{Variant: crypto.Ed25519Scheme, Signature: mustHex(ed25519Sig)},
+	}
+
+	// Account authenticators as the node encodes them
+	ed25519Account := "00" + "20" + ed25519Key + "40" + ed25519Sig
+	secp256k1Account := "02" + "01" + "41" + secp256k1Key + "01" + "40" + secp256k1Sig
+
+	tests := []struct {
+		name   string
+		auth   TransactionAuthenticator
+		before string
+		after  string
+	}{
+		{
+			name:   "ed25519",
+			auth:   TransactionAuthenticator{Variant: TransactionAuthenticatorEd25519, Auth: ed25519Auth()},
+			before: "00" + ed25519Key + ed25519Sig,
+			after:  "00" + "20" + ed25519Key + "40" + ed25519Sig,
+		},
+		{
+			name:   "single sender ed25519",
+			auth:   TransactionAuthenticator{Variant: TransactionAuthenticatorSingleSender, Auth: ed25519SingleKey},
+			before: "04" + "00" + ed25519Key + "00" + ed25519Sig,
+			after:  "04" + "02" + "00" + "20" + ed25519Key + "00" + "40" + ed25519Sig,
+		},
+		{
+			name:   "single sender secp256k1",
+			auth:   TransactionAuthenticator{Variant: TransactionAuthenticatorSingleSender, Auth: secp256k1Auth},
+			before: "04" + "01" + secp256k1Key + "01" + secp256k1Sig,
+			after:  "04" + secp256k1Account,
+		},
+		{
+			name: "multi agent",
+			auth: TransactionAuthenticator{Variant: TransactionAuthenticatorMultiAgent, Auth: &MultiAgentAuthenticator{
+				Sender:                   ed25519Auth(),
+				SecondarySignerAddresses: []AccountAddress{AccountOne},
+				SecondarySigners:         []AccountAuthenticatorImpl{secp256k1Auth},
+			}},
+			before: "02" + ed25519Key + ed25519Sig + "01" + address1 + "01" + "01" + secp256k1Key + "01" + secp256k1Sig,
+			after:  "02" + ed25519Account + "01" + address1 + "01" + secp256k1Account,
+		},
+		{
+			name: "fee payer",
+			auth: TransactionAuthenticator{Variant: TransactionAuthenticatorFeePayer, Auth: &FeePayerAuthenticator{
+				Sender:          ed25519Auth(),
+				FeePayerAddress: AccountThree,
+				FeePayer:        secp256k1Auth,
+			}},
+			before: "03" + ed25519Key + ed25519Sig + "00" + "00" + address3 + "01" + secp256k1Key + "01" + secp256k1Sig,
+			after:  "03" + ed25519Account + "00" + "00" + address3 + secp256k1Account,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bcs.Serialize(tt.auth)
+			if err != nil {
+				t.Fatalf("Serialize error: %v", err)
+			}
+			if bytes.Equal(got, mustHex(tt.before)) {
+				t.Fatal("authenticator still uses the old encoding")
+			}
+			if want := mustHex(tt.after); !bytes.Equal(got, want) {
+				t.Fatalf("encoding =\n%x\nwant\n%x", got, want)
+			}
+
+			var decoded TransactionAuthenticator
+			if err := bcs.Deserialize(got, &decoded); err != nil {
+				t.Fatalf("Deserialize error: %v", err)
+			}
+			again, err := bcs.Serialize(decoded)
+			if err != nil || !bytes.Equal(again, got) {
+				t.Errorf("round trip = %x, %v; want %x", again, err, got)
+			}
+		})
+	}
+
+	t.Run("multisig payload", func(t *testing.T) {
+		payload := TransactionPayload{Payload: &MultisigPayload{
+			MultisigAddress:    AccountThree,
+			TransactionPayload: &EntryFunction{Module: ModuleId{Address: AccountOne, Name: "m"}, Function: "f"},
+		}}
+		// 0x1::m::f with no type arguments or arguments
+		entryFunction := address1 + "016d" + "0166" + "00" + "00"
+		before := "03" + address3 + "01" + entryFunction
+		after := "03" + address3 + "01" + "00" + entryFunction
+
+		got, err := bcs.Serialize(payload)
+		if err != nil {
+			t.Fatalf("Serialize error: %v", err)
+		}
+		if bytes.Equal(got, mustHex(before)) {
+			t.Fatal("multisig payload still uses the old encoding")
+		}
+		if want := mustHex(after); !bytes.Equal(got, want) {
+			t.Fatalf("encoding =\n%x\nwant\n%x", got, want)
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package aptos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/0xbe1/aptopher/bcs"
+)
+
+// TransactionVariant identifies the kind of on-chain transaction in the BCS
+// Transaction enum.
+type TransactionVariant uint8
+
+const (
+	// TransactionVariantUser is a signed user transaction.
+	TransactionVariantUser TransactionVariant = 0
+
+	// TransactionVariantGenesis is the genesis write set.
+	TransactionVariantGenesis TransactionVariant = 1
+
+	// TransactionVariantBlockMetadata starts a new block.
+	TransactionVariantBlockMetadata TransactionVariant = 2
+
+	// TransactionVariantStateCheckpoint marks a state checkpoint.
+	TransactionVariantStateCheckpoint TransactionVariant = 3
+
+	// TransactionVariantValidator is a validator transaction (DKG result or JWK update).
+	TransactionVariantValidator TransactionVariant = 4
+
+	// TransactionVariantBlockMetadataExt starts a new block, optionally with randomness.
+	TransactionVariantBlockMetadataExt TransactionVariant = 5
+
+	// TransactionVariantBlockEpilogue ends a block.
+	TransactionVariantBlockEpilogue TransactionVariant = 6
+)
+
+// TransactionBCS is the BCS representation of an on-chain transaction, as
+// stored by nodes and returned by BCS endpoints. Exactly one of the fields
+// after Variant is set, depending on the variant:
+//   - TransactionVariantUser: UserTransaction
+//   - TransactionVariantBlockMetadata, TransactionVariantBlockMetadataExt: BlockMetadata
+//   - TransactionVariantStateCheckpoint: StateCheckpoint
+//   - TransactionVariantBlockEpilogue: BlockEpilogue
+//
+// Genesis and validator transactions cannot be decoded; UnmarshalBCS fails
+// on them.
+type TransactionBCS struct {
+	Variant         TransactionVariant
+	UserTransaction *SignedTransaction
+	BlockMetadata   *BlockMetadata
+	StateCheckpoint *[32]byte // Block ID
+	BlockEpilogue   *BlockEpilogue
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (t *TransactionBCS) UnmarshalBCS(des *bcs.Deserializer) {
+	t.Variant = TransactionVariant(des.Uleb128())
+	if des.Error() != nil {
+		return
+	}
+	switch t.Variant {
+	case TransactionVariantUser:
+		t.UserTransaction = &SignedTransaction{}
+		t.UserTransaction.UnmarshalBCS(des)
+	case TransactionVariantBlockMetadata:
+		t.BlockMetadata = &BlockMetadata{}
+		t.BlockMetadata.UnmarshalBCS(des)
+	case TransactionVariantBlockMetadataExt:
+		t.BlockMetadata = &BlockMetadata{}
+		t.BlockMetadata.unmarshalExt(des)
+	case TransactionVariantStateCheckpoint:
+		var id [32]byte
+		unmarshalFixedLengthBytes(des, id[:], "hash")
+		t.StateCheckpoint = &id
+	case TransactionVariantBlockEpilogue:
+		t.BlockEpilogue = &BlockEpilogue{}
+		t.BlockEpilogue.UnmarshalBCS(des)
+	case TransactionVariantGenesis, TransactionVariantValidator:
+		des.SetError(fmt.Errorf("decoding transaction variant %d is not supported", t.Variant))
+	default:
+		des.SetError(fmt.Errorf("unknown transaction variant: %d", t.Variant))
+	}
+}
+
+// TransactionOnChainBCS is a committed transaction as returned in BCS by
+// GetTransactionByVersionBCS: its version, the transaction itself, and its
+// output, which is not decoded.
+type TransactionOnChainBCS struct {
+	Version     uint64
+	Transaction TransactionBCS
+
+	// Output holds the rest of the response, still BCS-encoded: the
+	// transaction info, events, accumulator root hash, and write set.
+	Output []byte
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (t *TransactionOnChainBCS) UnmarshalBCS(des *bcs.Deserializer) {
+	t.Version = des.U64()
+	t.Transaction.UnmarshalBCS(des)
+	if des.Error() != nil {
+		return
+	}
+	t.Output = des.FixedBytes(des.Remaining())
+}
+
+// BlockMetadata is the transaction that starts each block.
+type BlockMetadata struct {
+	ID                       [32]byte
+	Epoch                    uint64
+	Round                    uint64
+	Proposer                 AccountAddress
+	PreviousBlockVotesBitvec []byte
+	FailedProposerIndices    []uint32
+	TimestampUsecs           uint64
+	Randomness               *BlockRandomness // Only set for BlockMetadataExt V1
+}
+
+// BlockRandomness is the on-chain randomness seed for a block.
+type BlockRandomness struct {
+	Epoch      uint64
+	Round      uint64
+	Randomness []byte
+}
+
+// Time returns the block timestamp.
+func (m *BlockMetadata) Time() time.Time {
+	return time.UnixMicro(int64(m.TimestampUsecs))
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (m *BlockMetadata) UnmarshalBCS(des *bcs.Deserializer) {
+	unmarshalFixedLengthBytes(des, m.ID[:], "hash")
+	m.Epoch = des.U64()
+	m.Round = des.U64()
+	m.Proposer.UnmarshalBCS(des)
+	m.PreviousBlockVotesBitvec = des.Bytes()
+	length := des.Uleb128()
+	if des.Error() != nil {
+		return
+	}
+	m.FailedProposerIndices = make([]uint32, 0, min(int(length), des.Remaining()/4))
+	for i := uint32(0); i < length; i++ {
+		index := des.U32()
+		if des.Error() != nil {
+			return
+		}
+		m.FailedProposerIndices = append(m.FailedProposerIndices, index)
+	}
+	m.TimestampUsecs = des.U64()
+}
+
+// unmarshalExt deserializes the BlockMetadataExt enum. V0 is plain block
+// metadata; V1 adds optional randomness.
+func (m *BlockMetadata) unmarshalExt(des *bcs.Deserializer) {
+	variant := des.Uleb128()
+	if des.Error() != nil {
+		return
+	}
+	switch variant {
+	case 0:
+		m.UnmarshalBCS(des)
+	case 1:
+		m.UnmarshalBCS(des)
+		if unmarshalOptionTag(des) {
+			m.Randomness = &BlockRandomness{
+				Epoch: des.U64(),
+				Round: des.U64(),
+			}
+			m.Randomness.Randomness = des.Bytes()
+		}
+	default:
+		des.SetError(fmt.Errorf("unsupported block metadata ext variant: %d", variant))
+	}
+}
+
+// BlockEpilogue is the transaction that ends each block.
+type BlockEpilogue struct {
+	BlockID                     [32]byte
+	BlockGasLimitReached        bool
+	BlockOutputLimitReached     bool
+	BlockEffectiveBlockGasUnits uint64
+	BlockApproxOutputSize       uint64
+	FeeDistribution             map[uint64]uint64 // Validator index to amount; nil for V0 epilogues
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (e *BlockEpilogue) UnmarshalBCS(des *bcs.Deserializer) {
+	variant := des.Uleb128()
+	if des.Error() != nil {
+		return
+	}
+	if variant > 1 {
+		des.SetError(fmt.Errorf("unsupported block epilogue variant: %d", variant))
+		return
+	}
+	unmarshalFixedLengthBytes(des, e.BlockID[:], "hash")
+
+	if endInfoVariant := des.Uleb128(); endInfoVariant != 0 {
+		des.SetError(fmt.Errorf("unsupported block end info variant: %d", endInfoVariant))
+		return
+	}
+	e.BlockGasLimitReached = des.Bool()
+	e.BlockOutputLimitReached = des.Bool()
+	e.BlockEffectiveBlockGasUnits = des.U64()
+	e.BlockApproxOutputSize = des.U64()
+
+	if variant == 0 {
+		return
+	}
+	if feeVariant := des.Uleb128(); feeVariant != 0 {
+		des.SetError(fmt.Errorf("unsupported fee distribution variant: %d", feeVariant))
+		return
+	}
+	length := des.Uleb128()
+	if des.Error() != nil {
+		return
+	}
+	e.FeeDistribution = make(map[uint64]uint64, min(int(length), des.Remaining()/16))
+	for i := uint32(0); i < length; i++ {
+		index := des.U64()
+		amount := des.U64()
+		if des.Error() != nil {
+			return
+		}
+		e.FeeDistribution[index] = amount
+	}
+}
@@ -0,0 +1,335 @@
+package aptos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/0xbe1/aptopher/bcs"
+	"github.com/0xbe1/aptopher/crypto"
+)
+
+func TestTransactionPayloadBCSRoundTrip(t *testing.T) {
+	entryFunction := &EntryFunction{
+		Module:   ModuleId{Address: AccountOne, Name: "coin"},
+		Function: "transfer",
+		TypeArgs: mustParseTypeTags(t, "0x1::aptos_coin::AptosCoin"),
+		Args:     EntryFunctionArgs(AddressArg(AccountOne), U64Arg(100)),
+	}
+	nonce := uint64(42)
+
+	tests := []struct {
+		name    string
+		payload TransactionPayloadImpl
+	}{
+		{"entry function", entryFunction},
+		{"script", &Script{
+			Code:     []byte{0xa1, 0x1c, 0xeb, 0x0b},
+			TypeArgs: mustParseTypeTags(t, "u64"),
+			Args: []ScriptArgument{
+				{Variant: ScriptArgumentU8, Value: uint8(1)},
+				{Variant: ScriptArgumentU64, Value: uint64(2)},
+				{Variant: ScriptArgumentU128, Value: NewU128(3)},
+				{Variant: ScriptArgumentAddress, Value: AccountOne},
+				{Variant: ScriptArgumentU8Vec, Value: []byte{4, 5}},
+				{Variant: ScriptArgumentBool, Value: true},
+			},
+		}},
+		{"multisig without payload", &MultisigPayload{MultisigAddress: AccountOne}},
+		{"multisig with payload", &MultisigPayload{MultisigAddress: AccountOne, TransactionPayload: entryFunction}},
+		{"orderless", wrapPayloadForOrderless(TransactionPayload{Payload: entryFunction}, &nonce).Payload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := bcs.Serialize(TransactionPayload{Payload: tt.payload})
+			if err != nil {
+				t.Fatalf("Serialize error: %v", err)
+			}
+			var decoded TransactionPayload
+			if err := bcs.Deserialize(encoded, &decoded); err != nil {
+				t.Fatalf("Deserialize error: %v", err)
+			}
+			reencoded, err := bcs.Serialize(decoded)
+			if err != nil {
+				t.Fatalf("Serialize decoded error: %v", err)
+			}
+			if !bytes.Equal(encoded, reencoded) {
+				t.Errorf("round trip mismatch:\n got %x\nwant %x", reencoded, encoded)
+			}
+		})
+	}
+}
+
+func TestTransactionBCSUserTransaction(t *testing.T) {
+	account, err := NewEd25519Account()
+	if err != nil {
+		t.Fatalf("NewEd25519Account error: %v", err)
+	}
+	rawTxn, err := NewRawTransaction(account.Address, TransactionPayload{
+		Payload: &EntryFunction{
+			Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+			Function: "transfer",
+			Args:     EntryFunctionArgs(AddressArg(AccountOne), U64Arg(1)),
+		},
+	}, 2, WithSequenceNumber(7), WithGasUnitPrice(100), WithExpirationTimestampSecs(1700000000))
+	if err != nil {
+		t.Fatalf("NewRawTransaction error: %v", err)
+	}
+	signedTxn, err := account.SignTransaction(rawTxn)
+	if err != nil {
+		t.Fatalf("SignTransaction error: %v", err)
+	}
+	signedBytes, err := signedTxn.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes error: %v", err)
+	}
+
+	// Ed25519(32-byte public key, ...), matching the legacy address derivation
+	authPrefix := []byte{byte(TransactionAuthenticatorEd25519), 0x20}
+	rawBytes, _ := rawTxn.Bytes()
+	if !bytes.HasPrefix(signedBytes[len(rawBytes):], authPrefix) {
+		t.Errorf("authenticator = %x, want prefix %x", signedBytes[len(rawBytes):], authPrefix)
+	}
+
+	var txn TransactionBCS
+	if err := bcs.Deserialize(append([]byte{byte(TransactionVariantUser)}, signedBytes...), &txn); err != nil {
+		t.Fatalf("Deserialize error: %v", err)
+	}
+	if txn.Variant != TransactionVariantUser || txn.UserTransaction == nil {
+		t.Fatalf("unexpected transaction: %+v", txn)
+	}
+	if txn.UserTransaction.RawTxn.Sender != account.Address || txn.UserTransaction.RawTxn.SequenceNumber != 7 {
+		t.Errorf("unexpected raw transaction: %+v", txn.UserTransaction.RawTxn)
+	}
+	reencoded, err := txn.UserTransaction.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes error: %v", err)
+	}
+	if !bytes.Equal(reencoded, signedBytes) {
+		t.Errorf("round trip mismatch:\n got %x\nwant %x", reencoded, signedBytes)
+	}
+}
+
+func TestTransactionAuthenticatorBCSRoundTrip(t *testing.T) {
+	singleKey := &AccountAuthenticatorSingleKey{
+		PublicKey: AnyPublicKey{Variant: 0, PublicKey: bytes.Repeat([]byte{1}, 32)},
+		Signature: AnySignature{Variant: 0, Signature: bytes.Repeat([]byte{2}, 64)},
+	}
+	ed25519 := &AccountAuthenticatorEd25519{}
+	ed25519.PublicKey[0] = 3
+	ed25519.Signature[0] = 4
+
+	multiEd25519 := &AccountAuthenticatorMultiEd25519{
+		PublicKey: crypto.MultiEd25519PublicKey{PublicKeys: [][]byte{bytes.Repeat([]byte{5}, 32), bytes.Repeat([]byte{6}, 32)}, Threshold: 1},
+		Signature: crypto.MultiEd25519Signature{Signatures: [][]byte{bytes.Repeat([]byte{7}, 64)}, Bitmap: [4]byte{0b01000000}},
+	}
+
+	tests := []struct {
+		name string
+		auth TransactionAuthenticator
+	}{
+		{"ed25519", TransactionAuthenticator{Variant: TransactionAuthenticatorEd25519, Auth: ed25519}},
+		{"single sender", TransactionAuthenticator{Variant: TransactionAuthenticatorSingleSender, Auth: singleKey}},
+		{"multi ed25519", TransactionAuthenticator{Variant: TransactionAuthenticatorMultiEd25519, Auth: multiEd25519}},
+		{"multi key", TransactionAuthenticator{Variant: TransactionAuthenticatorSingleSender, Auth: &AccountAuthenticatorMultiKey{
+			PublicKey: MultiKey{PublicKeys: []AnyPublicKey{singleKey.PublicKey, singleKey.PublicKey}, SignaturesRequired: 1},
+			Signature: MultiKeySignature{Signatures: []AnySignature{singleKey.Signature}, Bitmap: [4]byte{0b10000000}},
+		}}},
+		{"multi agent", TransactionAuthenticator{Variant: TransactionAuthenticatorMultiAgent, Auth: &MultiAgentAuthenticator{
+			Sender:                   singleKey,
+			SecondarySignerAddresses: []AccountAddress{AccountOne},
+			SecondarySigners:         []AccountAuthenticatorImpl{ed25519},
+		}}},
+		{"fee payer", TransactionAuthenticator{Variant: TransactionAuthenticatorFeePayer, Auth: &FeePayerAuthenticator{
+			Sender:          singleKey,
+			FeePayerAddress: AccountOne,
+			FeePayer:        ed25519,
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := bcs.Serialize(tt.auth)
+			if err != nil {
+				t.Fatalf("Serialize error: %v", err)
+			}
+			var decoded TransactionAuthenticator
+			if err := bcs.Deserialize(encoded, &decoded); err != nil {
+				t.Fatalf("Deserialize error: %v", err)
+			}
+			reencoded, err := bcs.Serialize(decoded)
+			if err != nil {
+				t.Fatalf("Serialize decoded error: %v", err)
+			}
+			if !bytes.Equal(encoded, reencoded) {
+				t.Errorf("round trip mismatch:\n got %x\nwant %x", reencoded, encoded)
+			}
+		})
+	}
+}
+
+func TestSignedTransactionBCSRoundTrip(t *testing.T) {
+	rawTxn, err := NewRawTransaction(AccountOne, TransactionPayload{
+		Payload: &EntryFunction{
+			Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+			Function: "transfer",
+			Args:     EntryFunctionArgs(AddressArg(AccountOne), U64Arg(1)),
+		},
+	}, 2, WithSequenceNumber(3), WithGasUnitPrice(100), WithExpirationTimestampSecs(1700000000))
+	if err != nil {
+		t.Fatalf("NewRawTransaction error: %v", err)
+	}
+	singleKey := &AccountAuthenticatorSingleKey{
+		PublicKey: AnyPublicKey{Variant: 0, PublicKey: bytes.Repeat([]byte{1}, 32)},
+		Signature: AnySignature{Variant: 0, Signature: bytes.Repeat([]byte{2}, 64)},
+	}
+
+	tests := []struct {
+		name string
+		auth TransactionAuthenticator
+	}{
+		{"single sender", TransactionAuthenticator{Variant: TransactionAuthenticatorSingleSender, Auth: singleKey}},
+		{"fee payer", TransactionAuthenticator{Variant: TransactionAuthenticatorFeePayer, Auth: &FeePayerAuthenticator{
+			Sender:          singleKey,
+			FeePayerAddress: AccountOne,
+			FeePayer:        singleKey,
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := (&SignedTransaction{RawTxn: rawTxn, Authenticator: tt.auth}).Bytes()
+			if err != nil {
+				t.Fatalf("Bytes error: %v", err)
+			}
+			var decoded SignedTransaction
+			if err := bcs.Deserialize(encoded, &decoded); err != nil {
+				t.Fatalf("Deserialize error: %v", err)
+			}
+			if decoded.RawTxn.Sender != AccountOne || decoded.RawTxn.SequenceNumber != 3 || decoded.Authenticator.Variant != tt.auth.Variant {
+				t.Errorf("unexpected transaction: %+v", decoded)
+			}
+			reencoded, err := decoded.Bytes()
+			if err != nil {
+				t.Fatalf("Bytes decoded error: %v", err)
+			}
+			if !bytes.Equal(encoded, reencoded) {
+				t.Errorf("round trip mismatch:\n got %x\nwant %x", reencoded, encoded)
+			}
+		})
+	}
+}
+
+func TestTransactionBCSBlockMetadata(t *testing.T) {
+	id := bytes.Repeat([]byte{0xab}, 32)
+	writeMetadata := func(ser *bcs.Serializer) {
+		ser.Bytes(id)
+		ser.U64(5)  // epoch
+		ser.U64(10) // round
+		AccountOne.MarshalBCS(ser)
+		ser.Bytes([]byte{0xff})
+		ser.Uleb128(2)
+		ser.U32(1)
+		ser.U32(3)
+		ser.U64(1700000000000000)
+	}
+
+	t.Run("v0", func(t *testing.T) {
+		ser := bcs.NewSerializer()
+		ser.Uleb128(uint32(TransactionVariantBlockMetadata))
+		writeMetadata(ser)
+
+		var txn TransactionBCS
+		if err := bcs.Deserialize(ser.ToBytes(), &txn); err != nil {
+			t.Fatalf("Deserialize error: %v", err)
+		}
+		m := txn.BlockMetadata
+		if m == nil {
+			t.Fatalf("BlockMetadata is nil: %+v", txn)
+		}
+		if !bytes.Equal(m.ID[:], id) || m.Epoch != 5 || m.Round != 10 || m.Proposer != AccountOne {
+			t.Errorf("unexpected block metadata: %+v", m)
+		}
+		if len(m.FailedProposerIndices) != 2 || m.FailedProposerIndices[1] != 3 {
+			t.Errorf("FailedProposerIndices = %v, want [1 3]", m.FailedProposerIndices)
+		}
+		if m.Time().Unix() != 1700000000 {
+			t.Errorf("Time = %v", m.Time())
+		}
+		if m.Randomness != nil {
+			t.Errorf("Randomness = %+v, want nil", m.Randomness)
+		}
+	})
+
+	t.Run("ext v1 with randomness", func(t *testing.T) {
+		ser := bcs.NewSerializer()
+		ser.Uleb128(uint32(TransactionVariantBlockMetadataExt))
+		ser.Uleb128(1)
+		writeMetadata(ser)
+		ser.U8(1) // Some
+		ser.U64(5)
+		ser.U64(10)
+		ser.Bytes([]byte{0x01, 0x02})
+
+		var txn TransactionBCS
+		if err := bcs.Deserialize(ser.ToBytes(), &txn); err != nil {
+			t.Fatalf("Deserialize error: %v", err)
+		}
+		r := txn.BlockMetadata.Randomness
+		if r == nil || r.Epoch != 5 || r.Round != 10 || !bytes.Equal(r.Randomness, []byte{0x01, 0x02}) {
+			t.Errorf("unexpected randomness: %+v", r)
+		}
+	})
+}
+
+func TestTransactionBCSOtherVariants(t *testing.T) {
+	id := bytes.Repeat([]byte{0xcd}, 32)
+
+	t.Run("state checkpoint", func(t *testing.T) {
+		ser := bcs.NewSerializer()
+		ser.Uleb128(uint32(TransactionVariantStateCheckpoint))
+		ser.Bytes(id)
+
+		var txn TransactionBCS
+		if err := bcs.Deserialize(ser.ToBytes(), &txn); err != nil {
+			t.Fatalf("Deserialize error: %v", err)
+		}
+		if txn.StateCheckpoint == nil || !bytes.Equal(txn.StateCheckpoint[:], id) {
+			t.Errorf("StateCheckpoint = %x, want %x", txn.StateCheckpoint, id)
+		}
+	})
+
+	t.Run("block epilogue v1", func(t *testing.T) {
+		ser := bcs.NewSerializer()
+		ser.Uleb128(uint32(TransactionVariantBlockEpilogue))
+		ser.Uleb128(1)
+		ser.Bytes(id)
+		ser.Uleb128(0) // BlockEndInfo::V0
+		ser.Bool(true)
+		ser.Bool(false)
+		ser.U64(1000)
+		ser.U64(2000)
+		ser.Uleb128(0) // FeeDistribution::V0
+		ser.Uleb128(1)
+		ser.U64(3)
+		ser.U64(500)
+
+		var txn TransactionBCS
+		if err := bcs.Deserialize(ser.ToBytes(), &txn); err != nil {
+			t.Fatalf("Deserialize error: %v", err)
+		}
+		e := txn.BlockEpilogue
+		if e == nil || !e.BlockGasLimitReached || e.BlockEffectiveBlockGasUnits != 1000 || e.FeeDistribution[3] != 500 {
+			t.Errorf("unexpected block epilogue: %+v", e)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for _, variant := range []TransactionVariant{TransactionVariantGenesis, TransactionVariantValidator, 7} {
+			var txn TransactionBCS
+			if err := bcs.Deserialize([]byte{byte(variant), 0x00}, &txn); err == nil {
+				t.Errorf("variant %d: expected error", variant)
+			}
+		}
+	})
+}
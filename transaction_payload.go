@@ -1,6 +1,8 @@
 package aptos
 
 import (
+	"fmt"
+
 	"github.com/0xbe1/aptopher/bcs"
 )
 
@@ -41,6 +43,52 @@ func (p TransactionPayload) MarshalBCS(ser *bcs.Serializer) {
 	p.Payload.MarshalBCS(ser)
 }
 
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (p *TransactionPayload) UnmarshalBCS(des *bcs.Deserializer) {
+	variant := TransactionPayloadVariant(des.Uleb128())
+	if des.Error() != nil {
+		return
+	}
+	switch variant {
+	case TransactionPayloadScript:
+		var s Script
+		s.UnmarshalBCS(des)
+		p.Payload = &s
+	case TransactionPayloadEntryFunction:
+		var e EntryFunction
+		e.UnmarshalBCS(des)
+		p.Payload = &e
+	case TransactionPayloadMultisig:
+		var m MultisigPayload
+		m.UnmarshalBCS(des)
+		p.Payload = &m
+	case TransactionPayloadPayload:
+		var inner TransactionInnerPayloadV1
+		inner.UnmarshalBCS(des)
+		p.Payload = &inner
+	default:
+		des.SetError(fmt.Errorf("unsupported transaction payload variant: %d", variant))
+	}
+}
+
+// unmarshalTypeArgs deserializes a vector of type tags.
+func unmarshalTypeArgs(des *bcs.Deserializer) []TypeTag {
+	length := des.Uleb128()
+	if des.Error() != nil {
+		return nil
+	}
+	typeArgs := make([]TypeTag, 0, min(int(length), des.Remaining()))
+	for i := uint32(0); i < length; i++ {
+		var t TypeTag
+		t.UnmarshalBCS(des)
+		if des.Error() != nil {
+			return nil
+		}
+		typeArgs = append(typeArgs, t)
+	}
+	return typeArgs
+}
+
 // EntryFunction represents an entry function call.
 type EntryFunction struct {
 	Module   ModuleId
@@ -63,12 +111,52 @@ func (e EntryFunction) MarshalBCS(ser *bcs.Serializer) {
 		t.MarshalBCS(ser)
 	}
 	// Arguments (as vector of bytes)
-	ser.Uleb128(uint32(len(e.Args)))
-	for _, arg := range e.Args {
-		ser.Bytes(arg)
+	ser.BytesVector(e.Args)
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (e *EntryFunction) UnmarshalBCS(des *bcs.Deserializer) {
+	e.Module.UnmarshalBCS(des)
+	e.Function = des.String()
+	e.TypeArgs = unmarshalTypeArgs(des)
+	e.Args = des.BytesVector()
+}
+
+// ValidateABI checks the entry function against its ABI, returning a
+// descriptive error if it is not an entry function or if the number of type
+// arguments or arguments does not match. Leading signer parameters are not
+// counted as arguments.
+func (e *EntryFunction) ValidateABI(fn *MoveFunction) error {
+	name := e.Module.String() + "::" + e.Function
+	if fn.Name != e.Function {
+		return fmt.Errorf("ABI is for function %s, not %s", fn.Name, name)
+	}
+	if !fn.IsEntry {
+		return fmt.Errorf("%s is not an entry function", name)
+	}
+	if len(e.TypeArgs) != len(fn.GenericTypeParams) {
+		return fmt.Errorf("%s expects %d type argument(s), got %d", name, len(fn.GenericTypeParams), len(e.TypeArgs))
+	}
+	params := fn.Params
+	for len(params) > 0 && (params[0] == "signer" || params[0] == "&signer") {
+		params = params[1:]
 	}
+	if len(e.Args) != len(params) {
+		return fmt.Errorf("%s expects %d argument(s), got %d", name, len(params), len(e.Args))
+	}
+	return nil
 }
 
+// PayloadJSON returns the entry function as a payload in the REST API's JSON
+// format, converting its BCS arguments with EntryFunctionArgsToJSON given the
+// function's parameter types. Leading signer parameters are skipped.
+func (e *EntryFunction) PayloadJSON(paramTypes []TypeTag) (EntryFunctionPayloadJSON, error) {
+	args, err := EntryFunctionArgsToJSON(e.Args, paramTypes)
+	if err != nil {
+		return EntryFunctionPayloadJSON{}, err
+	}
+	return NewEntryFunctionPayloadJSON(e.Module, e.Function, e.TypeArgs, args), nil
+}
 
 // Script represents a Move script.
 type Script struct {
@@ -94,6 +182,25 @@ func (s Script) MarshalBCS(ser *bcs.Serializer) {
 	}
 }
 
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (s *Script) UnmarshalBCS(des *bcs.Deserializer) {
+	s.Code = des.Bytes()
+	s.TypeArgs = unmarshalTypeArgs(des)
+	length := des.Uleb128()
+	if des.Error() != nil {
+		return
+	}
+	s.Args = make([]ScriptArgument, 0, min(int(length), des.Remaining()))
+	for i := uint32(0); i < length; i++ {
+		var arg ScriptArgument
+		arg.UnmarshalBCS(des)
+		if des.Error() != nil {
+			return
+		}
+		s.Args = append(s.Args, arg)
+	}
+}
+
 // ScriptArgumentVariant represents the type of script argument.
 type ScriptArgumentVariant uint8
 
@@ -143,6 +250,42 @@ func (a ScriptArgument) MarshalBCS(ser *bcs.Serializer) {
 	}
 }
 
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (a *ScriptArgument) UnmarshalBCS(des *bcs.Deserializer) {
+	a.Variant = ScriptArgumentVariant(des.Uleb128())
+	if des.Error() != nil {
+		return
+	}
+	switch a.Variant {
+	case ScriptArgumentU8:
+		a.Value = des.U8()
+	case ScriptArgumentU16:
+		a.Value = des.U16()
+	case ScriptArgumentU32:
+		a.Value = des.U32()
+	case ScriptArgumentU64:
+		a.Value = des.U64()
+	case ScriptArgumentU128:
+		var v U128
+		v.UnmarshalBCS(des)
+		a.Value = v
+	case ScriptArgumentU256:
+		var v U256
+		v.UnmarshalBCS(des)
+		a.Value = v
+	case ScriptArgumentAddress:
+		var v AccountAddress
+		v.UnmarshalBCS(des)
+		a.Value = v
+	case ScriptArgumentU8Vec:
+		a.Value = des.Bytes()
+	case ScriptArgumentBool:
+		a.Value = des.Bool()
+	default:
+		des.SetError(fmt.Errorf("unsupported script argument variant: %d", a.Variant))
+	}
+}
+
 // MultisigPayload represents a multisig transaction payload.
 type MultisigPayload struct {
 	MultisigAddress    AccountAddress
@@ -160,8 +303,32 @@ func (m MultisigPayload) MarshalBCS(ser *bcs.Serializer) {
 		ser.U8(0) // None
 	} else {
 		ser.U8(1) // Some
-		m.TransactionPayload.MarshalBCS(ser)
+		marshalMultisigTransactionPayload(ser, m.TransactionPayload)
+	}
+}
+
+// marshalMultisigTransactionPayload serializes an entry function as a
+// MultisigTransactionPayload, the form multisig proposals are stored and
+// hashed in.
+func marshalMultisigTransactionPayload(ser *bcs.Serializer, e *EntryFunction) {
+	ser.Uleb128(0) // MultisigTransactionPayload::EntryFunction
+	e.MarshalBCS(ser)
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (m *MultisigPayload) UnmarshalBCS(des *bcs.Deserializer) {
+	m.MultisigAddress.UnmarshalBCS(des)
+	m.TransactionPayload = nil
+	if !unmarshalOptionTag(des) {
+		return
 	}
+	if variant := des.Uleb128(); variant != 0 {
+		des.SetError(fmt.Errorf("unsupported multisig transaction payload variant: %d", variant))
+		return
+	}
+	var e EntryFunction
+	e.UnmarshalBCS(des)
+	m.TransactionPayload = &e
 }
 
 // TransactionInnerPayloadV1 wraps an executable with extra config for orderless transactions.
@@ -183,6 +350,16 @@ func (p TransactionInnerPayloadV1) MarshalBCS(ser *bcs.Serializer) {
 	p.ExtraConfig.MarshalBCS(ser)
 }
 
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (p *TransactionInnerPayloadV1) UnmarshalBCS(des *bcs.Deserializer) {
+	if variant := des.Uleb128(); variant != 0 {
+		des.SetError(fmt.Errorf("unsupported transaction inner payload variant: %d", variant))
+		return
+	}
+	p.Executable.UnmarshalBCS(des)
+	p.ExtraConfig.UnmarshalBCS(des)
+}
+
 // TransactionExecutableVariant represents the type of executable.
 type TransactionExecutableVariant uint8
 
@@ -192,6 +369,10 @@ const (
 
 	// TransactionExecutableEntryFunction is an entry function executable.
 	TransactionExecutableEntryFunction TransactionExecutableVariant = 1
+
+	// TransactionExecutableEmpty carries no executable, e.g. when executing a
+	// multisig transaction whose payload is already stored on-chain.
+	TransactionExecutableEmpty TransactionExecutableVariant = 2
 )
 
 // TransactionExecutable wraps a script or entry function for inner payloads.
@@ -212,6 +393,25 @@ func (e TransactionExecutable) MarshalBCS(ser *bcs.Serializer) {
 	}
 }
 
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (e *TransactionExecutable) UnmarshalBCS(des *bcs.Deserializer) {
+	e.Variant = TransactionExecutableVariant(des.Uleb128())
+	if des.Error() != nil {
+		return
+	}
+	switch e.Variant {
+	case TransactionExecutableScript:
+		e.Script = &Script{}
+		e.Script.UnmarshalBCS(des)
+	case TransactionExecutableEntryFunction:
+		e.EntryFunc = &EntryFunction{}
+		e.EntryFunc.UnmarshalBCS(des)
+	case TransactionExecutableEmpty:
+	default:
+		des.SetError(fmt.Errorf("unsupported transaction executable variant: %d", e.Variant))
+	}
+}
+
 // TransactionExtraConfigV1 contains optional extra configuration for transactions.
 type TransactionExtraConfigV1 struct {
 	MultisigAddress       *AccountAddress // Optional multisig address
@@ -238,3 +438,38 @@ func (c TransactionExtraConfigV1) MarshalBCS(ser *bcs.Serializer) {
 		ser.U64(*c.ReplayProtectionNonce)
 	}
 }
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (c *TransactionExtraConfigV1) UnmarshalBCS(des *bcs.Deserializer) {
+	if variant := des.Uleb128(); variant != 0 {
+		des.SetError(fmt.Errorf("unsupported transaction extra config variant: %d", variant))
+		return
+	}
+	c.MultisigAddress = nil
+	if unmarshalOptionTag(des) {
+		c.MultisigAddress = &AccountAddress{}
+		c.MultisigAddress.UnmarshalBCS(des)
+	}
+	c.ReplayProtectionNonce = nil
+	if unmarshalOptionTag(des) {
+		nonce := des.U64()
+		c.ReplayProtectionNonce = &nonce
+	}
+}
+
+// unmarshalOptionTag reads a BCS option tag and reports whether a value follows.
+func unmarshalOptionTag(des *bcs.Deserializer) bool {
+	tag := des.U8()
+	if des.Error() != nil {
+		return false
+	}
+	switch tag {
+	case 0:
+		return false
+	case 1:
+		return true
+	default:
+		des.SetError(fmt.Errorf("bcs: invalid option tag: 0x%02x", tag))
+		return false
+	}
+}
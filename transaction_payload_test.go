@@ -0,0 +1,109 @@
+package aptos
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEntryFunctionValidateABI(t *testing.T) {
+	transferCoins := &MoveFunction{
+		Name:              "transfer_coins",
+		IsEntry:           true,
+		GenericTypeParams: []MoveFunctionGenericTypeParam{{}},
+		Params:            []string{"&signer", "address", "u64"},
+	}
+	entryFunction := func(typeArgs []TypeTag, args ...EntryFunctionArg) *EntryFunction {
+		return &EntryFunction{
+			Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+			Function: "transfer_coins",
+			TypeArgs: typeArgs,
+			Args:     EntryFunctionArgs(args...),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		fn      *MoveFunction
+		e       *EntryFunction
+		wantErr string
+	}{
+		{
+			name: "valid",
+			fn:   transferCoins,
+			e:    entryFunction(mustParseTypeTags(t, "0x1::aptos_coin::AptosCoin"), AddressArg(AccountOne), U64Arg(1)),
+		},
+		{
+			name:    "missing type argument",
+			fn:      transferCoins,
+			e:       entryFunction(nil, AddressArg(AccountOne), U64Arg(1)),
+			wantErr: "expects 1 type argument(s), got 0",
+		},
+		{
+			name:    "missing argument",
+			fn:      transferCoins,
+			e:       entryFunction(mustParseTypeTags(t, "0x1::aptos_coin::AptosCoin"), AddressArg(AccountOne)),
+			wantErr: "expects 2 argument(s), got 1",
+		},
+		{
+			name:    "not an entry function",
+			fn:      &MoveFunction{Name: "transfer_coins"},
+			e:       entryFunction(nil),
+			wantErr: "is not an entry function",
+		},
+		{
+			name:    "wrong function",
+			fn:      &MoveFunction{Name: "transfer", IsEntry: true},
+			e:       entryFunction(nil),
+			wantErr: "ABI is for function transfer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.e.ValidateABI(tt.fn)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEntryFunctionPayloadJSON(t *testing.T) {
+	entryFunction := &EntryFunction{
+		Module:   ModuleId{Address: AccountOne, Name: "aptos_account"},
+		Function: "transfer_coins",
+		TypeArgs: mustParseTypeTags(t, "0x1::aptos_coin::AptosCoin"),
+		Args:     EntryFunctionArgs(AddressArg(MustParseAccountAddress("0xb0b")), U64Arg(100)),
+	}
+	payload, err := entryFunction.PayloadJSON(mustParseTypeTags(t, "signer", "address", "u64"))
+	if err != nil {
+		t.Fatalf("PayloadJSON error: %v", err)
+	}
+	got, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	want := `{"type":"entry_function_payload","function":"0x1::aptos_account::transfer_coins",` +
+		`"type_arguments":["0x1::aptos_coin::AptosCoin"],` +
+		`"arguments":["0x0000000000000000000000000000000000000000000000000000000000000b0b","100"]}`
+	if string(got) != want {
+		t.Errorf("payload = %s, want %s", got, want)
+	}
+
+	// Arguments are always an array, even when there are none
+	payload = NewEntryFunctionPayloadJSON(ModuleId{Address: AccountOne, Name: "m"}, "f", nil, nil)
+	if got, _ := json.Marshal(payload); string(got) != `{"type":"entry_function_payload","function":"0x1::m::f","type_arguments":[],"arguments":[]}` {
+		t.Errorf("empty payload = %s", got)
+	}
+
+	if _, err := entryFunction.PayloadJSON(mustParseTypeTags(t, "address")); err == nil {
+		t.Error("PayloadJSON with too few parameter types succeeded, want error")
+	}
+}
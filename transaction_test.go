@@ -0,0 +1,89 @@
+package aptos
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTransactionTime(t *testing.T) {
+	want := time.Date(2024, 1, 2, 3, 4, 5, 678901000, time.UTC)
+
+	txn := Transaction{Timestamp: 1704164645678901}
+	if got := txn.Time(); !got.Equal(want) {
+		t.Errorf("Transaction.Time() = %v, want %v", got, want)
+	}
+
+	userTxn := UserTransaction{Timestamp: 1704164645678901}
+	if got := userTxn.Time(); !got.Equal(want) {
+		t.Errorf("UserTransaction.Time() = %v, want %v", got, want)
+	}
+
+	block := Block{BlockTimestamp: 1704164645678901}
+	if got := block.Time(); !got.Equal(want) {
+		t.Errorf("Block.Time() = %v, want %v", got, want)
+	}
+
+	metadata := ResponseMetadata{LedgerTimestampUsec: 1704164645678901}
+	if got := metadata.Time(); !got.Equal(want) {
+		t.Errorf("ResponseMetadata.Time() = %v, want %v", got, want)
+	}
+
+	pending := Transaction{Type: TransactionTypePending}
+	if got := pending.Time(); !got.IsZero() {
+		t.Errorf("pending Transaction.Time() = %v, want zero time", got)
+	}
+}
+
+func TestTransactionFeeStatement(t *testing.T) {
+	var txn Transaction
+	err := json.Unmarshal([]byte(`{
+		"type": "user_transaction",
+		"events": [
+			{"type": "0x1::fungible_asset::Withdraw", "data": {"amount": "1"}},
+			{"type": "0x1::transaction_fee::FeeStatement", "data": {
+				"total_charge_gas_units": "1500",
+				"execution_gas_units": "7",
+				"io_gas_units": "3",
+				"storage_fee_octas": "149000",
+				"storage_fee_refund_octas": "200"
+			}}
+		]
+	}`), &txn)
+	if err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	fs, ok := txn.FeeStatement()
+	if !ok {
+		t.Fatal("FeeStatement not found")
+	}
+	want := FeeStatement{
+		TotalChargeGasUnits:   1500,
+		ExecutionGasUnits:     7,
+		IOGasUnits:            3,
+		StorageFeeOctas:       149000,
+		StorageFeeRefundOctas: 200,
+	}
+	if *fs != want {
+		t.Errorf("FeeStatement = %+v, want %+v", *fs, want)
+	}
+	if got := fs.GasFeeOctas(100); got != 150000 {
+		t.Errorf("GasFeeOctas(100) = %d, want 150000", got)
+	}
+	if got := fs.NetFeeOctas(100); got != 149800 {
+		t.Errorf("NetFeeOctas(100) = %d, want 149800", got)
+	}
+
+	decoded, ok, err := txn.Events[1].Decode()
+	if err != nil || !ok {
+		t.Fatalf("Decode() = %v, %v", ok, err)
+	}
+	if got, isFee := decoded.(*FeeStatement); !isFee || *got != want {
+		t.Errorf("Decode() = %#v, want *FeeStatement %+v", decoded, want)
+	}
+
+	if _, ok := (&Transaction{Type: TransactionTypePending}).FeeStatement(); ok {
+		t.Error("FeeStatement found on a transaction without events")
+	}
+}
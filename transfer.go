@@ -0,0 +1,86 @@
+package aptos
+
+import (
+	"fmt"
+)
+
+// TransferDetails is the recipient, amount and asset of a transfer decoded
+// by DecodeTransfer.
+type TransferDetails struct {
+	Recipient AccountAddress
+	Amount    uint64
+
+	// AssetType is the coin type (e.g. "0x1::aptos_coin::AptosCoin") for
+	// coin transfers, or the address of the metadata object for fungible
+	// asset transfers, in the forms accepted by GetAccountBalance.
+	AssetType string
+}
+
+// DecodeTransfer extracts the transfer details from a payload that calls one
+// of the known transfer functions:
+//   - 0x1::aptos_account::transfer(to, amount), which moves APT
+//   - 0x1::aptos_account::transfer_coins<CoinType>(to, amount)
+//   - 0x1::coin::transfer<CoinType>(to, amount)
+//   - 0x1::aptos_account::transfer_fungible_assets(metadata, to, amount)
+//   - 0x1::primary_fungible_store::transfer<T>(metadata, to, amount)
+//
+// Entry functions wrapped in an orderless payload are unwrapped first. It
+// reports false if the payload is not one of these functions, and returns an
+// error if it is but the arguments cannot be decoded.
+func DecodeTransfer(payload TransactionPayload) (TransferDetails, bool, error) {
+	ef := payloadEntryFunction(payload)
+	if ef == nil || ef.Module.Address != AccountOne {
+		return TransferDetails{}, false, nil
+	}
+
+	var assetType string
+	fungible := false
+	switch ef.Module.Name + "::" + ef.Function {
+	case "aptos_account::transfer":
+		assetType = AptosCoinType
+	case "aptos_account::transfer_coins", "coin::transfer":
+		if len(ef.TypeArgs) != 1 {
+			return TransferDetails{}, true, fmt.Errorf("%s::%s: got %d type arguments, want 1", ef.Module.Name, ef.Function, len(ef.TypeArgs))
+		}
+		assetType = ef.TypeArgs[0].String()
+	case "aptos_account::transfer_fungible_assets", "primary_fungible_store::transfer":
+		fungible = true
+	default:
+		return TransferDetails{}, false, nil
+	}
+
+	paramTypes := []TypeTag{{Value: &AddressTag{}}, {Value: &U64Tag{}}}
+	if fungible {
+		paramTypes = append([]TypeTag{{Value: &AddressTag{}}}, paramTypes...)
+	}
+	values, err := DecodeEntryFunctionArgs(ef.Args, paramTypes)
+	if err != nil {
+		return TransferDetails{}, true, fmt.Errorf("%s::%s: %w", ef.Module.Name, ef.Function, err)
+	}
+	if fungible {
+		assetType = values[0].(AccountAddress).String()
+		values = values[1:]
+	}
+
+	return TransferDetails{
+		Recipient: values[0].(AccountAddress),
+		Amount:    values[1].(uint64),
+		AssetType: assetType,
+	}, true, nil
+}
+
+// payloadEntryFunction returns the entry function a payload calls directly or
+// through an orderless payload, or nil.
+func payloadEntryFunction(payload TransactionPayload) *EntryFunction {
+	switch p := payload.Payload.(type) {
+	case *EntryFunction:
+		return p
+	case EntryFunction:
+		return &p
+	case *TransactionInnerPayloadV1:
+		return p.Executable.EntryFunc
+	case TransactionInnerPayloadV1:
+		return p.Executable.EntryFunc
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package aptos
+
+import "testing"
+
+func TestDecodeTransfer(t *testing.T) {
+	recipient := MustParseAccountAddress("0x123")
+	usdc := MustParseAccountAddress("0xbae")
+	coinPayload, err := TransferCoinPayload("0x1::aptos_coin::AptosCoin", recipient, 500)
+	if err != nil {
+		t.Fatalf("TransferCoinPayload error: %v", err)
+	}
+	nonce := uint64(7)
+
+	entry := func(module, function string, typeArgs []TypeTag, args ...EntryFunctionArg) TransactionPayload {
+		return TransactionPayload{Payload: &EntryFunction{
+			Module:   ModuleId{Address: AccountOne, Name: module},
+			Function: function,
+			TypeArgs: typeArgs,
+			Args:     EntryFunctionArgs(args...),
+		}}
+	}
+	fungibleTransfer := entry("primary_fungible_store", "transfer", mustParseTypeTags(t, "0x1::fungible_asset::Metadata"),
+		AddressArg(usdc), AddressArg(recipient), U64Arg(42))
+
+	tests := []struct {
+		name    string
+		payload TransactionPayload
+		want    TransferDetails
+		ok      bool
+		wantErr bool
+	}{
+		{
+			name:    "aptos_account::transfer",
+			payload: entry("aptos_account", "transfer", nil, AddressArg(recipient), U64Arg(100)),
+			want:    TransferDetails{Recipient: recipient, Amount: 100, AssetType: AptosCoinType},
+			ok:      true,
+		},
+		{
+			name:    "aptos_account::transfer_coins",
+			payload: coinPayload,
+			want:    TransferDetails{Recipient: recipient, Amount: 500, AssetType: AptosCoinType},
+			ok:      true,
+		},
+		{
+			name:    "coin::transfer",
+			payload: entry("coin", "transfer", mustParseTypeTags(t, "0xbae::usdc::USDC"), AddressArg(recipient), U64Arg(3)),
+			want:    TransferDetails{Recipient: recipient, Amount: 3, AssetType: "0xbae::usdc::USDC"},
+			ok:      true,
+		},
+		{
+			name:    "primary_fungible_store::transfer",
+			payload: fungibleTransfer,
+			want:    TransferDetails{Recipient: recipient, Amount: 42, AssetType: usdc.String()},
+			ok:      true,
+		},
+		{
+			name:    "aptos_account::transfer_fungible_assets",
+			payload: entry("aptos_account", "transfer_fungible_assets", nil, AddressArg(usdc), AddressArg(recipient), U64Arg(9)),
+			want:    TransferDetails{Recipient: recipient, Amount: 9, AssetType: usdc.String()},
+			ok:      true,
+		},
+		{
+			name: "orderless",
+			payload: TransactionPayload{Payload: &TransactionInnerPayloadV1{
+				Executable: TransactionExecutable{
+					Variant:   TransactionExecutableEntryFunction,
+					EntryFunc: fungibleTransfer.Payload.(*EntryFunction),
+				},
+				ExtraConfig: TransactionExtraConfigV1{ReplayProtectionNonce: &nonce},
+			}},
+			want: TransferDetails{Recipient: recipient, Amount: 42, AssetType: usdc.String()},
+			ok:   true,
+		},
+		{
+			name:    "other function",
+			payload: entry("aptos_account", "create_account", nil, AddressArg(recipient)),
+		},
+		{
+			name: "other module address",
+			payload: TransactionPayload{Payload: &EntryFunction{
+				Module:   ModuleId{Address: usdc, Name: "coin"},
+				Function: "transfer",
+				Args:     EntryFunctionArgs(AddressArg(recipient), U64Arg(1)),
+			}},
+		},
+		{
+			name:    "script",
+			payload: TransactionPayload{Payload: &Script{}},
+		},
+		{
+			name:    "malformed amount",
+			payload: entry("aptos_account", "transfer", nil, AddressArg(recipient), U8Arg(1)),
+			ok:      true,
+			wantErr: true,
+		},
+		{
+			name:    "missing type argument",
+			payload: entry("coin", "transfer", nil, AddressArg(recipient), U64Arg(1)),
+			ok:      true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := DecodeTransfer(tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeTransfer error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.ok {
+				t.Errorf("ok = %v, want %v", ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package aptos
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/0xbe1/aptopher/internal/hex"
+)
+
+// MarshalJSON implements json.Marshaler.
+// Arguments are encoded with CanonicalViewArguments, and nil type arguments
+// or arguments are encoded as empty arrays.
+func (r ViewRequest) MarshalJSON() ([]byte, error) {
+	args, err := CanonicalViewArguments(r.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	typeArgs := r.TypeArguments
+	if typeArgs == nil {
+		typeArgs = []string{}
+	}
+	return json.Marshal(struct {
+		Function      string            `json:"function"`
+		TypeArguments []string          `json:"type_arguments"`
+		Arguments     []json.RawMessage `json:"arguments"`
+	}{
+		Function:      r.Function,
+		TypeArguments: typeArgs,
+		Arguments:     args,
+	})
+}
+
+// CanonicalViewArguments encodes view function arguments in the JSON form the
+// node expects, independent of the Go types used to construct them:
+//   - AccountAddress: 0x-prefixed hex string
+//   - uint8, uint16, uint32: JSON number
+//   - uint64, uint, other integers, U128, U256, *big.Int: decimal string
+//   - bool: JSON bool
+//   - []byte, [N]byte, and other byte slices and arrays: 0x-prefixed hex
+//     string (vector<u8>)
+//   - other slices and arrays: JSON array of canonical elements
+//   - json.RawMessage: passed through unchanged
+//
+// Any other value (such as a string) is encoded with encoding/json.
+func CanonicalViewArguments(args []interface{}) ([]json.RawMessage, error) {
+	encoded := make([]json.RawMessage, len(args))
+	for i, arg := range args {
+		raw, err := canonicalViewArgument(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		encoded[i] = raw
+	}
+	return encoded, nil
+}
+
+func canonicalViewArgument(arg interface{}) (json.RawMessage, error) {
+	switch v := arg.(type) {
+	case nil:
+		return nil, fmt.Errorf("nil argument")
+	case json.RawMessage:
+		return v, nil
+	case AccountAddress:
+		return json.Marshal(v.String())
+	case *AccountAddress:
+		return json.Marshal(v.String())
+	case bool:
+		return json.Marshal(v)
+	case uint8, uint16, uint32:
+		return json.Marshal(v)
+	case U128:
+		return json.Marshal(v.String())
+	case U256:
+		return json.Marshal(v.String())
+	case *big.Int:
+		if v.Sign() < 0 {
+			return nil, fmt.Errorf("negative integer %s", v)
+		}
+		return json.Marshal(v.String())
+	case []byte:
+		return json.Marshal(hex.Encode(v))
+	}
+
+	rv := reflect.ValueOf(arg)
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return json.Marshal(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() < 0 {
+			return nil, fmt.Errorf("negative integer %d", rv.Int())
+		}
+		return json.Marshal(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// Copy, since Bytes fails on an array that is not addressable
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return json.Marshal(hex.Encode(b))
+		}
+		elems := make([]json.RawMessage, rv.Len())
+		for i := range elems {
+			elem, err := canonicalViewArgument(rv.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			elems[i] = elem
+		}
+		return json.Marshal(elems)
+	case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
+		reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return nil, fmt.Errorf("unsupported argument type %T", arg)
+	}
+	return json.Marshal(arg)
+}
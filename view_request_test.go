@@ -0,0 +1,117 @@
+package aptos
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestCanonicalViewArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  interface{}
+		want string
+	}{
+		{"address", AccountOne, `"0x0000000000000000000000000000000000000000000000000000000000000001"`},
+		{"address pointer", &AccountOne, `"0x0000000000000000000000000000000000000000000000000000000000000001"`},
+		{"string", "0x1", `"0x1"`},
+		{"bool", true, `true`},
+		{"u8", uint8(7), `7`},
+		{"u32", uint32(70000), `70000`},
+		{"u64", uint64(18446744073709551615), `"18446744073709551615"`},
+		{"int", 42, `"42"`},
+		{"u128", NewU128(5), `"5"`},
+		{"big int", big.NewInt(9), `"9"`},
+		{"bytes", []byte{0xca, 0xfe}, `"0xcafe"`},
+		{"byte array", [2]byte{0xca, 0xfe}, `"0xcafe"`},
+		{"hash", [32]byte{31: 0x01}, `"0x0000000000000000000000000000000000000000000000000000000000000001"`},
+		{"vector<u64>", []uint64{1, 2}, `["1","2"]`},
+		{"vector<address>", []AccountAddress{AccountZero}, `["0x0000000000000000000000000000000000000000000000000000000000000000"]`},
+		{"vector<vector<u8>>", [][]byte{{1}, {}}, `["0x01","0x"]`},
+		{"raw", json.RawMessage(`{"inner":"0x1"}`), `{"inner":"0x1"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalViewArguments([]interface{}{tt.arg})
+			if err != nil {
+				t.Fatalf("CanonicalViewArguments error: %v", err)
+			}
+			if string(got[0]) != tt.want {
+				t.Errorf("CanonicalViewArguments() = %s, want %s", got[0], tt.want)
+			}
+		})
+	}
+
+	for _, arg := range []interface{}{nil, -1, 1.5, big.NewInt(-1)} {
+		if _, err := CanonicalViewArguments([]interface{}{arg}); err == nil {
+			t.Errorf("CanonicalViewArguments(%v) should have failed", arg)
+		}
+	}
+}
+
+func TestViewRequestMarshalJSON(t *testing.T) {
+	req := ViewRequest{
+		Function:  "0x1::coin::balance",
+		Arguments: []interface{}{AccountOne, uint64(1)},
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("json.Marshal error: %v", err)
+	}
+	want := `{"function":"0x1::coin::balance","type_arguments":[],"arguments":["0x0000000000000000000000000000000000000000000000000000000000000001","1"]}`
+	if string(data) != want {
+		t.Errorf("json.Marshal(ViewRequest) = %s, want %s", data, want)
+	}
+}
+
+// TestViewArgumentsCompatibility pins how each Go type the normalizer
+// handles was encoded before CanonicalViewArguments (plain encoding/json)
+// and how it is encoded now.
+func TestViewArgumentsCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		arg      interface{}
+		old, new string // empty new: now rejected
+	}{
+		{"address", AccountOne, `"0x0000000000000000000000000000000000000000000000000000000000000001"`, `"0x0000000000000000000000000000000000000000000000000000000000000001"`},
+		{"address pointer", &AccountOne, `"0x0000000000000000000000000000000000000000000000000000000000000001"`, `"0x0000000000000000000000000000000000000000000000000000000000000001"`},
+		{"string", "0x1", `"0x1"`, `"0x1"`},
+		{"bool", true, `true`, `true`},
+		{"uint8", uint8(7), `7`, `7`},
+		{"uint16", uint16(7), `7`, `7`},
+		{"uint32", uint32(7), `7`, `7`},
+		{"uint64", uint64(7), `7`, `"7"`},
+		{"uint", uint(7), `7`, `"7"`},
+		{"int", 7, `7`, `"7"`},
+		{"int64", int64(7), `7`, `"7"`},
+		{"negative int", -7, `-7`, ``},
+		{"U128", NewU128(7), `"7"`, `"7"`},
+		{"U256", NewU256(7), `"7"`, `"7"`},
+		{"*big.Int", big.NewInt(7), `7`, `"7"`},
+		{"[]byte", []byte{0xca, 0xfe}, `"yv4="`, `"0xcafe"`},
+		{"[2]byte", [2]byte{0xca, 0xfe}, `[202,254]`, `"0xcafe"`},
+		{"[]uint64", []uint64{1, 2}, `[1,2]`, `["1","2"]`},
+		{"[][]byte", [][]byte{{1}}, `["AQ=="]`, `["0x01"]`},
+		{"json.RawMessage", json.RawMessage(`{"inner":"0x1"}`), `{"inner":"0x1"}`, `{"inner":"0x1"}`},
+		{"float64", 1.5, `1.5`, ``},
+		{"nil", nil, `null`, ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, err := json.Marshal(tt.arg)
+			if err != nil || string(old) != tt.old {
+				t.Errorf("encoding/json = %s, %v; want %s", old, err, tt.old)
+			}
+			got, err := CanonicalViewArguments([]interface{}{tt.arg})
+			if tt.new == "" {
+				if err == nil {
+					t.Errorf("CanonicalViewArguments = %s, want an error", got[0])
+				}
+				return
+			}
+			if err != nil || string(got[0]) != tt.new {
+				t.Errorf("CanonicalViewArguments = %v, %v; want %s", got, err, tt.new)
+			}
+		})
+	}
+}